@@ -0,0 +1,12 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- INDEX UNTUK LISTING TASK (filter, sort & cursor pagination)
+-- ============================
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_created ON tasks(project_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_created ON tasks(assignee_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date);
+
+-- +migrate StatementEnd
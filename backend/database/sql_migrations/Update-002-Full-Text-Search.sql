@@ -0,0 +1,32 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- =======================================
+-- FULL-TEXT SEARCH (Bahasa Indonesia + English)
+-- kolom tsvector di-generate otomatis oleh postgres (butuh PostgreSQL 12+)
+-- =======================================
+
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('indonesian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('indonesian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN(search_vector);
+
+ALTER TABLE comments ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('indonesian', coalesce(content, '')) ||
+    to_tsvector('english', coalesce(content, ''))
+) STORED;
+
+CREATE INDEX idx_comments_search_vector ON comments USING GIN(search_vector);
+
+-- nama file dipecah per kata: "invoice_export-2024.pdf" -> invoice export 2024 pdf
+ALTER TABLE attachments ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', regexp_replace(coalesce(file_name, ''), '[._-]+', ' ', 'g'))
+) STORED;
+
+CREATE INDEX idx_attachments_search_vector ON attachments USING GIN(search_vector);
+
+-- +migrate StatementEnd
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK HISTORY (audit trail per field)
+-- task_id sengaja tanpa foreign key supaya riwayat task yang dihapus tetap ada
+-- ============================
+
+CREATE TABLE task_history (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id         UUID NOT NULL,
+    project_id      UUID NOT NULL,
+    task_title      VARCHAR(200) NOT NULL,
+    actor_id        UUID REFERENCES users(id) ON DELETE SET NULL,
+    action          VARCHAR(20) NOT NULL,
+    changes         JSONB NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_history_task_id ON task_history(task_id, created_at);
+CREATE INDEX idx_task_history_project_id ON task_history(project_id);
+CREATE INDEX idx_task_history_created_at ON task_history(created_at DESC);
+
+-- task yang sudah ada dicatat sebagai "created" supaya activity feed tidak kosong
+INSERT INTO task_history (task_id, project_id, task_title, action, created_at)
+SELECT id, project_id, title, 'created', created_at FROM tasks;
+
+-- +migrate StatementEnd
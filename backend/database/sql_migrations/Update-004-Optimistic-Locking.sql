@@ -0,0 +1,12 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- VERSION UNTUK OPTIMISTIC CONCURRENCY (ETag / If-Match)
+-- ============================
+
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE projects ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE comments ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +migrate StatementEnd
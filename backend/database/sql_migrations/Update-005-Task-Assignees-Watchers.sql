@@ -0,0 +1,37 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK ASSIGNEES (Many-to-Many)
+-- tasks.assignee_id tetap diisi dengan assignee utama (is_primary)
+-- ============================
+
+CREATE TABLE task_assignees (
+    task_id         UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_primary      BOOLEAN NOT NULL DEFAULT false,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_assignees_user_id ON task_assignees(user_id);
+CREATE UNIQUE INDEX idx_task_assignees_primary ON task_assignees(task_id) WHERE is_primary;
+
+INSERT INTO task_assignees (task_id, user_id, is_primary, created_at)
+SELECT id, assignee_id, true, created_at FROM tasks WHERE assignee_id IS NOT NULL;
+
+
+-- ============================
+-- TASK WATCHERS (Many-to-Many)
+-- ============================
+
+CREATE TABLE task_watchers (
+    task_id         UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watchers_user_id ON task_watchers(user_id);
+
+-- +migrate StatementEnd
@@ -0,0 +1,37 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK RECURRENCES
+-- satu baris per series, kolom title/description/assignee_id adalah template occurrence berikutnya
+-- ============================
+
+CREATE TABLE task_recurrences (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id          UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    rrule               VARCHAR(255) NOT NULL,
+    dtstart             DATE NOT NULL,
+    title               VARCHAR(200) NOT NULL,
+    description         TEXT,
+    assignee_id         UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_occurrence     DATE NOT NULL,
+    next_occurrence     DATE,
+    occurrence_count    INTEGER NOT NULL DEFAULT 1,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_recurrences_project_id ON task_recurrences(project_id);
+CREATE INDEX idx_task_recurrences_next_occurrence ON task_recurrences(next_occurrence) WHERE next_occurrence IS NOT NULL;
+
+-- occurrence_date adalah slot asli dari rrule, due_date boleh digeser per occurrence
+-- recurrence_exception = true berarti occurrence sudah diedit sendiri dan tidak ikut perubahan series
+ALTER TABLE tasks
+    ADD COLUMN recurrence_id UUID REFERENCES task_recurrences(id) ON DELETE SET NULL,
+    ADD COLUMN occurrence_date DATE,
+    ADD COLUMN recurrence_exception BOOLEAN NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX idx_tasks_recurrence_occurrence ON tasks(recurrence_id, occurrence_date) WHERE recurrence_id IS NOT NULL;
+
+-- +migrate StatementEnd
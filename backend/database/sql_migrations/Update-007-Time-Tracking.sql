@@ -0,0 +1,32 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+ALTER TABLE tasks
+    ADD COLUMN original_estimate_minutes INTEGER CHECK (original_estimate_minutes >= 0);
+
+-- ============================
+-- TIME LOGS
+-- entri manual: started_at dan ended_at kosong, duration_minutes diisi user
+-- timer: started_at terisi, ended_at kosong selama timer berjalan
+-- ============================
+
+CREATE TABLE time_logs (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id             UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id             UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    log_date            DATE NOT NULL DEFAULT CURRENT_DATE,
+    duration_minutes    INTEGER NOT NULL DEFAULT 0 CHECK (duration_minutes >= 0),
+    note                TEXT,
+    started_at          TIMESTAMP WITH TIME ZONE,
+    ended_at            TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_time_logs_task_id ON time_logs(task_id);
+CREATE INDEX idx_time_logs_user_id_log_date ON time_logs(user_id, log_date);
+
+-- satu user hanya boleh punya satu timer yang berjalan
+CREATE UNIQUE INDEX idx_time_logs_running_timer ON time_logs(user_id) WHERE started_at IS NOT NULL AND ended_at IS NULL;
+
+-- +migrate StatementEnd
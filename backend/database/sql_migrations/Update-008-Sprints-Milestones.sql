@@ -0,0 +1,55 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+CREATE TYPE sprint_status AS ENUM ('planned', 'active', 'closed');
+
+-- ============================
+-- SPRINTS
+-- ============================
+
+CREATE TABLE sprints (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id          UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name                VARCHAR(100) NOT NULL,
+    goal                TEXT,
+    start_date          DATE NOT NULL,
+    end_date            DATE NOT NULL,
+    status              sprint_status NOT NULL DEFAULT 'planned',
+    started_at          TIMESTAMP WITH TIME ZONE,
+    closed_at           TIMESTAMP WITH TIME ZONE,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_sprints_project_id ON sprints(project_id, start_date);
+
+-- hanya satu sprint aktif per project
+CREATE UNIQUE INDEX idx_sprints_active_project ON sprints(project_id) WHERE status = 'active';
+
+-- ============================
+-- MILESTONES
+-- ============================
+
+CREATE TABLE milestones (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id          UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name                VARCHAR(100) NOT NULL,
+    description         TEXT,
+    target_date         DATE NOT NULL,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_milestones_project_id ON milestones(project_id, target_date);
+
+ALTER TABLE tasks
+    ADD COLUMN sprint_id UUID REFERENCES sprints(id) ON DELETE SET NULL,
+    ADD COLUMN milestone_id UUID REFERENCES milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_sprint_id ON tasks(sprint_id) WHERE sprint_id IS NOT NULL;
+CREATE INDEX idx_tasks_milestone_id ON tasks(milestone_id) WHERE milestone_id IS NOT NULL;
+
+-- +migrate StatementEnd
@@ -0,0 +1,33 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK POSITION (urutan board)
+-- key fractional indexing base62, dibandingkan per byte sehingga wajib COLLATE "C".
+-- urutan berlaku untuk seluruh project, board cukup mengelompokkan per status
+-- ============================
+
+ALTER TABLE tasks
+    ADD COLUMN position TEXT COLLATE "C";
+
+-- task lama diurutkan sesuai created_at: 'c' + 3 digit base62 (cukup untuk 238.328 task per project)
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY created_at, id) - 1 AS n
+    FROM tasks
+)
+UPDATE tasks t
+SET position = 'c'
+    || substr(d.alphabet, ((r.n / 3844) % 62)::int + 1, 1)
+    || substr(d.alphabet, ((r.n / 62) % 62)::int + 1, 1)
+    || substr(d.alphabet, (r.n % 62)::int + 1, 1)
+FROM ranked r,
+     (SELECT '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz'::text AS alphabet) d
+WHERE t.id = r.id;
+
+ALTER TABLE tasks
+    ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX idx_tasks_project_position ON tasks(project_id, position);
+CREATE INDEX idx_tasks_project_status_position ON tasks(project_id, status, position);
+
+-- +migrate StatementEnd
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK CHECKLIST ITEMS
+-- position memakai key fractional indexing yang sama dengan tasks.position
+-- ============================
+
+CREATE TABLE task_checklist_items (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id             UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    content             VARCHAR(500) NOT NULL,
+    position            TEXT COLLATE "C" NOT NULL,
+    is_checked          BOOLEAN NOT NULL DEFAULT false,
+    checked_at          TIMESTAMP WITH TIME ZONE,
+    checked_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    assignee_id         UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_checklist_items_task_position ON task_checklist_items(task_id, position);
+CREATE INDEX idx_task_checklist_items_assignee_id ON task_checklist_items(assignee_id) WHERE assignee_id IS NOT NULL;
+
+-- +migrate StatementEnd
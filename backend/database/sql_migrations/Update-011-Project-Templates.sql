@@ -0,0 +1,32 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- PROJECT TEMPLATES
+-- ============================
+
+CREATE TABLE project_templates (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                VARCHAR(150) NOT NULL,
+    description         TEXT,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- due_offset_days dihitung dari tanggal mulai project saat template dipakai,
+-- assignee_placeholder dipetakan ke user saat instantiate
+CREATE TABLE template_tasks (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id                 UUID NOT NULL REFERENCES project_templates(id) ON DELETE CASCADE,
+    title                       VARCHAR(200) NOT NULL,
+    description                 TEXT,
+    due_offset_days             INTEGER CHECK (due_offset_days >= 0),
+    assignee_placeholder        VARCHAR(50),
+    original_estimate_minutes   INTEGER CHECK (original_estimate_minutes >= 0),
+    sort_order                  INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_template_tasks_template_id ON template_tasks(template_id, sort_order);
+
+-- +migrate StatementEnd
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TRASH (soft delete)
+-- baris dengan deleted_at terisi ada di trash dan dihapus permanen setelah masa retensi.
+-- task milik project yang dihapus mendapat deleted_at yang sama dengan project-nya
+-- ============================
+
+ALTER TABLE projects
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE tasks
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE comments
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_projects_deleted_at ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_tasks_deleted_at ON tasks(project_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_comments_deleted_at ON comments(task_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +migrate StatementEnd
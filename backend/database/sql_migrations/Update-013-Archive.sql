@@ -0,0 +1,21 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- ARCHIVE
+-- project dan task dengan archived_at terisi hanya bisa dibaca dan tidak ikut listing default.
+-- task di project yang diarsipkan ikut read-only tanpa mengubah archived_at task-nya
+-- ============================
+
+ALTER TABLE projects
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE tasks
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_projects_archived_at ON projects(archived_at) WHERE archived_at IS NOT NULL;
+CREATE INDEX idx_tasks_archived_at ON tasks(project_id, archived_at) WHERE archived_at IS NOT NULL;
+
+-- +migrate StatementEnd
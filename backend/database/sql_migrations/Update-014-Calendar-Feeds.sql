@@ -0,0 +1,22 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- CALENDAR FEEDS
+-- token feed iCalendar hanya disimpan sebagai hash SHA-256; project_id kosong berarti feed task milik user sendiri.
+-- membuat token baru untuk feed yang sama mengganti token lama
+-- ============================
+
+CREATE TABLE calendar_tokens (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id      UUID REFERENCES projects(id) ON DELETE CASCADE,
+    token_hash      VARCHAR(64) NOT NULL UNIQUE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_calendar_tokens_user_feed ON calendar_tokens(user_id) WHERE project_id IS NULL;
+CREATE UNIQUE INDEX idx_calendar_tokens_project_feed ON calendar_tokens(user_id, project_id) WHERE project_id IS NOT NULL;
+
+-- +migrate StatementEnd
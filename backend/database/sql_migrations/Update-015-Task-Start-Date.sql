@@ -0,0 +1,15 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK START DATE
+-- tanggal mulai opsional untuk timeline/gantt. start_date setelah due_date tidak ditolak,
+-- tetapi ditandai sebagai konflik di endpoint timeline
+-- ============================
+
+ALTER TABLE tasks
+    ADD COLUMN start_date DATE;
+
+CREATE INDEX idx_tasks_start_date ON tasks(project_id, start_date) WHERE start_date IS NOT NULL;
+
+-- +migrate StatementEnd
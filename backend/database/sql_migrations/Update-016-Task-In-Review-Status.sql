@@ -0,0 +1,8 @@
+-- +migrate Up notransaction
+
+-- ============================
+-- TASK IN-REVIEW STATUS
+-- ADD VALUE tidak bisa dipakai di transaksi yang sama dengan penambahannya, jadi dipisah dari tabel task_reviews
+-- ============================
+
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'in-review' BEFORE 'done';
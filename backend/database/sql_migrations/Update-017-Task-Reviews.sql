@@ -0,0 +1,27 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK REVIEWS
+-- assignee mengajukan review, manager project menyetujui (task menjadi done) atau menolak (task kembali in-progress).
+-- satu task hanya boleh punya satu review pending, yaitu selama status task in-review
+-- ============================
+
+CREATE TYPE review_decision AS ENUM ('pending', 'approved', 'rejected');
+
+CREATE TABLE task_reviews (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id         UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    submitted_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+    note            TEXT,
+    decision        review_decision NOT NULL DEFAULT 'pending',
+    reviewer_id     UUID REFERENCES users(id) ON DELETE SET NULL,
+    comment         TEXT,
+    submitted_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    reviewed_at     TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_task_reviews_pending ON task_reviews(task_id) WHERE decision = 'pending';
+CREATE INDEX idx_task_reviews_task ON task_reviews(task_id, submitted_at DESC);
+
+-- +migrate StatementEnd
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- TASK REMINDERS
+-- log reminder due date yang sudah dikirim scheduler. Baris dicatat dalam transaksi yang sama dengan pengiriman,
+-- unique key membuat setiap reminder hanya terkirim sekali walaupun server restart atau jalan di beberapa instance.
+-- due_date ikut di key, jadi task yang due date-nya diubah mendapat reminder lagi untuk tanggal barunya
+-- ============================
+
+CREATE TABLE task_reminders (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id     UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind        VARCHAR(30) NOT NULL,
+    due_date    DATE NOT NULL,
+    sent_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_task_reminders_unique ON task_reminders(task_id, user_id, kind, due_date);
+
+-- +migrate StatementEnd
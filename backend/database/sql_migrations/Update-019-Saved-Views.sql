@@ -0,0 +1,43 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- SAVED VIEWS
+-- filter dan sort listing task yang disimpan dengan nama. View private hanya terlihat oleh pemiliknya,
+-- view shared terlihat oleh manager dan member project-nya. Scope menentukan task mana yang difilter:
+-- satu project, semua project user, atau task yang di-assign ke user
+-- ============================
+
+CREATE TYPE saved_view_scope AS ENUM ('project', 'my_projects', 'my_tasks');
+CREATE TYPE saved_view_visibility AS ENUM ('private', 'shared');
+
+CREATE TABLE saved_views (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id      UUID REFERENCES projects(id) ON DELETE CASCADE,
+    name            VARCHAR(100) NOT NULL,
+    scope           saved_view_scope NOT NULL,
+    visibility      saved_view_visibility NOT NULL DEFAULT 'private',
+    filters         JSONB NOT NULL DEFAULT '{}',
+    sort            VARCHAR(50) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK ((scope = 'project') = (project_id IS NOT NULL)),
+    CHECK (visibility = 'private' OR scope = 'project')
+);
+
+CREATE INDEX idx_saved_views_owner ON saved_views(owner_id);
+CREATE INDEX idx_saved_views_shared ON saved_views(project_id) WHERE visibility = 'shared';
+
+-- satu user hanya punya satu view default per dashboard
+CREATE TABLE saved_view_pins (
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dashboard       VARCHAR(20) NOT NULL CHECK (dashboard IN ('manager', 'staff')),
+    view_id         UUID NOT NULL REFERENCES saved_views(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, dashboard)
+);
+
+CREATE INDEX idx_saved_view_pins_view ON saved_view_pins(view_id);
+
+-- +migrate StatementEnd
@@ -0,0 +1,45 @@
+-- +migrate Up
+-- +migrate StatementBegin
+
+-- ============================
+-- AUTOMATION RULES
+-- aturan "when X then Y" per project. conditions dan actions disimpan sebagai JSONB, lihat package automationmodel.
+-- setiap rule yang cocok dengan event dicatat di automation_executions beserta hasil per action untuk debugging.
+-- dedupe_key dipakai trigger due_date_passed supaya satu task hanya memicu rule sekali untuk satu due date
+-- ============================
+
+CREATE TYPE automation_trigger AS ENUM ('status_changed', 'task_created', 'due_date_passed', 'comment_added');
+CREATE TYPE automation_execution_status AS ENUM ('running', 'success', 'failed');
+
+CREATE TABLE automation_rules (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id      UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name            VARCHAR(100) NOT NULL,
+    trigger         automation_trigger NOT NULL,
+    conditions      JSONB NOT NULL DEFAULT '{}',
+    actions         JSONB NOT NULL DEFAULT '[]',
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_automation_rules_project ON automation_rules(project_id, trigger) WHERE enabled;
+
+CREATE TABLE automation_executions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id         UUID NOT NULL REFERENCES automation_rules(id) ON DELETE CASCADE,
+    task_id         UUID REFERENCES tasks(id) ON DELETE SET NULL,
+    trigger         automation_trigger NOT NULL,
+    status          automation_execution_status NOT NULL DEFAULT 'running',
+    results         JSONB NOT NULL DEFAULT '[]',
+    error           TEXT,
+    dedupe_key      VARCHAR(100),
+    started_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at     TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_automation_executions_rule ON automation_executions(rule_id, started_at DESC);
+CREATE UNIQUE INDEX idx_automation_executions_dedupe ON automation_executions(rule_id, dedupe_key) WHERE dedupe_key IS NOT NULL;
+
+-- +migrate StatementEnd
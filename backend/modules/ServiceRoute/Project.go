@@ -3,6 +3,7 @@ package serviceroute
 import (
 	"database/sql"
 	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
 	projectrepo "gintugas/modules/components/Project/repository"
 	projectservice "gintugas/modules/components/Project/service"
 	"net/http"
@@ -45,10 +46,11 @@ func CreateProjectRouter(db *sql.DB) gin.HandlerFunc {
 
 // GetAllProjektRouter godoc
 // @Summary Get semua projects
-// @Description Mendapatkan daftar semua projects (hanya admin/manager)
+// @Description Mendapatkan daftar semua projects (hanya admin/manager). Project yang diarsipkan tidak ikut kecuali memakai filter archived
 // @Tags projects
 // @Produce json
 // @Security BearerAuth
+// @Param archived query string false "false (default), true untuk hanya project yang diarsipkan, all untuk semuanya"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Router /api/project [get]
@@ -100,6 +102,8 @@ func GetProjectRouter(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
+		concurrency.SetETag(ctx, Project.Version)
+
 		ctx.JSON(http.StatusOK, gin.H{
 			"message": fmt.Sprintf("successfully get Project data"),
 			"Project": Project,
@@ -109,16 +113,18 @@ func GetProjectRouter(db *sql.DB) gin.HandlerFunc {
 
 // UpdateProjectRouter godoc
 // @Summary Update project
-// @Description Update data project (hanya admin/manager)
+// @Description Update data project (hanya admin/manager). Kirim header If-Match berisi ETag dari GET untuk mencegah menimpa perubahan user lain
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag project yang terakhir dibaca"
 // @Param input body map[string]interface{} true "Data project"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /api/project/{id} [put]
 func UpdateProjectRouter(db *sql.DB) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -129,12 +135,53 @@ func UpdateProjectRouter(db *sql.DB) gin.HandlerFunc {
 
 		Project, err := projekSrv.UpdateProjekService(ctx)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
+			ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
 
+		concurrency.SetETag(ctx, Project.Version)
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Data Project Berhasil di Update"),
+			"Project": Project,
+		})
+	}
+}
+
+// PatchProjectRouter godoc
+// @Summary Patch project
+// @Description Update sebagian field project dengan JSON Merge Patch (RFC 7396). Field yang tidak dikirim tidak berubah, null pada deskripsi mengosongkan deskripsi
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag project yang terakhir dibaca"
+// @Param input body map[string]interface{} true "Field project yang diubah (nama, deskripsi)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/project/{id} [patch]
+func PatchProjectRouter(db *sql.DB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			projekRepo = projectrepo.NewRepository(db)
+			projekSrv  = projectservice.NewService(projekRepo)
+		)
+
+		Project, err := projekSrv.PatchProjekService(ctx)
+		if err != nil {
+			ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		concurrency.SetETag(ctx, Project.Version)
+
 		ctx.JSON(http.StatusOK, gin.H{
 			"message": fmt.Sprintf("Data Project Berhasil di Update"),
 			"Project": Project,
@@ -144,7 +191,7 @@ func UpdateProjectRouter(db *sql.DB) gin.HandlerFunc {
 
 // DeleteProjectRouter godoc
 // @Summary Delete project
-// @Description Hapus project (hanya admin/manager)
+// @Description Pindahkan project beserta task-nya ke trash (hanya admin/manager). Restore project ikut mengembalikan task tersebut
 // @Tags projects
 // @Produce json
 // @Security BearerAuth
@@ -173,3 +220,75 @@ func DeleteProjectRouter(db *sql.DB) gin.HandlerFunc {
 		})
 	}
 }
+
+// ArchiveProjectRouter godoc
+// @Summary Arsipkan project
+// @Description Mengarsipkan project (hanya manager project). Project dan seluruh task-nya menjadi read-only dan tidak ikut listing default maupun statistik dashboard
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag project yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/project/{id}/archive [post]
+func ArchiveProjectRouter(db *sql.DB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			projekRepo = projectrepo.NewRepository(db)
+			projekSrv  = projectservice.NewService(projekRepo)
+		)
+
+		Project, err := projekSrv.ArchiveProjekService(ctx)
+		if err != nil {
+			ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		concurrency.SetETag(ctx, Project.Version)
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "Project archived successfully",
+			"Project": Project,
+		})
+	}
+}
+
+// UnarchiveProjectRouter godoc
+// @Summary Batalkan arsip project
+// @Description Mengaktifkan kembali project yang diarsipkan (hanya manager project). Task yang diarsipkan sendiri tetap diarsipkan
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param If-Match header string false "ETag project yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/project/{id}/unarchive [post]
+func UnarchiveProjectRouter(db *sql.DB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			projekRepo = projectrepo.NewRepository(db)
+			projekSrv  = projectservice.NewService(projekRepo)
+		)
+
+		Project, err := projekSrv.UnarchiveProjekService(ctx)
+		if err != nil {
+			ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		concurrency.SetETag(ctx, Project.Version)
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "Project unarchived successfully",
+			"Project": Project,
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package serviceroute
 
 import (
+	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
 	. "gintugas/modules/components/Tasks/service"
 	"net/http"
 
@@ -48,18 +50,30 @@ func (c *TaskHandler) CreateTask(ctx *gin.Context) {
 
 // GetProjectTasks godoc
 // @Summary Get semua tasks dalam project
-// @Description Mendapatkan daftar semua tasks dalam project (hanya admin/manager)
+// @Description Mendapatkan daftar tasks dalam project dengan filter, sort dan cursor pagination (hanya admin/manager)
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
 // @Param project_id path string true "Project ID"
+// @Param status query string false "Filter status, pisahkan dengan koma (todo,in-progress,in-review,done)"
+// @Param assignee_id query string false "Filter assignee"
+// @Param due_from query string false "Due date mulai (YYYY-MM-DD)"
+// @Param due_to query string false "Due date sampai (YYYY-MM-DD)"
+// @Param overdue query bool false "Hanya task overdue (true) atau tidak overdue (false)"
+// @Param sprint_id query string false "Sprint ID, atau none untuk task di backlog"
+// @Param milestone_id query string false "Milestone ID, atau none untuk task tanpa milestone"
+// @Param archived query string false "false (default), true untuk hanya task yang diarsipkan (termasuk lewat project-nya), all untuk semuanya"
+// @Param q query string false "Cari di title dan description"
+// @Param sort query string false "position (urutan board), created_at, updated_at, due_date, title; prefix - untuk descending" default(position)
+// @Param cursor query string false "Cursor dari next_cursor response sebelumnya"
+// @Param limit query int false "Limit (maks 100)" default(50)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Router /api/projects/{project_id}/tasks [get]
 func (c *TaskHandler) GetProjectTasks(ctx *gin.Context) {
-	tasks, err := c.taskService.GetProjectTasks(ctx)
+	page, err := c.taskService.GetProjectTasks(ctx)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -68,8 +82,9 @@ func (c *TaskHandler) GetProjectTasks(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Tasks retrieved successfully",
-		"tasks":   tasks,
+		"message":     "Tasks retrieved successfully",
+		"tasks":       page.Tasks,
+		"next_cursor": page.NextCursor,
 	})
 }
 
@@ -96,6 +111,8 @@ func (c *TaskHandler) GetTaskByID(ctx *gin.Context) {
 		return
 	}
 
+	concurrency.SetETag(ctx, task.Version)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Task retrieved successfully",
 		"task":    task,
@@ -104,38 +121,272 @@ func (c *TaskHandler) GetTaskByID(ctx *gin.Context) {
 
 // UpdateTask godoc
 // @Summary Update task
-// @Description Update data task (hanya admin/manager)
+// @Description Update data task oleh manager project, atau oleh assignee task terbatas pada status (todo, in-progress, in-review; done lewat approval review). Kirim header If-Match berisi ETag dari GET untuk mencegah menimpa perubahan user lain
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param project_id path string true "Project ID"
 // @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
 // @Param input body map[string]interface{} true "Data task"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /api/projects/{project_id}/tasks/{task_id} [put]
 func (c *TaskHandler) UpdateTask(ctx *gin.Context) {
 	task, err := c.taskService.UpdateTask(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	concurrency.SetETag(ctx, task.Version)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Task updated successfully",
 		"task":    task,
 	})
 }
 
+// PatchTask godoc
+// @Summary Patch task
+// @Description Update sebagian field task dengan JSON Merge Patch (RFC 7396). Field yang tidak dikirim tidak berubah, null menghapus description, assignee_id atau due_date. Assignee task hanya boleh mengubah status, sama seperti PUT
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "Field task yang diubah"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id} [patch]
+func (c *TaskHandler) PatchTask(ctx *gin.Context) {
+	task, err := c.taskService.PatchTask(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task updated successfully",
+		"task":    task,
+	})
+}
+
+// MoveTask godoc
+// @Summary Pindahkan task di board
+// @Description Mengubah status dan posisi task sekaligus (drag-and-drop). previous_id adalah task tepat di atas posisi baru, next_id task tepat di bawahnya; tanpa keduanya task dipindah ke paling bawah kolom
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "status, previous_id, next_id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/move [post]
+func (c *TaskHandler) MoveTask(ctx *gin.Context) {
+	task, err := c.taskService.MoveTask(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task moved successfully",
+		"task":    task,
+	})
+}
+
+// MoveTaskToProject godoc
+// @Summary Pindahkan task ke project lain
+// @Description Memindahkan task beserta comment, attachment, checklist dan time log ke project lain (harus manager kedua project). Semua assignee harus member project tujuan; sprint, milestone dan recurrence dilepas, begitu juga watcher dan assignee item checklist yang bukan member project tujuan
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "project_id tujuan"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/move-to-project [post]
+func (c *TaskHandler) MoveTaskToProject(ctx *gin.Context) {
+	task, err := c.taskService.MoveTaskToProject(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task moved successfully",
+		"task":    task,
+	})
+}
+
+// ArchiveTask godoc
+// @Summary Arsipkan task
+// @Description Mengarsipkan task (hanya manager project). Task menjadi read-only dan tidak ikut listing default; task masih bisa di-clone
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/archive [post]
+func (c *TaskHandler) ArchiveTask(ctx *gin.Context) {
+	task, err := c.taskService.ArchiveTask(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task archived successfully",
+		"task":    task,
+	})
+}
+
+// UnarchiveTask godoc
+// @Summary Batalkan arsip task
+// @Description Mengaktifkan kembali task yang diarsipkan (hanya manager project). Task di project yang diarsipkan mengikuti arsip project-nya
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/unarchive [post]
+func (c *TaskHandler) UnarchiveTask(ctx *gin.Context) {
+	task, err := c.taskService.UnarchiveTask(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task unarchived successfully",
+		"task":    task,
+	})
+}
+
+// BulkUpdateTasks godoc
+// @Summary Bulk update atau delete task
+// @Description Menerapkan status, assignee_id atau due_date (action update) atau menghapus (action delete) maksimal 100 task dalam satu transaksi (hanya manager project). Jika ada task yang gagal divalidasi, tidak ada perubahan yang disimpan dan results berisi alasan per task. Notifikasi dikirim sebagai satu email ringkasan per penerima
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param input body map[string]interface{} true "task_ids, action (update/delete) dan changes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/bulk [post]
+func (c *TaskHandler) BulkUpdateTasks(ctx *gin.Context) {
+	result, err := c.taskService.BulkUpdateTasks(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if !result.Applied {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "tidak ada task yang diubah karena sebagian task gagal divalidasi",
+			"results": result.Results,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Tasks updated successfully",
+		"results": result.Results,
+	})
+}
+
+// CloneTask godoc
+// @Summary Clone task
+// @Description Menyalin task sebagai task baru berstatus todo, di project yang sama atau project lain (project_id). include_assignees, include_checklist, include_comments dan include_attachments menentukan data yang ikut disalin
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} false "project_id, title, include_assignees, include_checklist, include_comments, include_attachments"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/clone [post]
+func (c *TaskHandler) CloneTask(ctx *gin.Context) {
+	task, err := c.taskService.CloneTask(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Task cloned successfully",
+		"task":    task,
+	})
+}
+
 // DeleteTask godoc
 // @Summary Delete task
-// @Description Hapus task (hanya admin/manager)
+// @Description Pindahkan task ke trash (hanya admin/manager, task atau project yang diarsipkan ditolak). Task bisa di-restore sampai masa retensi trash habis
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
@@ -146,10 +397,11 @@ func (c *TaskHandler) UpdateTask(ctx *gin.Context) {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /api/projects/{project_id}/tasks/{task_id} [delete]
 func (c *TaskHandler) DeleteTask(ctx *gin.Context) {
 	if err := c.taskService.DeleteTask(ctx); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -162,10 +414,21 @@ func (c *TaskHandler) DeleteTask(ctx *gin.Context) {
 
 // GetMyTasks godoc
 // @Summary Get tasks assigned to current user
-// @Description Mendapatkan daftar tasks yang di-assign ke user yang login
+// @Description Mendapatkan daftar tasks yang di-assign ke user yang login (mendukung filter, sort dan cursor yang sama dengan listing project)
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
+// @Param status query string false "Filter status, pisahkan dengan koma"
+// @Param due_from query string false "Due date mulai (YYYY-MM-DD)"
+// @Param due_to query string false "Due date sampai (YYYY-MM-DD)"
+// @Param overdue query bool false "Hanya task overdue"
+// @Param sprint_id query string false "Sprint ID, atau none untuk task di backlog"
+// @Param milestone_id query string false "Milestone ID, atau none untuk task tanpa milestone"
+// @Param archived query string false "false (default), true untuk hanya task yang diarsipkan (termasuk lewat project-nya), all untuk semuanya"
+// @Param q query string false "Cari di title dan description"
+// @Param sort query string false "created_at, updated_at, due_date, title, position; prefix - untuk descending"
+// @Param cursor query string false "Cursor dari next_cursor response sebelumnya"
+// @Param limit query int false "Limit (maks 100)" default(50)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -182,3 +445,91 @@ func (c *TaskHandler) GetMyTasks(ctx *gin.Context) {
 		})
 	}
 }
+
+// ExportProjectTasks godoc
+// @Summary Export tasks project
+// @Description Mengunduh semua task project yang cocok dengan filter sebagai CSV, XLSX atau JSON (hanya manager project). Berisi assignee, status, due date dan jumlah komentar; file dikirim bertahap
+// @Tags tasks
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param format query string false "csv (default), xlsx atau json"
+// @Param status query string false "Filter status, pisahkan dengan koma (todo,in-progress,in-review,done)"
+// @Param assignee_id query string false "Filter assignee"
+// @Param due_from query string false "Due date mulai (YYYY-MM-DD)"
+// @Param due_to query string false "Due date sampai (YYYY-MM-DD)"
+// @Param overdue query bool false "Hanya task overdue (true) atau tidak overdue (false)"
+// @Param archived query string false "false (default), true atau all"
+// @Param sort query string false "position (urutan board), created_at, updated_at, due_date, title; prefix - untuk descending" default(position)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/export [get]
+func (c *TaskHandler) ExportProjectTasks(ctx *gin.Context) {
+	writeExport(ctx, c.taskService.ExportProjectTasks(ctx))
+}
+
+// ExportMyTasks godoc
+// @Summary Export tasks milik user
+// @Description Mengunduh semua task yang di-assign ke user yang login sebagai CSV, XLSX atau JSON, dengan filter yang sama dengan my-tasks
+// @Tags tasks
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "csv (default), xlsx atau json"
+// @Param status query string false "Filter status, pisahkan dengan koma"
+// @Param due_from query string false "Due date mulai (YYYY-MM-DD)"
+// @Param due_to query string false "Due date sampai (YYYY-MM-DD)"
+// @Param overdue query bool false "Hanya task overdue"
+// @Param archived query string false "false (default), true atau all"
+// @Param sort query string false "created_at, updated_at, due_date, title, position; prefix - untuk descending"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/my-tasks/export [get]
+func (c *TaskHandler) ExportMyTasks(ctx *gin.Context) {
+	writeExport(ctx, c.taskService.ExportMyTasks(ctx))
+}
+
+// writeExport: setelah file mulai dikirim status tidak bisa diubah lagi, error hanya dicatat dan file terpotong
+func writeExport(ctx *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	if ctx.Writer.Written() {
+		fmt.Printf("export terhenti: %v\n", err)
+		return
+	}
+	ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+		"error": err.Error(),
+	})
+}
+
+// GetTaskHistory godoc
+// @Summary Get riwayat perubahan task
+// @Description Mendapatkan riwayat create/update/delete task beserta field yang berubah (nilai lama dan baru), terbaru di atas (hanya admin, manager dan member project). History tetap bisa dibaca setelah task dihapus ke trash
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/history [get]
+func (c *TaskHandler) GetTaskHistory(ctx *gin.Context) {
+	history, err := c.taskService.GetTaskHistory(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task history retrieved successfully",
+		"history": history,
+	})
+}
@@ -34,7 +34,7 @@ func NewAttachmentHandler(attachmentService attachmentservice.AttachmentService)
 func (h *AttachmentHandler) UploadAttachment(ctx *gin.Context) {
 	response, err := h.attachmentService.UploadAttachment(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -88,7 +88,7 @@ func (h *AttachmentHandler) GetTaskAttachments(ctx *gin.Context) {
 // @Router /api/attachments/{attachment_id} [delete]
 func (h *AttachmentHandler) DeleteAttachment(ctx *gin.Context) {
 	if err := h.attachmentService.DeleteAttachment(ctx); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -0,0 +1,152 @@
+package serviceroute
+
+import (
+	automationmodel "gintugas/modules/components/Automation/model"
+	automationservice "gintugas/modules/components/Automation/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AutomationHandler struct {
+	automationService automationservice.AutomationService
+}
+
+func NewAutomationHandler(automationService automationservice.AutomationService) *AutomationHandler {
+	return &AutomationHandler{
+		automationService: automationService,
+	}
+}
+
+func writeAutomationRule(ctx *gin.Context, rule *automationmodel.AutomationRule, err error, status int, message string) {
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(status, gin.H{
+		"message": message,
+		"rule":    rule,
+	})
+}
+
+// GetAutomationRules godoc
+// @Summary Get automation rules
+// @Description Mendapatkan semua automation rule project (hanya manager project)
+// @Tags automations
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/automations [get]
+func (h *AutomationHandler) GetAutomationRules(ctx *gin.Context) {
+	rules, err := h.automationService.GetProjectRules(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Automation rules retrieved successfully",
+		"rules":   rules,
+	})
+}
+
+// CreateAutomationRule godoc
+// @Summary Buat automation rule
+// @Description Membuat rule "when X then Y" (hanya manager project). trigger: status_changed, task_created, due_date_passed, atau comment_added. Semua conditions harus terpenuhi, lalu actions (assign_user, change_status, add_comment, send_notification, call_webhook) dijalankan berurutan di background. Comment dan message boleh memakai {task}, {status}, {project} dan {rule}. url call_webhook tidak boleh mengarah ke jaringan internal
+// @Tags automations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param input body automationmodel.RuleRequest true "Rule"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/automations [post]
+func (h *AutomationHandler) CreateAutomationRule(ctx *gin.Context) {
+	rule, err := h.automationService.CreateRule(ctx)
+	writeAutomationRule(ctx, rule, err, http.StatusCreated, "Automation rule created successfully")
+}
+
+// UpdateAutomationRule godoc
+// @Summary Ubah automation rule
+// @Description Mengganti seluruh isi rule (hanya manager project), kirim enabled false untuk menonaktifkan rule
+// @Tags automations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param rule_id path string true "Rule ID"
+// @Param input body automationmodel.RuleRequest true "Rule"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/automations/{rule_id} [put]
+func (h *AutomationHandler) UpdateAutomationRule(ctx *gin.Context) {
+	rule, err := h.automationService.UpdateRule(ctx)
+	writeAutomationRule(ctx, rule, err, http.StatusOK, "Automation rule updated successfully")
+}
+
+// DeleteAutomationRule godoc
+// @Summary Hapus automation rule
+// @Description Menghapus rule beserta execution log-nya (hanya manager project)
+// @Tags automations
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param rule_id path string true "Rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/automations/{rule_id} [delete]
+func (h *AutomationHandler) DeleteAutomationRule(ctx *gin.Context) {
+	if err := h.automationService.DeleteRule(ctx); err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Automation rule deleted successfully",
+	})
+}
+
+// GetAutomationExecutions godoc
+// @Summary Get execution log rule
+// @Description Mendapatkan riwayat execution rule terbaru lebih dulu, berisi hasil setiap action dan error jika gagal (hanya manager project)
+// @Tags automations
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param rule_id path string true "Rule ID"
+// @Param limit query int false "Limit (maks 200)" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/automations/{rule_id}/executions [get]
+func (h *AutomationHandler) GetAutomationExecutions(ctx *gin.Context) {
+	executions, err := h.automationService.GetRuleExecutions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Automation executions retrieved successfully",
+		"executions": executions,
+	})
+}
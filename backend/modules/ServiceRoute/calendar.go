@@ -0,0 +1,150 @@
+package serviceroute
+
+import (
+	"errors"
+	calendarservice "gintugas/modules/components/Calendar/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	calendarService calendarservice.CalendarService
+}
+
+func NewCalendarHandler(calendarService calendarservice.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+func calendarErrorStatus(err error) int {
+	if errors.Is(err, calendarservice.ErrFeedNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// CreateUserFeed godoc
+// @Summary Buat feed kalender task saya
+// @Description Membuat token rahasia untuk feed iCalendar berisi due date task yang di-assign ke user. Token lama langsung tidak berlaku; token hanya ditampilkan sekali
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/calendar/feed [post]
+func (h *CalendarHandler) CreateUserFeed(ctx *gin.Context) {
+	feed, err := h.calendarService.CreateUserFeed(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Calendar feed created successfully",
+		"feed":    feed,
+	})
+}
+
+// RevokeUserFeed godoc
+// @Summary Cabut feed kalender task saya
+// @Description Menghapus token feed iCalendar user sehingga url feed lama tidak bisa dipakai lagi
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/calendar/feed [delete]
+func (h *CalendarHandler) RevokeUserFeed(ctx *gin.Context) {
+	if err := h.calendarService.RevokeUserFeed(ctx); err != nil {
+		ctx.JSON(calendarErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// CreateProjectFeed godoc
+// @Summary Buat feed kalender project
+// @Description Membuat token rahasia untuk feed iCalendar berisi due date semua task project (hanya manager project). Token lama langsung tidak berlaku; token hanya ditampilkan sekali
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/calendar/feed [post]
+func (h *CalendarHandler) CreateProjectFeed(ctx *gin.Context) {
+	feed, err := h.calendarService.CreateProjectFeed(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Calendar feed created successfully",
+		"feed":    feed,
+	})
+}
+
+// RevokeProjectFeed godoc
+// @Summary Cabut feed kalender project
+// @Description Menghapus token feed iCalendar project milik manager yang login
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/calendar/feed [delete]
+func (h *CalendarHandler) RevokeProjectFeed(ctx *gin.Context) {
+	if err := h.calendarService.RevokeProjectFeed(ctx); err != nil {
+		ctx.JSON(calendarErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// GetFeed godoc
+// @Summary Feed iCalendar
+// @Description Feed iCalendar (RFC 5545) berisi satu VEVENT atau VTODO per task aktif yang punya due date. Tidak memakai login, akses memakai token di url
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Token feed, boleh diakhiri .ics"
+// @Param type query string false "event (default) atau todo"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/calendar/{token} [get]
+func (h *CalendarHandler) GetFeed(ctx *gin.Context) {
+	feed, err := h.calendarService.GetFeed(ctx)
+	if err != nil {
+		ctx.JSON(calendarErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// feed dibuat ulang setiap diminta, jangan disimpan proxy
+	ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	ctx.Header("Content-Disposition", `inline; filename="gintugas.ics"`)
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
@@ -1,6 +1,7 @@
 package serviceroute
 
 import (
+	concurrency "gintugas/modules/components/Concurrency"
 	. "gintugas/modules/components/command/service"
 	"net/http"
 
@@ -34,7 +35,7 @@ func NewCommentsHandler(commentsService CommentsService) *CommentsHandler {
 func (c *CommentsHandler) CreateComments(ctx *gin.Context) {
 	comments, err := c.commentsService.CreateComments(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -96,6 +97,8 @@ func (c *CommentsHandler) GetCommentsByID(ctx *gin.Context) {
 		return
 	}
 
+	concurrency.SetETag(ctx, comments.Version)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":  "Comments retrieved successfully",
 		"comments": comments,
@@ -104,29 +107,33 @@ func (c *CommentsHandler) GetCommentsByID(ctx *gin.Context) {
 
 // UpdateComments godoc
 // @Summary Update komentar
-// @Description Update data komentar (hanya admin/manager/staff)
+// @Description Update data komentar (hanya admin/manager/staff). Kirim header If-Match berisi ETag dari GET untuk mencegah menimpa perubahan lain
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param task_id path string true "Task ID"
 // @Param comments_id path string true "Comments ID"
+// @Param If-Match header string false "ETag komentar yang terakhir dibaca"
 // @Param input body map[string]interface{} true "Data komentar"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /api/tasks/{task_id}/comments/{comments_id} [put]
 func (c *CommentsHandler) UpdateComments(ctx *gin.Context) {
 	comments, err := c.commentsService.UpdateComments(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	concurrency.SetETag(ctx, comments.Version)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":  "Comments updated successfully",
 		"comments": comments,
@@ -135,7 +142,7 @@ func (c *CommentsHandler) UpdateComments(ctx *gin.Context) {
 
 // DeleteComments godoc
 // @Summary Delete komentar
-// @Description Hapus komentar (hanya admin/manager/staff)
+// @Description Pindahkan komentar ke trash (hanya admin/manager/staff). Komentar bisa di-restore sampai masa retensi trash habis
 // @Tags comments
 // @Produce json
 // @Security BearerAuth
@@ -149,7 +156,7 @@ func (c *CommentsHandler) UpdateComments(ctx *gin.Context) {
 // @Router /api/tasks/{task_id}/comments/{comments_id} [delete]
 func (c *CommentsHandler) DeleteComments(ctx *gin.Context) {
 	if err := c.commentsService.DeleteComments(ctx); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
 			"error": err.Error(),
 		})
 		return
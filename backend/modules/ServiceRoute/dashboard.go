@@ -1,8 +1,11 @@
 package serviceroute
 
 import (
+	"errors"
 	"strconv"
+	"time"
 
+	dashboardmodel "gintugas/modules/components/Dashboard/model"
 	dashboardrepository "gintugas/modules/components/Dashboard/repository"
 	dashboardservice "gintugas/modules/components/Dashboard/service"
 	"net/http"
@@ -290,6 +293,94 @@ func (h *DashboardHandler) GetManagerTasks(c *gin.Context) {
 	})
 }
 
+// GetManagerTimeByProject godoc
+// @Summary Get manager time per project
+// @Description Total estimasi, waktu tercatat dan sisa estimasi per project yang dibuat manager. from/to membatasi waktu tercatat, sisa estimasi selalu dihitung dari semua catatan
+// @Tags dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/dashboard/manager/time/projects [get]
+func (h *DashboardHandler) GetManagerTimeByProject(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	managerID, err := parseUUID(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	period, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summaries, err := h.managerService.GetManagerTimeByProject(managerID, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch project time totals: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project time totals retrieved successfully",
+		"data":    summaries,
+	})
+}
+
+// GetManagerTimeByUser godoc
+// @Summary Get manager time per user
+// @Description Total waktu tercatat per user di project yang dibuat manager
+// @Tags dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/dashboard/manager/time/users [get]
+func (h *DashboardHandler) GetManagerTimeByUser(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	managerID, err := parseUUID(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	period, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summaries, err := h.managerService.GetManagerTimeByUser(managerID, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user time totals: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User time totals retrieved successfully",
+		"data":    summaries,
+	})
+}
+
 // GetStaffDashboard godoc
 // @Summary Get staff dashboard
 // @Description Mendapatkan dashboard untuk staff dengan statistik task yang ditugaskan
@@ -389,3 +480,27 @@ func parseUUID(s string) (uuid.UUID, error) {
 func parseInt(s string) (int, error) {
 	return strconv.Atoi(s)
 }
+
+// parseTimeRange membaca query from dan to (YYYY-MM-DD)
+func parseTimeRange(c *gin.Context) (dashboardmodel.TimeRange, error) {
+	var period dashboardmodel.TimeRange
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &period.From}, {"to", &period.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return period, errors.New("Invalid " + bound.name + ", use YYYY-MM-DD")
+		}
+		*bound.target = &date
+	}
+
+	if period.From != nil && period.To != nil && period.To.Before(*period.From) {
+		return period, errors.New("to must not be before from")
+	}
+	return period, nil
+}
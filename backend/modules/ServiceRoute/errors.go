@@ -0,0 +1,20 @@
+package serviceroute
+
+import (
+	"errors"
+	archive "gintugas/modules/components/Archive"
+	concurrency "gintugas/modules/components/Concurrency"
+	"net/http"
+)
+
+// errorStatus memetakan error dari service ke HTTP status, selain error yang dikenal dipakai fallback
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, concurrency.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, archive.ErrReadOnly):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
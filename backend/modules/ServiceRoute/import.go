@@ -0,0 +1,102 @@
+package serviceroute
+
+import (
+	importservice "gintugas/modules/components/Import/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importService importservice.ImportService
+}
+
+func NewImportHandler(importService importservice.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// PreviewImport godoc
+// @Summary Preview import task
+// @Description Membaca file CSV, export JSON board Trello atau export CSV Jira tanpa menyimpan apa pun (hanya manager project). Mengembalikan kolom, status/list dan user yang ditemukan, mapping yang akan dipakai dan 20 task pertama
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param file formData file true "File export"
+// @Param source formData string true "csv, trello atau jira"
+// @Param mapping formData string false "JSON mapping: columns, statuses, default_status, users, add_members"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/import/preview [post]
+func (h *ImportHandler) PreviewImport(ctx *gin.Context) {
+	preview, err := h.importService.PreviewImport(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Import preview generated successfully",
+		"preview": preview,
+	})
+}
+
+// ImportTasks godoc
+// @Summary Import task
+// @Description Membuat task, komentar dan attachment dari file export dalam satu transaksi (hanya manager project). Jika ada baris dengan error tidak ada task yang dibuat. dry_run=true menjalankan validasi yang sama tanpa menyimpan. Attachment diunduh dari url di file, yang gagal diunduh dilewati dengan warning. Maksimal 10 attachment per task dan 100 per import; import dibatalkan jika total attachment lebih dari 200MB atau pengunduhan lebih dari 3 menit
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param file formData file true "File export"
+// @Param source formData string true "csv, trello atau jira"
+// @Param mapping formData string false "JSON mapping: columns, statuses, default_status, users, add_members"
+// @Param dry_run formData bool false "Validasi tanpa menyimpan"
+// @Success 200 {object} map[string]interface{}
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/import [post]
+func (h *ImportHandler) ImportTasks(ctx *gin.Context) {
+	result, err := h.importService.ImportTasks(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if !result.Valid {
+		status := http.StatusBadRequest
+		if result.DryRun {
+			status = http.StatusOK
+		}
+		ctx.JSON(status, gin.H{
+			"error":  "sebagian baris tidak valid, tidak ada task yang dibuat",
+			"result": result,
+		})
+		return
+	}
+
+	if result.DryRun {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "Import validated successfully",
+			"result":  result,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Tasks imported successfully",
+		"result":  result,
+	})
+}
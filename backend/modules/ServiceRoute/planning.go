@@ -0,0 +1,366 @@
+package serviceroute
+
+import (
+	planningservice "gintugas/modules/components/Planning/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlanningHandler struct {
+	planningService planningservice.PlanningService
+}
+
+func NewPlanningHandler(planningService planningservice.PlanningService) *PlanningHandler {
+	return &PlanningHandler{
+		planningService: planningService,
+	}
+}
+
+// CreateSprint godoc
+// @Summary Buat sprint
+// @Description Membuat sprint baru di project (hanya manager project)
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param input body map[string]interface{} true "name, goal, start_date, end_date (YYYY-MM-DD)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/sprints [post]
+func (h *PlanningHandler) CreateSprint(ctx *gin.Context) {
+	sprint, err := h.planningService.CreateSprint(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Sprint created successfully",
+		"sprint":  sprint,
+	})
+}
+
+// GetProjectSprints godoc
+// @Summary Get project sprints
+// @Description Mendapatkan sprint project beserta progress task. Filter status: planned, active, closed
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param status query string false "planned, active atau closed"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/sprints [get]
+func (h *PlanningHandler) GetProjectSprints(ctx *gin.Context) {
+	sprints, err := h.planningService.GetProjectSprints(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprints retrieved successfully",
+		"sprints": sprints,
+	})
+}
+
+// GetSprint godoc
+// @Summary Get sprint
+// @Description Mendapatkan detail sprint beserta progress task
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param sprint_id path string true "Sprint ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/sprints/{sprint_id} [get]
+func (h *PlanningHandler) GetSprint(ctx *gin.Context) {
+	sprint, err := h.planningService.GetSprint(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprint retrieved successfully",
+		"sprint":  sprint,
+	})
+}
+
+// UpdateSprint godoc
+// @Summary Update sprint
+// @Description Mengubah name, goal, start_date atau end_date sprint dengan JSON Merge Patch (hanya manager project)
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sprint_id path string true "Sprint ID"
+// @Param input body map[string]interface{} true "Field sprint yang diubah"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/sprints/{sprint_id} [patch]
+func (h *PlanningHandler) UpdateSprint(ctx *gin.Context) {
+	sprint, err := h.planningService.UpdateSprint(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprint updated successfully",
+		"sprint":  sprint,
+	})
+}
+
+// DeleteSprint godoc
+// @Summary Hapus sprint
+// @Description Menghapus sprint yang tidak sedang berjalan, task di dalamnya kembali ke backlog
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param sprint_id path string true "Sprint ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/sprints/{sprint_id} [delete]
+func (h *PlanningHandler) DeleteSprint(ctx *gin.Context) {
+	if err := h.planningService.DeleteSprint(ctx); err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprint deleted successfully",
+	})
+}
+
+// StartSprint godoc
+// @Summary Mulai sprint
+// @Description Mengaktifkan sprint berstatus planned. Satu project hanya boleh punya satu sprint aktif
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param sprint_id path string true "Sprint ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/sprints/{sprint_id}/start [post]
+func (h *PlanningHandler) StartSprint(ctx *gin.Context) {
+	sprint, err := h.planningService.StartSprint(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprint started successfully",
+		"sprint":  sprint,
+	})
+}
+
+// CloseSprint godoc
+// @Summary Tutup sprint
+// @Description Menutup sprint aktif. Task yang belum done dipindah ke sprint carry_over_to, atau kembali ke backlog jika carry_over_to kosong
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sprint_id path string true "Sprint ID"
+// @Param input body map[string]interface{} false "carry_over_to (opsional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/sprints/{sprint_id}/close [post]
+func (h *PlanningHandler) CloseSprint(ctx *gin.Context) {
+	result, err := h.planningService.CloseSprint(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Sprint closed successfully",
+		"data":    result,
+	})
+}
+
+// CreateMilestone godoc
+// @Summary Buat milestone
+// @Description Membuat milestone dengan target date (hanya manager project)
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param input body map[string]interface{} true "name, description, target_date (YYYY-MM-DD)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/milestones [post]
+func (h *PlanningHandler) CreateMilestone(ctx *gin.Context) {
+	milestone, err := h.planningService.CreateMilestone(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":   "Milestone created successfully",
+		"milestone": milestone,
+	})
+}
+
+// GetProjectMilestones godoc
+// @Summary Get project milestones
+// @Description Mendapatkan milestone project beserta progress task
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/milestones [get]
+func (h *PlanningHandler) GetProjectMilestones(ctx *gin.Context) {
+	milestones, err := h.planningService.GetProjectMilestones(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Milestones retrieved successfully",
+		"milestones": milestones,
+	})
+}
+
+// GetMilestone godoc
+// @Summary Get milestone
+// @Description Mendapatkan detail milestone beserta progress task
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param milestone_id path string true "Milestone ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/milestones/{milestone_id} [get]
+func (h *PlanningHandler) GetMilestone(ctx *gin.Context) {
+	milestone, err := h.planningService.GetMilestone(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":   "Milestone retrieved successfully",
+		"milestone": milestone,
+	})
+}
+
+// UpdateMilestone godoc
+// @Summary Update milestone
+// @Description Mengubah name, description atau target_date milestone dengan JSON Merge Patch (hanya manager project)
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param milestone_id path string true "Milestone ID"
+// @Param input body map[string]interface{} true "Field milestone yang diubah"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/milestones/{milestone_id} [patch]
+func (h *PlanningHandler) UpdateMilestone(ctx *gin.Context) {
+	milestone, err := h.planningService.UpdateMilestone(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":   "Milestone updated successfully",
+		"milestone": milestone,
+	})
+}
+
+// DeleteMilestone godoc
+// @Summary Hapus milestone
+// @Description Menghapus milestone, task di dalamnya tetap ada tanpa milestone
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param milestone_id path string true "Milestone ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/milestones/{milestone_id} [delete]
+func (h *PlanningHandler) DeleteMilestone(ctx *gin.Context) {
+	if err := h.planningService.DeleteMilestone(ctx); err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Milestone deleted successfully",
+	})
+}
+
+// GetProjectTimeline godoc
+// @Summary Get project timeline
+// @Description Mendapatkan task project sebagai batang tanggal per assignee (gantt) beserta konflik jadwal dan critical path
+// @Tags timeline
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param from query string false "Awal rentang (YYYY-MM-DD)"
+// @Param to query string false "Akhir rentang (YYYY-MM-DD)"
+// @Param max_parallel query int false "Batas task bersamaan per assignee sebelum dianggap overbooked"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/timeline [get]
+func (h *PlanningHandler) GetProjectTimeline(ctx *gin.Context) {
+	timeline, err := h.planningService.GetProjectTimeline(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Timeline retrieved successfully",
+		"timeline": timeline,
+	})
+}
@@ -0,0 +1,129 @@
+package serviceroute
+
+import (
+	recurrenceservice "gintugas/modules/components/Recurrence/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurrenceHandler struct {
+	recurrenceService recurrenceservice.RecurrenceService
+}
+
+func NewRecurrenceHandler(recurrenceService recurrenceservice.RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{
+		recurrenceService: recurrenceService,
+	}
+}
+
+// CreateRecurrence godoc
+// @Summary Jadikan task berulang
+// @Description Membuat series recurrence dari task (hanya manager project). due_date task menjadi tanggal mulai. RRULE yang didukung: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (weekly), BYMONTHDAY (monthly), COUNT atau UNTIL
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "rrule, contoh FREQ=WEEKLY;BYDAY=MO"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/recurrence [post]
+func (h *RecurrenceHandler) CreateRecurrence(ctx *gin.Context) {
+	recurrence, err := h.recurrenceService.CreateRecurrence(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":    "Recurrence created successfully",
+		"recurrence": recurrence,
+	})
+}
+
+// GetRecurrence godoc
+// @Summary Get recurrence
+// @Description Mendapatkan detail series recurrence beserta 20 occurrence terakhir (hanya manager project)
+// @Tags recurrences
+// @Produce json
+// @Security BearerAuth
+// @Param recurrence_id path string true "Recurrence ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/recurrences/{recurrence_id} [get]
+func (h *RecurrenceHandler) GetRecurrence(ctx *gin.Context) {
+	recurrence, err := h.recurrenceService.GetRecurrence(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Recurrence retrieved successfully",
+		"recurrence": recurrence,
+	})
+}
+
+// UpdateRecurrence godoc
+// @Summary Update series recurrence
+// @Description Mengubah rrule atau template series dengan JSON Merge Patch (rrule, title, description, assignee_id). Occurrence yang belum selesai ikut diperbarui kecuali yang sudah diedit sendiri; untuk mengubah satu occurrence saja, update task-nya langsung
+// @Tags recurrences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recurrence_id path string true "Recurrence ID"
+// @Param input body map[string]interface{} true "Field series yang diubah"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/recurrences/{recurrence_id} [patch]
+func (h *RecurrenceHandler) UpdateRecurrence(ctx *gin.Context) {
+	recurrence, err := h.recurrenceService.UpdateRecurrence(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Recurrence updated successfully",
+		"recurrence": recurrence,
+	})
+}
+
+// DeleteRecurrence godoc
+// @Summary Hentikan recurrence
+// @Description Menghentikan series (hanya manager project, project yang diarsipkan ditolak). Occurrence yang sudah dibuat tetap ada sebagai task biasa
+// @Tags recurrences
+// @Produce json
+// @Security BearerAuth
+// @Param recurrence_id path string true "Recurrence ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/recurrences/{recurrence_id} [delete]
+func (h *RecurrenceHandler) DeleteRecurrence(ctx *gin.Context) {
+	if err := h.recurrenceService.DeleteRecurrence(ctx); err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Recurrence deleted successfully",
+	})
+}
@@ -0,0 +1,179 @@
+package serviceroute
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func writeSavedView(ctx *gin.Context, view *taskmodel.SavedView, err error, status int, message string) {
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(status, gin.H{
+		"message": message,
+		"view":    view,
+	})
+}
+
+// GetSavedViews godoc
+// @Summary Get saved views
+// @Description Mendapatkan view milik user dan view yang dibagikan di project tempat user menjadi manager atau member. pinned_on berisi dashboard tempat view menjadi default
+// @Tags saved-views
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query string false "Hanya view untuk project ini"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views [get]
+func (c *TaskHandler) GetSavedViews(ctx *gin.Context) {
+	views, err := c.taskService.GetSavedViews(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Saved views retrieved successfully",
+		"views":   views,
+	})
+}
+
+// CreateSavedView godoc
+// @Summary Simpan view
+// @Description Menyimpan filter dan sort listing task dengan nama. scope: project (wajib project_id), my_projects, atau my_tasks. filters memakai nama query parameter listing task, assignee_id "me" berarti user yang membuka view. Hanya view scope project yang bisa shared
+// @Tags saved-views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body taskmodel.SavedViewRequest true "View"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views [post]
+func (c *TaskHandler) CreateSavedView(ctx *gin.Context) {
+	view, err := c.taskService.CreateSavedView(ctx)
+	writeSavedView(ctx, view, err, http.StatusCreated, "Saved view created successfully")
+}
+
+// UpdateSavedView godoc
+// @Summary Ubah saved view
+// @Description Mengganti seluruh isi view, hanya pemilik view. View yang menjadi private dilepas dari dashboard user lain
+// @Tags saved-views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param view_id path string true "View ID"
+// @Param input body taskmodel.SavedViewRequest true "View"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views/{view_id} [put]
+func (c *TaskHandler) UpdateSavedView(ctx *gin.Context) {
+	view, err := c.taskService.UpdateSavedView(ctx)
+	writeSavedView(ctx, view, err, http.StatusOK, "Saved view updated successfully")
+}
+
+// DeleteSavedView godoc
+// @Summary Hapus saved view
+// @Description Menghapus view. Pemilik view, atau manager project untuk view yang dibagikan di project-nya
+// @Tags saved-views
+// @Produce json
+// @Security BearerAuth
+// @Param view_id path string true "View ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views/{view_id} [delete]
+func (c *TaskHandler) DeleteSavedView(ctx *gin.Context) {
+	if err := c.taskService.DeleteSavedView(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Saved view deleted successfully",
+	})
+}
+
+// GetSavedViewTasks godoc
+// @Summary Jalankan saved view
+// @Description Mendapatkan task sesuai filter dan sort view, dengan pagination cursor seperti listing task
+// @Tags saved-views
+// @Produce json
+// @Security BearerAuth
+// @Param view_id path string true "View ID"
+// @Param cursor query string false "Cursor dari next_cursor response sebelumnya"
+// @Param limit query int false "Limit (maks 100)" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views/{view_id}/tasks [get]
+func (c *TaskHandler) GetSavedViewTasks(ctx *gin.Context) {
+	page, err := c.taskService.GetSavedViewTasks(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":     "Tasks retrieved successfully",
+		"tasks":       page.Tasks,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// PinSavedView godoc
+// @Summary Jadikan view default dashboard
+// @Description Memasang view sebagai default di dashboard manager atau staff milik user, menggantikan view default sebelumnya. Dashboard manager hanya untuk admin dan manager
+// @Tags saved-views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param view_id path string true "View ID"
+// @Param input body taskmodel.PinViewRequest true "Dashboard"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views/{view_id}/pin [post]
+func (c *TaskHandler) PinSavedView(ctx *gin.Context) {
+	view, err := c.taskService.PinSavedView(ctx)
+	writeSavedView(ctx, view, err, http.StatusOK, "Saved view pinned successfully")
+}
+
+// UnpinSavedView godoc
+// @Summary Lepas view default dashboard
+// @Description Melepas view dari dashboard user, tanpa query dashboard view dilepas dari semua dashboard
+// @Tags saved-views
+// @Produce json
+// @Security BearerAuth
+// @Param view_id path string true "View ID"
+// @Param dashboard query string false "manager atau staff"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/views/{view_id}/pin [delete]
+func (c *TaskHandler) UnpinSavedView(ctx *gin.Context) {
+	if err := c.taskService.UnpinSavedView(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Saved view unpinned successfully",
+	})
+}
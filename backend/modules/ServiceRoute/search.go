@@ -0,0 +1,48 @@
+package serviceroute
+
+import (
+	searchservice "gintugas/modules/components/Search/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService searchservice.SearchService
+}
+
+func NewSearchHandler(searchService searchservice.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Summary Cari task, komentar dan attachment
+// @Description Full-text search (Bahasa Indonesia & English) pada judul/deskripsi task, isi komentar dan nama file attachment. Hasil diurutkan berdasarkan relevansi dan hanya dari project yang bisa dilihat user. highlight berupa HTML yang sudah di-escape dengan kata yang cocok di dalam <mark>
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Kata kunci (mendukung \"frasa\", OR dan -kata)"
+// @Param types query string false "Jenis data, pisahkan dengan koma (task,comment,attachment)"
+// @Param project_id query string false "Batasi ke satu project"
+// @Param limit query int false "Limit (maks 50)" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/search [get]
+func (h *SearchHandler) Search(ctx *gin.Context) {
+	results, err := h.searchService.Search(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Search results retrieved successfully",
+		"results": results,
+	})
+}
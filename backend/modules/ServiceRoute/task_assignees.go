@@ -0,0 +1,210 @@
+package serviceroute
+
+import (
+	concurrency "gintugas/modules/components/Concurrency"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTaskAssignees godoc
+// @Summary Get assignee task
+// @Description Mendapatkan semua assignee task, assignee utama di urutan pertama (hanya admin, manager dan member project)
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/assignees [get]
+func (c *TaskHandler) GetTaskAssignees(ctx *gin.Context) {
+	assignees, err := c.taskService.GetTaskAssignees(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":   "Task assignees retrieved successfully",
+		"assignees": assignees,
+	})
+}
+
+// AddTaskAssignee godoc
+// @Summary Tambah assignee task
+// @Description Menambahkan member project sebagai assignee task (hanya manager project). is_primary=true menjadikannya assignee utama
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "user_id dan is_primary"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/assignees [post]
+func (c *TaskHandler) AddTaskAssignee(ctx *gin.Context) {
+	task, err := c.taskService.AddTaskAssignee(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Assignee added successfully",
+		"task":    task,
+	})
+}
+
+// RemoveTaskAssignee godoc
+// @Summary Hapus assignee task
+// @Description Melepas user dari task (hanya manager project). Jika user adalah assignee utama, task menjadi tanpa assignee utama
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/assignees/{user_id} [delete]
+func (c *TaskHandler) RemoveTaskAssignee(ctx *gin.Context) {
+	task, err := c.taskService.RemoveTaskAssignee(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Assignee removed successfully",
+		"task":    task,
+	})
+}
+
+// SetPrimaryAssignee godoc
+// @Summary Jadikan assignee utama
+// @Description Menjadikan assignee task sebagai assignee utama (hanya manager project), assignee utama sebelumnya tetap menjadi assignee
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/assignees/{user_id}/primary [put]
+func (c *TaskHandler) SetPrimaryAssignee(ctx *gin.Context) {
+	task, err := c.taskService.SetPrimaryAssignee(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Primary assignee updated successfully",
+		"task":    task,
+	})
+}
+
+// GetTaskWatchers godoc
+// @Summary Get watcher task
+// @Description Mendapatkan user yang mengikuti notifikasi task (hanya admin, manager dan member project)
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/watchers [get]
+func (c *TaskHandler) GetTaskWatchers(ctx *gin.Context) {
+	watchers, err := c.taskService.GetTaskWatchers(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Task watchers retrieved successfully",
+		"watchers": watchers,
+	})
+}
+
+// WatchTask godoc
+// @Summary Ikuti task
+// @Description Menjadi watcher task. Tanpa body, user yang login yang menjadi watcher; menambahkan user lain hanya untuk manager project
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} false "user_id (opsional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/watchers [post]
+func (c *TaskHandler) WatchTask(ctx *gin.Context) {
+	watchers, err := c.taskService.WatchTask(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Watcher added successfully",
+		"watchers": watchers,
+	})
+}
+
+// UnwatchTask godoc
+// @Summary Berhenti mengikuti task
+// @Description Menghapus watcher task. User biasa hanya bisa menghapus dirinya sendiri
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/watchers/{user_id} [delete]
+func (c *TaskHandler) UnwatchTask(ctx *gin.Context) {
+	if err := c.taskService.UnwatchTask(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Watcher removed successfully",
+	})
+}
@@ -0,0 +1,145 @@
+package serviceroute
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func writeChecklist(ctx *gin.Context, checklist *taskmodel.Checklist, err error, status int, message string) {
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(status, gin.H{
+		"message":   message,
+		"checklist": checklist,
+	})
+}
+
+// GetChecklist godoc
+// @Summary Get checklist task
+// @Description Mendapatkan item checklist task sesuai urutan beserta jumlah item dan item yang sudah dicentang
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist [get]
+func (c *TaskHandler) GetChecklist(ctx *gin.Context) {
+	checklist, err := c.taskService.GetChecklist(ctx)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist retrieved successfully")
+}
+
+// AddChecklistItem godoc
+// @Summary Tambah item checklist
+// @Description Menambahkan item di paling bawah checklist (manager project atau assignee task). assignee_id harus member project
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} true "content dan assignee_id"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist [post]
+func (c *TaskHandler) AddChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.AddChecklistItem(ctx)
+	writeChecklist(ctx, checklist, err, http.StatusCreated, "Checklist item created successfully")
+}
+
+// UpdateChecklistItem godoc
+// @Summary Update item checklist
+// @Description Mengubah content atau assignee_id item dengan JSON Merge Patch, null menghapus assignee
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Param input body map[string]interface{} true "content, assignee_id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist/{item_id} [patch]
+func (c *TaskHandler) UpdateChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.UpdateChecklistItem(ctx)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist item updated successfully")
+}
+
+// CheckChecklistItem godoc
+// @Summary Centang item checklist
+// @Description Mencentang item (manager project, assignee task atau assignee item). suggest_done bernilai true jika semua item sudah dicentang tapi task belum done
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist/{item_id}/check [post]
+func (c *TaskHandler) CheckChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.SetChecklistItemChecked(ctx, true)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist item checked successfully")
+}
+
+// UncheckChecklistItem godoc
+// @Summary Hapus centang item checklist
+// @Description Menghapus centang item (manager project, assignee task atau assignee item)
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist/{item_id}/uncheck [post]
+func (c *TaskHandler) UncheckChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.SetChecklistItemChecked(ctx, false)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist item unchecked successfully")
+}
+
+// MoveChecklistItem godoc
+// @Summary Pindahkan item checklist
+// @Description Mengubah urutan item. previous_id adalah item tepat di atas posisi baru, next_id item tepat di bawahnya; tanpa keduanya item dipindah ke paling bawah
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Param input body map[string]interface{} true "previous_id, next_id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist/{item_id}/move [post]
+func (c *TaskHandler) MoveChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.MoveChecklistItem(ctx)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist item moved successfully")
+}
+
+// DeleteChecklistItem godoc
+// @Summary Hapus item checklist
+// @Description Menghapus item checklist (manager project atau assignee task)
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/checklist/{item_id} [delete]
+func (c *TaskHandler) DeleteChecklistItem(ctx *gin.Context) {
+	checklist, err := c.taskService.DeleteChecklistItem(ctx)
+	writeChecklist(ctx, checklist, err, http.StatusOK, "Checklist item deleted successfully")
+}
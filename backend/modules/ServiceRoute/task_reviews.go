@@ -0,0 +1,132 @@
+package serviceroute
+
+import (
+	concurrency "gintugas/modules/components/Concurrency"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func writeReviewedTask(ctx *gin.Context, task *taskmodel.TaskResponse, err error, message string) {
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	concurrency.SetETag(ctx, task.Version)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"task":    task,
+	})
+}
+
+// SetTaskStatus godoc
+// @Summary Ubah status task sendiri
+// @Description Assignee memindahkan task miliknya antara todo dan in-progress. Task yang sedang direview atau sudah done tidak bisa diubah assignee
+// @Tags task-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "status: todo atau in-progress"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/status [post]
+func (c *TaskHandler) SetTaskStatus(ctx *gin.Context) {
+	task, err := c.taskService.SetTaskStatus(ctx)
+	writeReviewedTask(ctx, task, err, "Task status updated successfully")
+}
+
+// SubmitTaskReview godoc
+// @Summary Ajukan review task
+// @Description Assignee mengajukan task ke manager project, status task menjadi in-review sampai disetujui atau ditolak
+// @Tags task-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} false "note untuk manager"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/review [post]
+func (c *TaskHandler) SubmitTaskReview(ctx *gin.Context) {
+	task, err := c.taskService.SubmitTaskReview(ctx)
+	writeReviewedTask(ctx, task, err, "Task submitted for review successfully")
+}
+
+// ApproveTaskReview godoc
+// @Summary Setujui review task
+// @Description Manager project menyetujui review pending, task menjadi done
+// @Tags task-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} false "comment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/review/approve [post]
+func (c *TaskHandler) ApproveTaskReview(ctx *gin.Context) {
+	task, err := c.taskService.ApproveTaskReview(ctx)
+	writeReviewedTask(ctx, task, err, "Task review approved successfully")
+}
+
+// RejectTaskReview godoc
+// @Summary Tolak review task
+// @Description Manager project menolak review pending dengan comment, task kembali ke in-progress
+// @Tags task-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param task_id path string true "Task ID"
+// @Param If-Match header string false "ETag task yang terakhir dibaca"
+// @Param input body map[string]interface{} true "comment (wajib)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/tasks/{task_id}/review/reject [post]
+func (c *TaskHandler) RejectTaskReview(ctx *gin.Context) {
+	task, err := c.taskService.RejectTaskReview(ctx)
+	writeReviewedTask(ctx, task, err, "Task review rejected successfully")
+}
+
+// GetTaskReviews godoc
+// @Summary Get riwayat review task
+// @Description Mendapatkan semua pengajuan review task beserta keputusan dan comment manager, terbaru di atas (hanya admin, manager dan member project)
+// @Tags task-reviews
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/reviews [get]
+func (c *TaskHandler) GetTaskReviews(ctx *gin.Context) {
+	reviews, err := c.taskService.GetTaskReviews(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task reviews retrieved successfully",
+		"reviews": reviews,
+	})
+}
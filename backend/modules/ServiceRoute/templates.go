@@ -0,0 +1,206 @@
+package serviceroute
+
+import (
+	templateservice "gintugas/modules/components/Templates/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService templateservice.TemplateService
+}
+
+func NewTemplateHandler(templateService templateservice.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// CreateTemplate godoc
+// @Summary Buat template project
+// @Description Membuat template berisi daftar task (hanya admin/manager). due_offset_days dihitung dari start_date project, assignee_placeholder dipetakan ke user saat template dipakai
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body map[string]interface{} true "name, description, tasks"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates [post]
+func (h *TemplateHandler) CreateTemplate(ctx *gin.Context) {
+	template, err := h.templateService.CreateTemplate(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":  "Template created successfully",
+		"template": template,
+	})
+}
+
+// GetTemplates godoc
+// @Summary Get semua template project
+// @Description Mendapatkan semua template beserta task dan placeholder-nya (hanya admin/manager)
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates [get]
+func (h *TemplateHandler) GetTemplates(ctx *gin.Context) {
+	templates, err := h.templateService.GetTemplates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":   "Templates retrieved successfully",
+		"templates": templates,
+	})
+}
+
+// GetTemplate godoc
+// @Summary Get template project
+// @Description Mendapatkan detail template beserta task dan placeholder-nya (hanya admin/manager)
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param template_id path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates/{template_id} [get]
+func (h *TemplateHandler) GetTemplate(ctx *gin.Context) {
+	template, err := h.templateService.GetTemplate(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Template retrieved successfully",
+		"template": template,
+	})
+}
+
+// UpdateTemplate godoc
+// @Summary Update template project
+// @Description Mengganti name, description dan seluruh task template (hanya pembuat template atau admin)
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template_id path string true "Template ID"
+// @Param input body map[string]interface{} true "name, description, tasks"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates/{template_id} [put]
+func (h *TemplateHandler) UpdateTemplate(ctx *gin.Context) {
+	template, err := h.templateService.UpdateTemplate(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Template updated successfully",
+		"template": template,
+	})
+}
+
+// DeleteTemplate godoc
+// @Summary Hapus template project
+// @Description Menghapus template (hanya pembuat template atau admin). Project yang sudah dibuat dari template tidak terpengaruh
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param template_id path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates/{template_id} [delete]
+func (h *TemplateHandler) DeleteTemplate(ctx *gin.Context) {
+	if err := h.templateService.DeleteTemplate(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+	})
+}
+
+// SaveProjectAsTemplate godoc
+// @Summary Simpan project sebagai template
+// @Description Membuat template dari task project (hanya manager project). Offset due date dihitung dari tanggal project dibuat, username assignee utama menjadi placeholder
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param input body map[string]interface{} true "name, description"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/template [post]
+func (h *TemplateHandler) SaveProjectAsTemplate(ctx *gin.Context) {
+	template, err := h.templateService.SaveProjectAsTemplate(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":  "Template created successfully",
+		"template": template,
+	})
+}
+
+// InstantiateTemplate godoc
+// @Summary Buat project dari template
+// @Description Membuat project, member dan semua task template dalam satu transaksi; user yang login menjadi manager. assignees memetakan placeholder ke user ID, placeholder yang tidak dipetakan menjadi task tanpa assignee
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template_id path string true "Template ID"
+// @Param input body map[string]interface{} true "name, description, start_date, members, assignees"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/templates/{template_id}/instantiate [post]
+func (h *TemplateHandler) InstantiateTemplate(ctx *gin.Context) {
+	result, err := h.templateService.InstantiateTemplate(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":    "Project created from template successfully",
+		"project":    result.Project,
+		"members":    result.Members,
+		"task_count": result.TaskCount,
+	})
+}
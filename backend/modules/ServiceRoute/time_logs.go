@@ -0,0 +1,171 @@
+package serviceroute
+
+import (
+	timelogservice "gintugas/modules/components/TimeTracking/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimeLogHandler struct {
+	timeLogService timelogservice.TimeLogService
+}
+
+func NewTimeLogHandler(timeLogService timelogservice.TimeLogService) *TimeLogHandler {
+	return &TimeLogHandler{
+		timeLogService: timeLogService,
+	}
+}
+
+// LogTime godoc
+// @Summary Catat waktu kerja
+// @Description Mencatat waktu kerja manual di task (assignee task atau manager project)
+// @Tags time-logs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} true "duration_minutes, log_date (YYYY-MM-DD, default hari ini), note"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/time-logs [post]
+func (h *TimeLogHandler) LogTime(ctx *gin.Context) {
+	log, err := h.timeLogService.LogTime(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":  "Time logged successfully",
+		"time_log": log,
+	})
+}
+
+// GetTaskTimeLogs godoc
+// @Summary Get task time logs
+// @Description Mendapatkan semua catatan waktu task beserta total menit (hanya admin, manager dan member project)
+// @Tags time-logs
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/time-logs [get]
+func (h *TimeLogHandler) GetTaskTimeLogs(ctx *gin.Context) {
+	logs, err := h.timeLogService.GetTaskTimeLogs(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Time logs retrieved successfully",
+		"data":    logs,
+	})
+}
+
+// DeleteTimeLog godoc
+// @Summary Hapus catatan waktu
+// @Description Menghapus catatan waktu (pemilik catatan atau manager project)
+// @Tags time-logs
+// @Produce json
+// @Security BearerAuth
+// @Param time_log_id path string true "Time Log ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/time-logs/{time_log_id} [delete]
+func (h *TimeLogHandler) DeleteTimeLog(ctx *gin.Context) {
+	if err := h.timeLogService.DeleteTimeLog(ctx); err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Time log deleted successfully",
+	})
+}
+
+// StartTimer godoc
+// @Summary Mulai timer
+// @Description Memulai timer di task. Satu user hanya bisa punya satu timer yang berjalan
+// @Tags time-logs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} false "note"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/timer/start [post]
+func (h *TimeLogHandler) StartTimer(ctx *gin.Context) {
+	log, err := h.timeLogService.StartTimer(ctx)
+	if err != nil {
+		ctx.JSON(errorStatus(err, http.StatusBadRequest), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":  "Timer started successfully",
+		"time_log": log,
+	})
+}
+
+// StopTimer godoc
+// @Summary Hentikan timer
+// @Description Menghentikan timer di task, durasi dibulatkan ke atas per menit. Timer tetap bisa dihentikan pemiliknya walaupun sudah tidak menjadi assignee atau task sudah dihapus
+// @Tags time-logs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param input body map[string]interface{} false "note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/tasks/{task_id}/timer/stop [post]
+func (h *TimeLogHandler) StopTimer(ctx *gin.Context) {
+	log, err := h.timeLogService.StopTimer(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Timer stopped successfully",
+		"time_log": log,
+	})
+}
+
+// GetRunningTimer godoc
+// @Summary Timer yang berjalan
+// @Description Mendapatkan timer user yang sedang berjalan, null jika tidak ada
+// @Tags time-logs
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/time-logs/running [get]
+func (h *TimeLogHandler) GetRunningTimer(ctx *gin.Context) {
+	log, err := h.timeLogService.GetRunningTimer(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Running timer retrieved successfully",
+		"time_log": log,
+	})
+}
@@ -0,0 +1,142 @@
+package serviceroute
+
+import (
+	trashservice "gintugas/modules/components/Trash/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService trashservice.TrashService
+}
+
+func NewTrashHandler(trashService trashservice.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetProjectTrash godoc
+// @Summary Trash project
+// @Description Daftar task dan komentar project yang ada di trash beserta tanggal penghapusan permanennya (manager project atau admin)
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/projects/{project_id}/trash [get]
+func (h *TrashHandler) GetProjectTrash(ctx *gin.Context) {
+	trash, err := h.trashService.GetProjectTrash(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Trash retrieved successfully",
+		"trash":   trash,
+	})
+}
+
+// GetDeletedProjects godoc
+// @Summary Project di trash
+// @Description Admin melihat semua project yang dihapus, manager hanya project miliknya
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/trash/projects [get]
+func (h *TrashHandler) GetDeletedProjects(ctx *gin.Context) {
+	projects, err := h.trashService.GetDeletedProjects(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Deleted projects retrieved successfully",
+		"projects": projects,
+	})
+}
+
+// RestoreProject godoc
+// @Summary Restore project
+// @Description Mengembalikan project dari trash beserta task yang ikut terhapus bersamanya. Task yang dihapus sendiri sebelumnya tetap di trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/trash/projects/{project_id}/restore [post]
+func (h *TrashHandler) RestoreProject(ctx *gin.Context) {
+	restored, err := h.trashService.RestoreProject(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":        "Project restored successfully",
+		"restored_tasks": restored,
+	})
+}
+
+// RestoreTask godoc
+// @Summary Restore task
+// @Description Mengembalikan task dari trash (manager project atau admin). Project task harus tidak sedang di trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/trash/tasks/{task_id}/restore [post]
+func (h *TrashHandler) RestoreTask(ctx *gin.Context) {
+	if err := h.trashService.RestoreTask(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Task restored successfully",
+	})
+}
+
+// RestoreComment godoc
+// @Summary Restore komentar
+// @Description Mengembalikan komentar dari trash (penulis komentar, manager project atau admin). Task komentar harus tidak sedang di trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/trash/comments/{comment_id}/restore [post]
+func (h *TrashHandler) RestoreComment(ctx *gin.Context) {
+	if err := h.trashService.RestoreComment(ctx); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Comment restored successfully",
+	})
+}
@@ -109,6 +109,41 @@ func UpdateUsersRouter(db *sql.DB) gin.HandlerFunc {
 	}
 }
 
+// PatchUsersRouter godoc
+// @Summary Patch user
+// @Description Update sebagian field user dengan JSON Merge Patch (RFC 7396), field yang tidak dikirim tidak berubah (hanya admin)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param input body map[string]interface{} true "Field user yang diubah (username, email, password, role)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/admin/users/{id} [patch]
+func PatchUsersRouter(db *sql.DB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			usersrepo = userrepo.NewRepository(db)
+			usersSrv  = userservice.NewService(usersrepo)
+		)
+
+		users, err := usersSrv.PatchUserService(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Data users Berhasil di Update"),
+			"Users":   users,
+		})
+	}
+}
+
 // DeleteUsersRouter godoc
 // @Summary Delete user
 // @Description Hapus user (hanya admin)
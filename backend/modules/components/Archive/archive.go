@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrReadOnly dikembalikan ketika mengubah project atau task yang sudah diarsipkan
+var ErrReadOnly = errors.New("conflict: data sudah diarsipkan dan hanya bisa dibaca, batalkan arsip terlebih dahulu")
+
+// Filter menentukan data arsip mana yang ikut di listing
+type Filter string
+
+const (
+	// Active adalah default listing: data yang diarsipkan tidak ikut
+	Active Filter = "false"
+	// Only hanya menampilkan data yang diarsipkan
+	Only Filter = "true"
+	// All menampilkan data aktif maupun yang diarsipkan
+	All Filter = "all"
+)
+
+// ParseFilter membaca query parameter archived, nilai kosong berarti Active
+func ParseFilter(value string) (Filter, error) {
+	switch Filter(strings.ToLower(strings.TrimSpace(value))) {
+	case "", Active:
+		return Active, nil
+	case Only:
+		return Only, nil
+	case All:
+		return All, nil
+	default:
+		return Active, errors.New("archived harus bernilai true, false atau all")
+	}
+}
+
+// Condition mengembalikan kondisi SQL untuk filter berdasarkan ekspresi "sudah diarsipkan", string kosong berarti tanpa kondisi
+func (f Filter) Condition(archivedExpr string) string {
+	switch f {
+	case Only:
+		return archivedExpr
+	case All:
+		return ""
+	default:
+		return "NOT (" + archivedExpr + ")"
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	models "gintugas/modules/components/Auth/model"
 	. "gintugas/modules/components/Auth/repo"
+	patch "gintugas/modules/components/Patch"
+	"net/mail"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,6 +19,7 @@ type Service interface {
 	GetAllUsersService(ctx *gin.Context) (result []models.User, err error)
 	GetUserService(ctx *gin.Context) (result models.User, err error)
 	UpdateUserService(ctx *gin.Context) (u models.User, err error)
+	PatchUserService(ctx *gin.Context) (u models.User, err error)
 	DeleteUserService(ctx *gin.Context) (err error)
 }
 type userService struct {
@@ -124,6 +128,76 @@ func (s *userService) UpdateUserService(ctx *gin.Context) (u models.User, err er
 	return u, nil
 }
 
+// PatchUserService mengubah sebagian field user (JSON Merge Patch). Semua field user wajib ada nilainya sehingga null ditolak
+func (s *userService) PatchUserService(ctx *gin.Context) (u models.User, err error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return models.User{}, errors.New("ID users tidak valid")
+	}
+
+	users, err := s.repository.GetUserByIDRepository(id)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	doc, err := patch.Bind(ctx, "username", "email", "password", "role")
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if doc.Has("username") {
+		username, err := doc.String("username", false)
+		if err != nil {
+			return models.User{}, err
+		}
+		if strings.TrimSpace(username) == "" {
+			return models.User{}, errors.New("username tidak boleh kosong")
+		}
+		users.Username = strings.TrimSpace(username)
+	}
+
+	if doc.Has("email") {
+		email, err := doc.String("email", false)
+		if err != nil {
+			return models.User{}, err
+		}
+		if _, err := mail.ParseAddress(email); err != nil {
+			return models.User{}, errors.New("format email tidak valid")
+		}
+		users.Email = email
+	}
+
+	if doc.Has("role") {
+		role, err := doc.String("role", false)
+		if err != nil {
+			return models.User{}, err
+		}
+		if role != "admin" && role != "manager" && role != "staff" {
+			return models.User{}, errors.New("role harus admin, manager atau staff")
+		}
+		users.Role = role
+	}
+
+	if doc.Has("password") {
+		password, err := doc.String("password", false)
+		if err != nil {
+			return models.User{}, err
+		}
+		if len(password) < 6 {
+			return models.User{}, errors.New("password minimal 6 karakter")
+		}
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return models.User{}, errors.New("gagal encrypt password")
+		}
+		users.Password = string(hashedPassword)
+	}
+
+	users.UpdatedAt = time.Now()
+
+	return s.repository.UpdateUsersRepository(users)
+}
+
 func (s *userService) DeleteUserService(ctx *gin.Context) (err error) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
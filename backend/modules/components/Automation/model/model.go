@@ -0,0 +1,133 @@
+package automationmodel
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// trigger rule
+const (
+	TriggerStatusChanged = "status_changed"
+	TriggerTaskCreated   = "task_created"
+	TriggerDueDatePassed = "due_date_passed"
+	TriggerCommentAdded  = "comment_added"
+)
+
+// jenis action, dijalankan berurutan sesuai urutan di rule
+const (
+	ActionAssignUser       = "assign_user"
+	ActionChangeStatus     = "change_status"
+	ActionAddComment       = "add_comment"
+	ActionSendNotification = "send_notification"
+	ActionCallWebhook      = "call_webhook"
+)
+
+// penerima send_notification selain user ID
+const (
+	RecipientAssignees = "assignees"
+	RecipientManager   = "manager"
+	RecipientWatchers  = "watchers"
+)
+
+// status execution, running berarti rule sedang (atau terhenti saat) dijalankan
+const (
+	ExecutionRunning = "running"
+	ExecutionSuccess = "success"
+	ExecutionFailed  = "failed"
+)
+
+// RuleConditions harus terpenuhi semua supaya action dijalankan, field kosong diabaikan.
+// FromStatus dan ToStatus hanya dipakai trigger status_changed
+type RuleConditions struct {
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status,omitempty"`
+	// AssigneeID berisi user ID yang harus menjadi salah satu assignee task, atau "none" untuk task tanpa assignee
+	AssigneeID string `json:"assignee_id,omitempty"`
+	// TitleKeywords cocok jika salah satu kata ada di title task (tanpa membedakan huruf besar kecil)
+	TitleKeywords []string `json:"title_keywords,omitempty"`
+	// DueWithinDays cocok untuk task yang punya due date paling lambat sekian hari dari hari ini, termasuk yang sudah lewat
+	DueWithinDays *int `json:"due_within_days,omitempty"`
+}
+
+// RuleAction: field yang dipakai tergantung Type. Comment dan Message boleh memakai {task}, {status}, {project} dan {rule}
+type RuleAction struct {
+	Type       string     `json:"type" binding:"required,oneof=assign_user change_status add_comment send_notification call_webhook"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	Recipients []string   `json:"recipients,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	URL        string     `json:"url,omitempty"`
+}
+
+type AutomationRule struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID  uuid.UUID      `json:"project_id" gorm:"type:uuid;not null"`
+	Name       string         `json:"name" gorm:"type:varchar(100);not null"`
+	Trigger    string         `json:"trigger" gorm:"type:automation_trigger;not null"`
+	Conditions RuleConditions `json:"conditions" gorm:"type:jsonb;serializer:json"`
+	Actions    []RuleAction   `json:"actions" gorm:"type:jsonb;serializer:json"`
+	Enabled    bool           `json:"enabled" gorm:"not null;default:true"`
+	CreatedBy  *uuid.UUID     `json:"created_by" gorm:"type:uuid"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (AutomationRule) TableName() string {
+	return "automation_rules"
+}
+
+// ActionResult adalah hasil satu action dalam satu execution
+type ActionResult struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type AutomationExecution struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RuleID     uuid.UUID      `json:"rule_id" gorm:"type:uuid;not null"`
+	TaskID     *uuid.UUID     `json:"task_id" gorm:"type:uuid"`
+	Trigger    string         `json:"trigger" gorm:"type:automation_trigger;not null"`
+	Status     string         `json:"status" gorm:"type:automation_execution_status;not null;default:'running'"`
+	Results    []ActionResult `json:"results" gorm:"type:jsonb;serializer:json"`
+	Error      string         `json:"error" gorm:"type:text"`
+	DedupeKey  *string        `json:"-" gorm:"type:varchar(100)"`
+	StartedAt  time.Time      `json:"started_at" gorm:"default:CURRENT_TIMESTAMP"`
+	FinishedAt *time.Time     `json:"finished_at"`
+}
+
+func (AutomationExecution) TableName() string {
+	return "automation_executions"
+}
+
+// RuleRequest: enabled kosong berarti rule langsung aktif
+type RuleRequest struct {
+	Name       string         `json:"name" binding:"required,max=100"`
+	Trigger    string         `json:"trigger" binding:"required,oneof=status_changed task_created due_date_passed comment_added"`
+	Conditions RuleConditions `json:"conditions"`
+	Actions    []RuleAction   `json:"actions" binding:"required,min=1,max=10,dive"`
+	Enabled    *bool          `json:"enabled"`
+}
+
+// WebhookPayload adalah body JSON yang dikirim action call_webhook
+type WebhookPayload struct {
+	RuleID   uuid.UUID   `json:"rule_id"`
+	RuleName string      `json:"rule_name"`
+	Trigger  string      `json:"trigger"`
+	Task     WebhookTask `json:"task"`
+	// Changes berisi perubahan field untuk trigger status_changed dan task_created
+	Changes []taskmodel.FieldChange `json:"changes,omitempty"`
+	SentAt  time.Time               `json:"sent_at"`
+}
+
+type WebhookTask struct {
+	ID         uuid.UUID  `json:"id"`
+	ProjectID  uuid.UUID  `json:"project_id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	AssigneeID *uuid.UUID `json:"assignee_id"`
+	DueDate    *time.Time `json:"due_date"`
+}
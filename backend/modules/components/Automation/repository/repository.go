@@ -0,0 +1,126 @@
+package automationrepository
+
+import (
+	automationmodel "gintugas/modules/components/Automation/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	commentmodel "gintugas/modules/components/command/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AutomationRepository interface {
+	GetProjectRules(projectID uuid.UUID) ([]automationmodel.AutomationRule, error)
+	GetRule(projectID uuid.UUID, ruleID uuid.UUID) (*automationmodel.AutomationRule, error)
+	CreateRule(rule *automationmodel.AutomationRule) error
+	UpdateRule(rule *automationmodel.AutomationRule) error
+	DeleteRule(ruleID uuid.UUID) error
+
+	// GetEnabledRules mengambil rule aktif untuk trigger tertentu, projectID nil berarti semua project
+	GetEnabledRules(projectID *uuid.UUID, trigger string) ([]automationmodel.AutomationRule, error)
+	// GetPassedDueTasks mengambil task aktif yang belum done di project tersebut dengan due_date antara since dan kemarin
+	GetPassedDueTasks(projectIDs []uuid.UUID, since time.Time) ([]taskmodel.Task, error)
+
+	// StartExecution mencatat execution berstatus running. Execution dengan dedupe_key yang sudah pernah dicatat untuk
+	// rule yang sama tidak disimpan dan StartExecution mengembalikan false
+	StartExecution(execution *automationmodel.AutomationExecution) (bool, error)
+	FinishExecution(execution *automationmodel.AutomationExecution) error
+	GetRuleExecutions(ruleID uuid.UUID, limit int) ([]automationmodel.AutomationExecution, error)
+
+	CreateComment(comment *commentmodel.Comments) error
+}
+
+type automationRepository struct {
+	db *gorm.DB
+}
+
+func NewAutomationRepository(db *gorm.DB) AutomationRepository {
+	return &automationRepository{db: db}
+}
+
+func (r *automationRepository) GetProjectRules(projectID uuid.UUID) ([]automationmodel.AutomationRule, error) {
+	rules := []automationmodel.AutomationRule{}
+	err := r.db.Where("project_id = ?", projectID).
+		Order("created_at ASC, id ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *automationRepository) GetRule(projectID uuid.UUID, ruleID uuid.UUID) (*automationmodel.AutomationRule, error) {
+	var rule automationmodel.AutomationRule
+	err := r.db.Where("project_id = ? AND id = ?", projectID, ruleID).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *automationRepository) CreateRule(rule *automationmodel.AutomationRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *automationRepository) UpdateRule(rule *automationmodel.AutomationRule) error {
+	return r.db.Model(rule).
+		Select("*").
+		Omit("id", "project_id", "created_by", "created_at").
+		Updates(rule).Error
+}
+
+func (r *automationRepository) DeleteRule(ruleID uuid.UUID) error {
+	return r.db.Delete(&automationmodel.AutomationRule{}, "id = ?", ruleID).Error
+}
+
+func (r *automationRepository) GetEnabledRules(projectID *uuid.UUID, trigger string) ([]automationmodel.AutomationRule, error) {
+	rules := []automationmodel.AutomationRule{}
+	query := r.db.Where("enabled AND trigger = ?", trigger)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	err := query.Order("created_at ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetPassedDueTasks: task di trash tidak ikut karena soft delete, task dan project yang diarsipkan dicek sendiri
+func (r *automationRepository) GetPassedDueTasks(projectIDs []uuid.UUID, since time.Time) ([]taskmodel.Task, error) {
+	tasks := []taskmodel.Task{}
+	if len(projectIDs) == 0 {
+		return tasks, nil
+	}
+	err := r.db.Model(&taskmodel.Task{}).
+		Where("tasks.project_id IN ?", projectIDs).
+		Where("tasks.due_date >= ? AND tasks.due_date < CURRENT_DATE", since.Format("2006-01-02")).
+		Where("tasks.status <> 'done' AND tasks.archived_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL)").
+		Order("tasks.due_date ASC, tasks.id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *automationRepository) StartExecution(execution *automationmodel.AutomationExecution) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(execution)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *automationRepository) FinishExecution(execution *automationmodel.AutomationExecution) error {
+	return r.db.Model(execution).
+		Select("status", "results", "error", "finished_at").
+		Updates(execution).Error
+}
+
+func (r *automationRepository) GetRuleExecutions(ruleID uuid.UUID, limit int) ([]automationmodel.AutomationExecution, error) {
+	executions := []automationmodel.AutomationExecution{}
+	err := r.db.Where("rule_id = ?", ruleID).
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&executions).Error
+	return executions, err
+}
+
+func (r *automationRepository) CreateComment(comment *commentmodel.Comments) error {
+	return r.db.Create(comment).Error
+}
@@ -0,0 +1,427 @@
+package automationservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	automationmodel "gintugas/modules/components/Automation/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	commentmodel "gintugas/modules/components/command/model"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	queueSize      = 256
+	webhookTimeout = 10 * time.Second
+
+	// dueCatchUpDays membatasi task overdue yang masih memicu due_date_passed, supaya rule baru tidak langsung
+	// berjalan untuk semua task lama yang sudah lama overdue
+	dueCatchUpDays = 7
+)
+
+// job adalah satu event yang menunggu diproses worker. rules terisi untuk due_date_passed karena scheduler sudah
+// memilih rule-nya, untuk trigger lain rule diambil dari project task saat job diproses
+type job struct {
+	trigger   string
+	taskID    uuid.UUID
+	changes   []taskmodel.FieldChange
+	rules     []automationmodel.AutomationRule
+	dedupeKey *string
+}
+
+// HandleTaskEvent mengabaikan event dari action automation sendiri supaya rule tidak saling memicu tanpa henti.
+// Event dari proses lain tanpa actor (recurrence, import, template) tetap diproses
+func (s *automationService) HandleTaskEvent(event taskmodel.TaskEvent) {
+	if event.Source == taskmodel.EventSourceAutomation {
+		return
+	}
+
+	switch {
+	case event.Action == taskmodel.HistoryCreated:
+		s.enqueue(job{trigger: automationmodel.TriggerTaskCreated, taskID: event.Task.ID, changes: event.Changes})
+	case event.Action == taskmodel.HistoryUpdated && event.HasChange("status"):
+		s.enqueue(job{trigger: automationmodel.TriggerStatusChanged, taskID: event.Task.ID, changes: event.Changes})
+	}
+}
+
+// HandleCommentEvent: komentar dari action add_comment disimpan lewat repository automation tanpa event, jadi tidak memicu rule lagi
+func (s *automationService) HandleCommentEvent(event commentmodel.CommentEvent) {
+	s.enqueue(job{trigger: automationmodel.TriggerCommentAdded, taskID: event.Comment.TaskID})
+}
+
+// enqueue tidak pernah menunggu: jika antrian penuh event dilewati supaya publisher tidak ikut tertahan
+func (s *automationService) enqueue(j job) {
+	select {
+	case s.jobs <- j:
+	default:
+		fmt.Printf("Antrian automation penuh, event %s task %s dilewati\n", j.trigger, j.taskID)
+	}
+}
+
+func (s *automationService) StartWorkers(workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range s.jobs {
+				s.process(j)
+			}
+		}()
+	}
+}
+
+func (s *automationService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.queuePassedDueTasks(); err != nil {
+				fmt.Printf("Gagal memeriksa trigger due_date_passed: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// queuePassedDueTasks memasukkan task yang due date-nya sudah lewat ke antrian. dedupe_key berisi task dan due date,
+// jadi setiap rule hanya berjalan sekali untuk satu due date walaupun scheduler berjalan berkali-kali atau server restart
+func (s *automationService) queuePassedDueTasks() error {
+	rules, err := s.repo.GetEnabledRules(nil, automationmodel.TriggerDueDatePassed)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	rulesByProject := map[uuid.UUID][]automationmodel.AutomationRule{}
+	projectIDs := []uuid.UUID{}
+	for _, rule := range rules {
+		if _, ok := rulesByProject[rule.ProjectID]; !ok {
+			projectIDs = append(projectIDs, rule.ProjectID)
+		}
+		rulesByProject[rule.ProjectID] = append(rulesByProject[rule.ProjectID], rule)
+	}
+
+	tasks, err := s.repo.GetPassedDueTasks(projectIDs, time.Now().AddDate(0, 0, -dueCatchUpDays))
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		dedupeKey := fmt.Sprintf("due:%s:%s", task.ID, task.DueDate.Format("2006-01-02"))
+		s.enqueue(job{
+			trigger:   automationmodel.TriggerDueDatePassed,
+			taskID:    task.ID,
+			rules:     rulesByProject[task.ProjectID],
+			dedupeKey: &dedupeKey,
+		})
+	}
+	return nil
+}
+
+// process membaca ulang task supaya condition dicek terhadap data terbaru, task yang sudah dihapus dilewati
+func (s *automationService) process(j job) {
+	task, err := s.taskRepo.GetTaskByID(j.taskID)
+	if err != nil {
+		return
+	}
+
+	rules := j.rules
+	if rules == nil {
+		rules, err = s.repo.GetEnabledRules(&task.ProjectID, j.trigger)
+		if err != nil {
+			fmt.Printf("Gagal mengambil automation rule: %v\n", err)
+			return
+		}
+	}
+
+	for i := range rules {
+		if !matches(&rules[i], task, j.changes) {
+			continue
+		}
+		// action rule sebelumnya bisa mengubah task
+		if i > 0 {
+			if task, err = s.taskRepo.GetTaskByID(j.taskID); err != nil {
+				return
+			}
+		}
+		s.execute(&rules[i], task, j)
+	}
+}
+
+// matches memeriksa semua condition rule, condition yang kosong selalu cocok
+func matches(rule *automationmodel.AutomationRule, task *taskmodel.Task, changes []taskmodel.FieldChange) bool {
+	conditions := rule.Conditions
+
+	if rule.Trigger == automationmodel.TriggerStatusChanged {
+		for _, change := range changes {
+			if change.Field != "status" {
+				continue
+			}
+			if conditions.FromStatus != "" && fmt.Sprint(change.Old) != conditions.FromStatus {
+				return false
+			}
+			if conditions.ToStatus != "" && fmt.Sprint(change.New) != conditions.ToStatus {
+				return false
+			}
+		}
+	}
+
+	switch conditions.AssigneeID {
+	case "":
+	case "none":
+		if len(task.Assignees) > 0 {
+			return false
+		}
+	default:
+		assigned := false
+		for _, assignee := range task.Assignees {
+			if assignee.UserID.String() == conditions.AssigneeID {
+				assigned = true
+			}
+		}
+		if !assigned {
+			return false
+		}
+	}
+
+	if len(conditions.TitleKeywords) > 0 {
+		title := strings.ToLower(task.Title)
+		found := false
+		for _, keyword := range conditions.TitleKeywords {
+			if strings.Contains(title, strings.ToLower(keyword)) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if conditions.DueWithinDays != nil {
+		if task.DueDate == nil {
+			return false
+		}
+		now := time.Now()
+		limit := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, *conditions.DueWithinDays)
+		if task.DueDate.After(limit) {
+			return false
+		}
+	}
+	return true
+}
+
+// execute menjalankan action rule secara berurutan dan mencatat hasilnya. Action yang gagal menghentikan action berikutnya
+func (s *automationService) execute(rule *automationmodel.AutomationRule, task *taskmodel.Task, j job) {
+	execution := &automationmodel.AutomationExecution{
+		RuleID:    rule.ID,
+		TaskID:    &task.ID,
+		Trigger:   j.trigger,
+		Status:    automationmodel.ExecutionRunning,
+		Results:   []automationmodel.ActionResult{},
+		DedupeKey: j.dedupeKey,
+	}
+
+	started, err := s.repo.StartExecution(execution)
+	if err != nil {
+		fmt.Printf("Gagal mencatat automation execution: %v\n", err)
+		return
+	}
+	if !started {
+		return
+	}
+
+	execution.Status = automationmodel.ExecutionSuccess
+	for i, action := range rule.Actions {
+		detail, err := s.runAction(rule, task, action, j)
+		if err != nil {
+			execution.Status = automationmodel.ExecutionFailed
+			execution.Error = fmt.Sprintf("action %d (%s): %v", i+1, action.Type, err)
+			execution.Results = append(execution.Results, automationmodel.ActionResult{
+				Type:   action.Type,
+				Status: automationmodel.ExecutionFailed,
+				Detail: err.Error(),
+			})
+			break
+		}
+		execution.Results = append(execution.Results, automationmodel.ActionResult{
+			Type:   action.Type,
+			Status: automationmodel.ExecutionSuccess,
+			Detail: detail,
+		})
+	}
+
+	finishedAt := time.Now()
+	execution.FinishedAt = &finishedAt
+	if err := s.repo.FinishExecution(execution); err != nil {
+		fmt.Printf("Gagal menyimpan hasil automation execution: %v\n", err)
+	}
+}
+
+// runAction mengembalikan keterangan singkat untuk log execution. task ikut diperbarui oleh action yang mengubahnya
+func (s *automationService) runAction(rule *automationmodel.AutomationRule, task *taskmodel.Task, action automationmodel.RuleAction, j job) (string, error) {
+	switch action.Type {
+	case automationmodel.ActionAssignUser:
+		return s.updateTask(task, func(t *taskmodel.Task) error {
+			t.AssigneeID = action.UserID
+			return nil
+		})
+
+	case automationmodel.ActionChangeStatus:
+		return s.updateTask(task, func(t *taskmodel.Task) error {
+			t.Status = action.Status
+			return nil
+		})
+
+	case automationmodel.ActionAddComment:
+		return s.addComment(rule, task, action)
+
+	case automationmodel.ActionSendNotification:
+		return s.sendNotification(rule, task, action)
+
+	case automationmodel.ActionCallWebhook:
+		return s.callWebhook(rule, task, action, j)
+	}
+	return "", fmt.Errorf("action tidak dikenal: %s", action.Type)
+}
+
+func (s *automationService) updateTask(task *taskmodel.Task, mutate func(t *taskmodel.Task) error) (string, error) {
+	updated, changes, err := s.tasks.UpdateTaskAsSystem(task.ID, taskmodel.EventSourceAutomation, mutate)
+	if err != nil {
+		return "", err
+	}
+	*task = *updated
+
+	if len(changes) == 0 {
+		return "tidak ada perubahan", nil
+	}
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, fmt.Sprintf("%s: %v -> %v", change.Field, change.Old, change.New))
+	}
+	return strings.Join(fields, ", "), nil
+}
+
+// render mengganti placeholder {task}, {status}, {project} dan {rule} di comment dan message
+func render(text string, rule *automationmodel.AutomationRule, task *taskmodel.Task, projectName string) string {
+	return strings.NewReplacer(
+		"{task}", task.Title,
+		"{status}", task.Status,
+		"{project}", projectName,
+		"{rule}", rule.Name,
+	).Replace(text)
+}
+
+// addComment: komentar ditulis atas nama pembuat rule, atau manager project jika pembuatnya sudah tidak ada
+func (s *automationService) addComment(rule *automationmodel.AutomationRule, task *taskmodel.Task, action automationmodel.RuleAction) (string, error) {
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	author := project.ManagerID
+	if rule.CreatedBy != nil {
+		author = *rule.CreatedBy
+	}
+
+	comment := &commentmodel.Comments{
+		TaskID:  task.ID,
+		UserID:  &author,
+		Content: render(action.Comment, rule, task, project.Nama),
+	}
+	if err := s.repo.CreateComment(comment); err != nil {
+		return "", err
+	}
+	return "comment " + comment.ID.String(), nil
+}
+
+// sendNotification mengirim email ke setiap penerima sekali, penerima tanpa email dilewati
+func (s *automationService) sendNotification(rule *automationmodel.AutomationRule, task *taskmodel.Task, action automationmodel.RuleAction) (string, error) {
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	userIDs := []uuid.UUID{}
+	for _, recipient := range action.Recipients {
+		switch recipient {
+		case automationmodel.RecipientAssignees:
+			for _, assignee := range task.Assignees {
+				userIDs = append(userIDs, assignee.UserID)
+			}
+		case automationmodel.RecipientManager:
+			userIDs = append(userIDs, project.ManagerID)
+		case automationmodel.RecipientWatchers:
+			watchers, err := s.taskRepo.GetTaskWatchers(task.ID)
+			if err != nil {
+				return "", fmt.Errorf("gagal mengambil watcher task: %v", err)
+			}
+			for _, watcher := range watchers {
+				userIDs = append(userIDs, watcher.UserID)
+			}
+		default:
+			if userID, err := uuid.Parse(recipient); err == nil {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	message := render(action.Message, rule, task, project.Nama)
+	seen := map[uuid.UUID]bool{}
+	sent := 0
+	failures := []string{}
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := s.taskRepo.GetUserByID(userID)
+		if err != nil || user.Email == "" {
+			continue
+		}
+		if err := s.mailService.SendAutomationNotification(user.Email, rule.Name, task.Title, project.Nama, message); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", user.Email, err))
+			continue
+		}
+		sent++
+	}
+
+	if len(failures) > 0 {
+		return "", fmt.Errorf("gagal mengirim ke %s", strings.Join(failures, "; "))
+	}
+	return fmt.Sprintf("terkirim ke %d penerima", sent), nil
+}
+
+func (s *automationService) callWebhook(rule *automationmodel.AutomationRule, task *taskmodel.Task, action automationmodel.RuleAction, j job) (string, error) {
+	payload, err := json.Marshal(automationmodel.WebhookPayload{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Trigger:  j.trigger,
+		Task: automationmodel.WebhookTask{
+			ID:         task.ID,
+			ProjectID:  task.ProjectID,
+			Title:      task.Title,
+			Status:     task.Status,
+			AssigneeID: task.AssigneeID,
+			DueDate:    task.DueDate,
+		},
+		Changes: j.changes,
+		SentAt:  time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.Post(action.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", errors.New("webhook membalas status " + resp.Status)
+	}
+	return "webhook membalas status " + resp.Status, nil
+}
@@ -0,0 +1,335 @@
+package automationservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	automationmodel "gintugas/modules/components/Automation/model"
+	automationrepository "gintugas/modules/components/Automation/repository"
+	services "gintugas/modules/components/Mail/service"
+	outbound "gintugas/modules/components/Outbound"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	commentmodel "gintugas/modules/components/command/model"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var validStatuses = map[string]bool{
+	"todo":        true,
+	"in-progress": true,
+	"in-review":   true,
+	"done":        true,
+}
+
+// TaskUpdater adalah bagian TaskService yang dipakai action assign_user dan change_status
+type TaskUpdater interface {
+	UpdateTaskAsSystem(taskID uuid.UUID, source string, mutate func(task *taskmodel.Task) error) (*taskmodel.Task, []taskmodel.FieldChange, error)
+}
+
+type AutomationService interface {
+	GetProjectRules(ctx *gin.Context) ([]automationmodel.AutomationRule, error)
+	CreateRule(ctx *gin.Context) (*automationmodel.AutomationRule, error)
+	UpdateRule(ctx *gin.Context) (*automationmodel.AutomationRule, error)
+	DeleteRule(ctx *gin.Context) error
+	GetRuleExecutions(ctx *gin.Context) ([]automationmodel.AutomationExecution, error)
+
+	// HandleTaskEvent dan HandleCommentEvent hanya memasukkan event ke antrian, rule dijalankan oleh worker
+	HandleTaskEvent(event taskmodel.TaskEvent)
+	HandleCommentEvent(event commentmodel.CommentEvent)
+	// StartWorkers menjalankan worker yang memproses antrian event
+	StartWorkers(workers int)
+	// StartScheduler memeriksa trigger due_date_passed setiap interval
+	StartScheduler(interval time.Duration)
+}
+
+type automationService struct {
+	repo        automationrepository.AutomationRepository
+	taskRepo    taskrepository.TaskRepository
+	tasks       TaskUpdater
+	mailService services.MailService
+	httpClient  *http.Client
+	jobs        chan job
+}
+
+func NewAutomationService(repo automationrepository.AutomationRepository, taskRepo taskrepository.TaskRepository, tasks TaskUpdater, mailService services.MailService) AutomationService {
+	return &automationService{
+		repo:        repo,
+		taskRepo:    taskRepo,
+		tasks:       tasks,
+		mailService: mailService,
+		httpClient:  outbound.NewClient(webhookTimeout),
+		jobs:        make(chan job, queueSize),
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+// validateProjectManager: rule automation hanya bisa dikelola manager project. Dengan write, project yang diarsipkan
+// ditolak; rule dan execution log project arsip tetap bisa dilihat
+func (s *automationService) validateProjectManager(ctx *gin.Context, write bool) (uuid.UUID, error) {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	projectID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return uuid.Nil, errors.New("Gagal format Project ID")
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ManagerID != userUUID {
+		return uuid.Nil, errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	if write && project.ArchivedAt != nil {
+		return uuid.Nil, archive.ErrReadOnly
+	}
+	return projectID, nil
+}
+
+func (s *automationService) loadRule(ctx *gin.Context, write bool) (*automationmodel.AutomationRule, error) {
+	projectID, err := s.validateProjectManager(ctx, write)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(ctx.Param("rule_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format rule ID")
+	}
+
+	rule, err := s.repo.GetRule(projectID, ruleID)
+	if err != nil {
+		return nil, errors.New("automation rule tidak ditemukan")
+	}
+	return rule, nil
+}
+
+// applyRuleRequest memvalidasi trigger, condition dan setiap action terhadap project rule sebelum mengisi rule
+func (s *automationService) applyRuleRequest(rule *automationmodel.AutomationRule, req automationmodel.RuleRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("nama rule wajib diisi")
+	}
+
+	conditions := req.Conditions
+	if conditions.FromStatus != "" || conditions.ToStatus != "" {
+		if req.Trigger != automationmodel.TriggerStatusChanged {
+			return errors.New("from_status dan to_status hanya dipakai trigger status_changed")
+		}
+		for _, status := range []string{conditions.FromStatus, conditions.ToStatus} {
+			if status != "" && !validStatuses[status] {
+				return fmt.Errorf("status tidak valid: %s", status)
+			}
+		}
+	}
+
+	if conditions.AssigneeID != "" && conditions.AssigneeID != "none" {
+		assigneeID, err := uuid.Parse(conditions.AssigneeID)
+		if err != nil {
+			return errors.New("conditions.assignee_id harus user ID atau none")
+		}
+		if err := s.validateProjectMember(rule.ProjectID, assigneeID); err != nil {
+			return err
+		}
+	}
+
+	keywords := []string{}
+	for _, keyword := range conditions.TitleKeywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	conditions.TitleKeywords = keywords
+
+	if conditions.DueWithinDays != nil && *conditions.DueWithinDays < 0 {
+		return errors.New("conditions.due_within_days tidak boleh negatif")
+	}
+
+	for i := range req.Actions {
+		if err := s.validateAction(rule.ProjectID, &req.Actions[i]); err != nil {
+			return fmt.Errorf("action %d (%s): %v", i+1, req.Actions[i].Type, err)
+		}
+	}
+
+	rule.Name = name
+	rule.Trigger = req.Trigger
+	rule.Conditions = conditions
+	rule.Actions = req.Actions
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+func (s *automationService) validateAction(projectID uuid.UUID, action *automationmodel.RuleAction) error {
+	switch action.Type {
+	case automationmodel.ActionAssignUser:
+		if action.UserID == nil {
+			return errors.New("user_id wajib diisi")
+		}
+		return s.validateProjectMember(projectID, *action.UserID)
+
+	case automationmodel.ActionChangeStatus:
+		if !validStatuses[action.Status] {
+			return fmt.Errorf("status tidak valid: %s", action.Status)
+		}
+
+	case automationmodel.ActionAddComment:
+		action.Comment = strings.TrimSpace(action.Comment)
+		if action.Comment == "" {
+			return errors.New("comment wajib diisi")
+		}
+
+	case automationmodel.ActionSendNotification:
+		action.Message = strings.TrimSpace(action.Message)
+		if action.Message == "" {
+			return errors.New("message wajib diisi")
+		}
+		if len(action.Recipients) == 0 {
+			return errors.New("recipients wajib diisi")
+		}
+		for _, recipient := range action.Recipients {
+			switch recipient {
+			case automationmodel.RecipientAssignees, automationmodel.RecipientManager, automationmodel.RecipientWatchers:
+				continue
+			}
+			userID, err := uuid.Parse(recipient)
+			if err != nil {
+				return fmt.Errorf("recipient tidak valid: %s", recipient)
+			}
+			if err := s.validateProjectMember(projectID, userID); err != nil {
+				return err
+			}
+		}
+
+	case automationmodel.ActionCallWebhook:
+		target, err := url.Parse(strings.TrimSpace(action.URL))
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return errors.New("url harus berupa URL http atau https")
+		}
+		// hostname tetap diperiksa lagi saat koneksi dibuka oleh httpClient, di sini hanya supaya error langsung terlihat
+		host := strings.ToLower(target.Hostname())
+		if ip := net.ParseIP(host); (ip != nil && outbound.IsInternalIP(ip)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return errors.New("url tidak boleh mengarah ke jaringan internal")
+		}
+		action.URL = target.String()
+	}
+	return nil
+}
+
+func (s *automationService) validateProjectMember(projectID uuid.UUID, userID uuid.UUID) error {
+	isMember, err := s.taskRepo.IsProjectMember(projectID, userID)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa member project: %v", err)
+	}
+	if !isMember {
+		return fmt.Errorf("user %s bukan member project ini", userID)
+	}
+	return nil
+}
+
+func (s *automationService) GetProjectRules(ctx *gin.Context) ([]automationmodel.AutomationRule, error) {
+	projectID, err := s.validateProjectManager(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetProjectRules(projectID)
+}
+
+func (s *automationService) CreateRule(ctx *gin.Context) (*automationmodel.AutomationRule, error) {
+	projectID, err := s.validateProjectManager(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req automationmodel.RuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	userID, _ := currentUserID(ctx)
+	rule := &automationmodel.AutomationRule{
+		ProjectID: projectID,
+		CreatedBy: &userID,
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule mengganti seluruh isi rule, execution lama tetap tersimpan
+func (s *automationService) UpdateRule(ctx *gin.Context) (*automationmodel.AutomationRule, error) {
+	rule, err := s.loadRule(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req automationmodel.RuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *automationService) DeleteRule(ctx *gin.Context) error {
+	rule, err := s.loadRule(ctx, true)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteRule(rule.ID)
+}
+
+// GetRuleExecutions mengembalikan execution terbaru lebih dulu, query limit default 50 dan maksimal 200
+func (s *automationService) GetRuleExecutions(ctx *gin.Context) ([]automationmodel.AutomationExecution, error) {
+	rule, err := s.loadRule(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 50
+	if value := ctx.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("limit harus berupa angka positif")
+		}
+		if limit > 200 {
+			limit = 200
+		}
+	}
+
+	return s.repo.GetRuleExecutions(rule.ID, limit)
+}
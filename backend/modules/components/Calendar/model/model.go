@@ -0,0 +1,30 @@
+package calendarmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarToken memberi akses baca ke feed iCalendar tanpa login. Token asli hanya ditampilkan sekali saat dibuat,
+// yang disimpan hanya hash-nya. ProjectID kosong berarti feed task yang di-assign ke user
+type CalendarToken struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	ProjectID  *uuid.UUID `json:"project_id" gorm:"type:uuid"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+func (CalendarToken) TableName() string {
+	return "calendar_tokens"
+}
+
+// CalendarFeed adalah token baru beserta url feed yang bisa langsung dipasang di aplikasi kalender
+type CalendarFeed struct {
+	Token     string     `json:"token"`
+	FeedURL   string     `json:"feed_url"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,93 @@
+package calendarrepository
+
+import (
+	calendarmodel "gintugas/modules/components/Calendar/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CalendarRepository interface {
+	// ReplaceToken menghapus token lama user untuk feed yang sama lalu menyimpan token baru
+	ReplaceToken(token *calendarmodel.CalendarToken) error
+	// DeleteToken mengembalikan false jika feed belum punya token
+	DeleteToken(userID uuid.UUID, projectID *uuid.UUID) (bool, error)
+	GetTokenByHash(hash string) (*calendarmodel.CalendarToken, error)
+	TouchToken(id uuid.UUID) error
+
+	// GetUserTasks dan GetProjectTasks hanya mengambil task aktif yang punya due date
+	GetUserTasks(userID uuid.UUID) ([]taskmodel.Task, error)
+	GetProjectTasks(projectID uuid.UUID) ([]taskmodel.Task, error)
+}
+
+type calendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepository{db: db}
+}
+
+func feedCondition(db *gorm.DB, userID uuid.UUID, projectID *uuid.UUID) *gorm.DB {
+	if projectID == nil {
+		return db.Where("user_id = ? AND project_id IS NULL", userID)
+	}
+	return db.Where("user_id = ? AND project_id = ?", userID, *projectID)
+}
+
+func (r *calendarRepository) ReplaceToken(token *calendarmodel.CalendarToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := feedCondition(tx, token.UserID, token.ProjectID).Delete(&calendarmodel.CalendarToken{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *calendarRepository) DeleteToken(userID uuid.UUID, projectID *uuid.UUID) (bool, error) {
+	result := feedCondition(r.db, userID, projectID).Delete(&calendarmodel.CalendarToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *calendarRepository) GetTokenByHash(hash string) (*calendarmodel.CalendarToken, error) {
+	var token calendarmodel.CalendarToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *calendarRepository) TouchToken(id uuid.UUID) error {
+	return r.db.Model(&calendarmodel.CalendarToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+// activeDueTasks: task di trash tidak ikut karena soft delete, task dan project yang diarsipkan dicek sendiri
+func (r *calendarRepository) activeDueTasks() *gorm.DB {
+	return r.db.Model(&taskmodel.Task{}).
+		Where("tasks.due_date IS NOT NULL AND tasks.archived_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL)").
+		Preload("Project").
+		Order("tasks.due_date ASC, tasks.id ASC")
+}
+
+func (r *calendarRepository) GetUserTasks(userID uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.activeDueTasks().
+		Where("EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = ?)", userID).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *calendarRepository) GetProjectTasks(projectID uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.activeDueTasks().
+		Where("tasks.project_id = ?", projectID).
+		Preload("Assignee").
+		Find(&tasks).Error
+	return tasks, err
+}
@@ -0,0 +1,126 @@
+package calendarservice
+
+import (
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// komponen iCalendar untuk setiap task
+const (
+	ComponentEvent = "event"
+	ComponentTodo  = "todo"
+)
+
+const icsTimestamp = "20060102T150405Z"
+
+// icsWriter menulis baris iCalendar (RFC 5545): diakhiri CRLF dan dilipat setiap 75 oktet
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (i *icsWriter) line(name, value string) {
+	if i.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	var folded strings.Builder
+	// baris lanjutan diawali spasi, jadi isinya hanya boleh 74 oktet
+	limit := 75
+	for len(content) > limit {
+		// potong di batas karakter UTF-8 supaya tidak merusak huruf multi-byte
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		folded.WriteString(content[:cut])
+		folded.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74
+	}
+	folded.WriteString(content)
+	folded.WriteString("\r\n")
+
+	_, i.err = io.WriteString(i.w, folded.String())
+}
+
+// escapeText meng-escape nilai TEXT sesuai RFC 5545 bagian 3.3.11
+func escapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// writeCalendar menulis satu VCALENDAR berisi satu VEVENT (acara sehari penuh di due date) atau VTODO per task
+func writeCalendar(w io.Writer, name, component string, tasks []taskmodel.Task) error {
+	ics := &icsWriter{w: w}
+	ics.line("BEGIN", "VCALENDAR")
+	ics.line("VERSION", "2.0")
+	ics.line("PRODID", "-//gintugas//Task Calendar//ID")
+	ics.line("CALSCALE", "GREGORIAN")
+	ics.line("METHOD", "PUBLISH")
+	ics.line("X-WR-CALNAME", escapeText(name))
+	// aplikasi kalender diminta mengambil ulang feed setiap jam
+	ics.line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	ics.line("X-PUBLISHED-TTL", "PT1H")
+
+	for i := range tasks {
+		writeTask(ics, component, &tasks[i])
+	}
+
+	ics.line("END", "VCALENDAR")
+	return ics.err
+}
+
+func writeTask(ics *icsWriter, component string, task *taskmodel.Task) {
+	due := *task.DueDate
+	description := fmt.Sprintf("Project: %s\nStatus: %s", task.Project.Nama, task.Status)
+	if task.Assignee != nil {
+		description += "\nAssignee: " + task.Assignee.Username
+	}
+	if task.Description != "" {
+		description += "\n\n" + task.Description
+	}
+
+	name := "VEVENT"
+	if component == ComponentTodo {
+		name = "VTODO"
+	}
+
+	ics.line("BEGIN", name)
+	ics.line("UID", task.ID.String()+"@gintugas")
+	ics.line("DTSTAMP", task.UpdatedAt.UTC().Format(icsTimestamp))
+	ics.line("LAST-MODIFIED", task.UpdatedAt.UTC().Format(icsTimestamp))
+	// SEQUENCE ikut version task supaya aplikasi kalender mengganti salinan lamanya
+	ics.line("SEQUENCE", fmt.Sprint(task.Version-1))
+	ics.line("SUMMARY", escapeText(task.Title))
+	ics.line("DESCRIPTION", escapeText(description))
+	ics.line("CATEGORIES", escapeText(task.Project.Nama))
+
+	if component == ComponentTodo {
+		ics.line("DUE;VALUE=DATE", due.Format("20060102"))
+		ics.line("STATUS", todoStatus(task.Status))
+		if task.Status == "done" {
+			ics.line("COMPLETED", task.UpdatedAt.UTC().Format(icsTimestamp))
+		}
+	} else {
+		ics.line("DTSTART;VALUE=DATE", due.Format("20060102"))
+		ics.line("DTEND;VALUE=DATE", due.AddDate(0, 0, 1).Format("20060102"))
+		ics.line("TRANSP", "TRANSPARENT")
+	}
+
+	ics.line("END", name)
+}
+
+func todoStatus(status string) string {
+	switch status {
+	case "done":
+		return "COMPLETED"
+	case "todo":
+		return "NEEDS-ACTION"
+	default:
+		return "IN-PROCESS"
+	}
+}
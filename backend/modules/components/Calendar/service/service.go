@@ -0,0 +1,212 @@
+package calendarservice
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	calendarmodel "gintugas/modules/components/Calendar/model"
+	calendarrepository "gintugas/modules/components/Calendar/repository"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ErrFeedNotFound dikembalikan untuk token yang tidak dikenal atau sudah dicabut
+var ErrFeedNotFound = errors.New("feed kalender tidak ditemukan")
+
+type CalendarService interface {
+	CreateUserFeed(ctx *gin.Context) (*calendarmodel.CalendarFeed, error)
+	RevokeUserFeed(ctx *gin.Context) error
+	CreateProjectFeed(ctx *gin.Context) (*calendarmodel.CalendarFeed, error)
+	RevokeProjectFeed(ctx *gin.Context) error
+	// GetFeed membuat isi feed iCalendar dari token di path, tanpa login
+	GetFeed(ctx *gin.Context) ([]byte, error)
+}
+
+type calendarService struct {
+	repo     calendarrepository.CalendarRepository
+	taskRepo taskrepository.TaskRepository
+}
+
+func NewCalendarService(repo calendarrepository.CalendarRepository, taskRepo taskrepository.TaskRepository) CalendarService {
+	return &calendarService{
+		repo:     repo,
+		taskRepo: taskRepo,
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+func (s *calendarService) validateProjectManager(ctx *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("Gagal format project ID")
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectUUID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ManagerID != userUUID {
+		return uuid.Nil, uuid.Nil, errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	return userUUID, projectUUID, nil
+}
+
+// hashToken: token disimpan sebagai SHA-256 supaya isi database tidak bisa langsung dipakai membuka feed
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// feedURL memakai host request karena url feed dipasang langsung di aplikasi kalender
+func feedURL(ctx *gin.Context, token string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || strings.EqualFold(ctx.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/api/calendar/%s.ics", scheme, ctx.Request.Host, token)
+}
+
+// createFeed membuat token baru dan mencabut token lama untuk feed yang sama
+func (s *calendarService) createFeed(ctx *gin.Context, userID uuid.UUID, projectID *uuid.UUID) (*calendarmodel.CalendarFeed, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	record := &calendarmodel.CalendarToken{
+		UserID:    userID,
+		ProjectID: projectID,
+		TokenHash: hashToken(token),
+	}
+	if err := s.repo.ReplaceToken(record); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan token kalender: %v", err)
+	}
+
+	return &calendarmodel.CalendarFeed{
+		Token:     token,
+		FeedURL:   feedURL(ctx, token),
+		ProjectID: projectID,
+		CreatedAt: record.CreatedAt,
+	}, nil
+}
+
+// CreateUserFeed membuat (atau mengganti) token feed due date task yang di-assign ke user
+func (s *calendarService) CreateUserFeed(ctx *gin.Context) (*calendarmodel.CalendarFeed, error) {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.createFeed(ctx, userUUID, nil)
+}
+
+func (s *calendarService) RevokeUserFeed(ctx *gin.Context) error {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	return s.revokeFeed(userUUID, nil)
+}
+
+// CreateProjectFeed membuat (atau mengganti) token feed due date semua task project, hanya untuk manager project
+func (s *calendarService) CreateProjectFeed(ctx *gin.Context) (*calendarmodel.CalendarFeed, error) {
+	userUUID, projectUUID, err := s.validateProjectManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.createFeed(ctx, userUUID, &projectUUID)
+}
+
+func (s *calendarService) RevokeProjectFeed(ctx *gin.Context) error {
+	userUUID, projectUUID, err := s.validateProjectManager(ctx)
+	if err != nil {
+		return err
+	}
+	return s.revokeFeed(userUUID, &projectUUID)
+}
+
+func (s *calendarService) revokeFeed(userID uuid.UUID, projectID *uuid.UUID) error {
+	deleted, err := s.repo.DeleteToken(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+// GetFeed selalu membaca task terbaru sehingga perubahan task langsung terlihat saat kalender mengambil ulang feed.
+// type=event (default) menghasilkan VEVENT sehari penuh, type=todo menghasilkan VTODO
+func (s *calendarService) GetFeed(ctx *gin.Context) ([]byte, error) {
+	component := ctx.DefaultQuery("type", ComponentEvent)
+	if component != ComponentEvent && component != ComponentTodo {
+		return nil, errors.New("type harus bernilai event atau todo")
+	}
+
+	token := strings.TrimSuffix(ctx.Param("token"), ".ics")
+	record, err := s.repo.GetTokenByHash(hashToken(token))
+	if err != nil {
+		return nil, ErrFeedNotFound
+	}
+
+	var name string
+	var tasks []taskmodel.Task
+	if record.ProjectID == nil {
+		user, err := s.taskRepo.GetUserByID(record.UserID)
+		if err != nil {
+			return nil, ErrFeedNotFound
+		}
+		name = "gintugas - " + user.Username
+		tasks, err = s.repo.GetUserTasks(record.UserID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// feed project ikut tidak berlaku jika pemilik token bukan lagi manager project
+		project, err := s.taskRepo.GetProjectByID(*record.ProjectID)
+		if err != nil || project.ManagerID != record.UserID {
+			return nil, ErrFeedNotFound
+		}
+		name = "gintugas - " + project.Nama
+		tasks, err = s.repo.GetProjectTasks(project.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.TouchToken(record.ID); err != nil {
+		fmt.Printf("Gagal memperbarui pemakaian token kalender: %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCalendar(&buf, name, component, tasks); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+package concurrency
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrPreconditionFailed dikembalikan ketika versi data yang dikirim client sudah tidak sama dengan database
+var ErrPreconditionFailed = errors.New("precondition failed: data sudah diubah oleh user lain, muat ulang data lalu coba lagi")
+
+// ETag mengubah nomor versi menjadi nilai header ETag
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag menulis header ETag ke response
+func SetETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", ETag(version))
+}
+
+// CheckIfMatch membandingkan header If-Match dengan versi saat ini.
+// Request tanpa If-Match (atau If-Match: *) tetap diizinkan
+func CheckIfMatch(ctx *gin.Context, currentVersion int) error {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		tag = strings.TrimPrefix(tag, "W/")
+		tag = strings.Trim(tag, `"`)
+
+		version, err := strconv.Atoi(tag)
+		if err != nil {
+			return errors.New("header If-Match tidak valid")
+		}
+		if version == currentVersion {
+			return nil
+		}
+	}
+
+	return ErrPreconditionFailed
+}
@@ -12,6 +12,7 @@ type AdminTaskStats struct {
 	TotalTasks      int64 `json:"total_tasks"`
 	TodoTasks       int64 `json:"todo_tasks"`
 	InProgressTasks int64 `json:"in_progress_tasks"`
+	InReviewTasks   int64 `json:"in_review_tasks"`
 	DoneTasks       int64 `json:"done_tasks"`
 	OverdueTasks    int64 `json:"overdue_tasks"`
 }
@@ -35,6 +36,7 @@ type AdminProjectDetail struct {
 	TotalTasks      int64     `json:"total_tasks"`
 	TodoTasks       int64     `json:"todo_tasks"`
 	InProgressTasks int64     `json:"in_progress_tasks"`
+	InReviewTasks   int64     `json:"in_review_tasks"`
 	DoneTasks       int64     `json:"done_tasks"`
 	Progress        float64   `json:"progress"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -64,6 +66,7 @@ type ManagerTaskStats struct {
 	TotalTasks      int64 `json:"total_tasks"`
 	TodoTasks       int64 `json:"todo_tasks"`
 	InProgressTasks int64 `json:"in_progress_tasks"`
+	InReviewTasks   int64 `json:"in_review_tasks"`
 	DoneTasks       int64 `json:"done_tasks"`
 	OverdueTasks    int64 `json:"overdue_tasks"`
 }
@@ -87,6 +90,7 @@ type ManagerProjectDetail struct {
 	TotalTasks      int64     `json:"total_tasks"`
 	TodoTasks       int64     `json:"todo_tasks"`
 	InProgressTasks int64     `json:"in_progress_tasks"`
+	InReviewTasks   int64     `json:"in_review_tasks"`
 	DoneTasks       int64     `json:"done_tasks"`
 	Progress        float64   `json:"progress"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -102,11 +106,63 @@ type ManagerTaskDetail struct {
 	CreatedAt    time.Time  `json:"created_at"`
 }
 
+// PendingReview adalah task in-review yang menunggu keputusan manager project
+type PendingReview struct {
+	ReviewID      uuid.UUID  `json:"review_id"`
+	TaskID        uuid.UUID  `json:"task_id"`
+	TaskTitle     string     `json:"task_title"`
+	ProjectID     uuid.UUID  `json:"project_id"`
+	ProjectName   string     `json:"project_name"`
+	SubmittedBy   *uuid.UUID `json:"submitted_by"`
+	SubmitterName *string    `json:"submitter_name"`
+	Note          string     `json:"note"`
+	DueDate       *time.Time `json:"due_date"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+}
+
+// DashboardView adalah saved view yang dipasang user sebagai default dashboard, task-nya dibaca lewat /api/views/{view_id}/tasks
+type DashboardView struct {
+	ViewID      uuid.UUID         `json:"view_id"`
+	Name        string            `json:"name"`
+	Scope       string            `json:"scope"`
+	ProjectID   *uuid.UUID        `json:"project_id"`
+	ProjectName *string           `json:"project_name"`
+	Filters     map[string]string `json:"filters" gorm:"serializer:json"`
+	Sort        string            `json:"sort"`
+}
+
 type ManagerDashboardResponse struct {
 	Stats           ManagerDashboardStats  `json:"stats"`
 	MyProjects      []ManagerProjectDetail `json:"my_projects"`
 	MyProjectsTasks []ManagerTaskDetail    `json:"my_projects_tasks"`
+	PendingReviews  []PendingReview        `json:"pending_reviews"`
 	RecentActivity  []RecentActivity       `json:"recent_activity"`
+	DefaultView     *DashboardView         `json:"default_view"`
+}
+
+// TimeRange membatasi log_date catatan waktu, nil berarti tanpa batas
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// TimeProjectSummary: estimate dan remaining dihitung dari task yang punya estimasi,
+// logged mengikuti rentang tanggal yang diminta
+type TimeProjectSummary struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	EstimatedTasks   int64     `json:"estimated_tasks"`
+	EstimateMinutes  int64     `json:"estimate_minutes"`
+	LoggedMinutes    int64     `json:"logged_minutes"`
+	RemainingMinutes int64     `json:"remaining_minutes"`
+}
+
+type TimeUserSummary struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Username      string    `json:"username"`
+	TotalTasks    int64     `json:"total_tasks"`
+	TotalEntries  int64     `json:"total_entries"`
+	LoggedMinutes int64     `json:"logged_minutes"`
 }
 
 // ==================== Staff Dashboard Models ====================
@@ -115,6 +171,7 @@ type StaffTaskStats struct {
 	TotalTasks      int64 `json:"total_tasks"`
 	TodoTasks       int64 `json:"todo_tasks"`
 	InProgressTasks int64 `json:"in_progress_tasks"`
+	InReviewTasks   int64 `json:"in_review_tasks"`
 	DoneTasks       int64 `json:"done_tasks"`
 	OverdueTasks    int64 `json:"overdue_tasks"`
 }
@@ -131,6 +188,7 @@ type StaffTaskDetail struct {
 	Status      string     `json:"status"`
 	DueDate     *time.Time `json:"due_date"`
 	IsOverdue   bool       `json:"is_overdue"`
+	IsPrimary   bool       `json:"is_primary"`
 	CreatedAt   time.Time  `json:"created_at"`
 }
 
@@ -138,6 +196,7 @@ type StaffDashboardResponse struct {
 	Stats          StaffDashboardStats `json:"stats"`
 	MyTasks        []StaffTaskDetail   `json:"my_tasks"`
 	RecentActivity []RecentActivity    `json:"recent_activity"`
+	DefaultView    *DashboardView      `json:"default_view"`
 }
 
 // ==================== Shared Models ====================
@@ -150,6 +209,7 @@ type ChartData struct {
 type TaskStatusChart struct {
 	Todo       int64 `json:"todo"`
 	InProgress int64 `json:"in_progress"`
+	InReview   int64 `json:"in_review"`
 	Done       int64 `json:"done"`
 }
 
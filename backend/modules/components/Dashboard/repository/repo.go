@@ -1,6 +1,7 @@
 package dashboardrepository
 
 import (
+	"database/sql"
 	dashboardmodel "gintugas/modules/components/Dashboard/model"
 	"time"
 
@@ -22,6 +23,11 @@ type DashboardRepository interface {
 	GetManagerTaskCountByStatus(managerID uuid.UUID) (dashboardmodel.ManagerTaskStats, error)
 	GetManagerProjectCountByStatus(managerID uuid.UUID) (dashboardmodel.ManagerProjectStats, error)
 	GetManagerProjectMembers(managerID uuid.UUID) (int64, error)
+	GetManagerPendingReviews(managerID uuid.UUID, limit int) ([]dashboardmodel.PendingReview, error)
+	// GetDefaultView mengembalikan nil jika user tidak memasang view di dashboard atau view tersebut tidak lagi terlihat olehnya
+	GetDefaultView(userID uuid.UUID, dashboard string) (*dashboardmodel.DashboardView, error)
+	GetManagerTimeByProject(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeProjectSummary, error)
+	GetManagerTimeByUser(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeUserSummary, error)
 
 	GetStaffDashboardStats(staffID uuid.UUID) (*dashboardmodel.StaffDashboardStats, error)
 	GetStaffTasks(staffID uuid.UUID, limit int, offset int) ([]dashboardmodel.StaffTaskDetail, error)
@@ -40,6 +46,18 @@ func NewDashboardRepository(db *gorm.DB) DashboardRepository {
 	return &repository{db: db}
 }
 
+// tasks dan projects mengabaikan data yang ada di trash maupun yang diarsipkan. Project di trash selalu ikut memindahkan
+// task-nya ke trash, jadi query task cukup memeriksa tasks.deleted_at; arsip project tidak mengubah task sehingga perlu dicek sendiri
+func (r *repository) tasks() *gorm.DB {
+	return r.db.Table("tasks").
+		Where("tasks.deleted_at IS NULL AND tasks.archived_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL)")
+}
+
+func (r *repository) projects(alias string) *gorm.DB {
+	return r.db.Table("projects " + alias).Where(alias + ".deleted_at IS NULL AND " + alias + ".archived_at IS NULL")
+}
+
 // ==================== Admin Dashboard Methods ====================
 
 func (r *repository) GetAdminDashboardStats() (*dashboardmodel.AdminDashboardStats, error) {
@@ -69,17 +87,17 @@ func (r *repository) GetAdminDashboardStats() (*dashboardmodel.AdminDashboardSta
 func (r *repository) GetProjectCountByStatus() (dashboardmodel.AdminProjectStats, error) {
 	var stats dashboardmodel.AdminProjectStats
 
-	r.db.Table("projects").Count(&stats.TotalProjects)
+	r.projects("p").Count(&stats.TotalProjects)
 
-	r.db.Table("projects").
-		Joins("LEFT JOIN tasks ON projects.id = tasks.project_id").
-		Where("tasks.status IN ('todo', 'in-progress')").
-		Distinct("projects.id").
+	r.projects("p").
+		Joins("JOIN tasks t ON p.id = t.project_id AND t.deleted_at IS NULL AND t.archived_at IS NULL").
+		Where("t.status IN ('todo', 'in-progress', 'in-review')").
+		Distinct("p.id").
 		Count(&stats.ActiveProjects)
 
-	r.db.Table("projects p").
-		Where("NOT EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND status != 'done')").
-		Where("EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id)").
+	r.projects("p").
+		Where("NOT EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND deleted_at IS NULL AND archived_at IS NULL AND status != 'done')").
+		Where("EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND deleted_at IS NULL AND archived_at IS NULL)").
 		Count(&stats.CompletedProjects)
 
 	return stats, nil
@@ -88,13 +106,14 @@ func (r *repository) GetProjectCountByStatus() (dashboardmodel.AdminProjectStats
 func (r *repository) GetTaskCountByStatus() (dashboardmodel.AdminTaskStats, error) {
 	var stats dashboardmodel.AdminTaskStats
 
-	r.db.Table("tasks").Count(&stats.TotalTasks)
-	r.db.Table("tasks").Where("status = ?", "todo").Count(&stats.TodoTasks)
-	r.db.Table("tasks").Where("status = ?", "in-progress").Count(&stats.InProgressTasks)
-	r.db.Table("tasks").Where("status = ?", "done").Count(&stats.DoneTasks)
+	r.tasks().Count(&stats.TotalTasks)
+	r.tasks().Where("status = ?", "todo").Count(&stats.TodoTasks)
+	r.tasks().Where("status = ?", "in-progress").Count(&stats.InProgressTasks)
+	r.tasks().Where("status = ?", "in-review").Count(&stats.InReviewTasks)
+	r.tasks().Where("status = ?", "done").Count(&stats.DoneTasks)
 
 	now := time.Now()
-	r.db.Table("tasks").
+	r.tasks().
 		Where("status != ? AND due_date IS NOT NULL AND due_date < ?", "done", now).
 		Count(&stats.OverdueTasks)
 
@@ -112,6 +131,7 @@ func (r *repository) GetAllProjects(limit int, offset int) ([]dashboardmodel.Adm
 			COUNT(DISTINCT t.id) as total_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'todo' THEN t.id END) as todo_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'in-progress' THEN t.id END) as in_progress_tasks,
+			COUNT(DISTINCT CASE WHEN t.status = 'in-review' THEN t.id END) as in_review_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'done' THEN t.id END) as done_tasks,
 			CASE 
 				WHEN COUNT(DISTINCT t.id) = 0 THEN 0
@@ -120,7 +140,8 @@ func (r *repository) GetAllProjects(limit int, offset int) ([]dashboardmodel.Adm
 			p.created_at
 		FROM projects p
 		LEFT JOIN users u ON p.manager_id = u.id
-		LEFT JOIN tasks t ON p.id = t.project_id
+		LEFT JOIN tasks t ON p.id = t.project_id AND t.deleted_at IS NULL AND t.archived_at IS NULL
+		WHERE p.deleted_at IS NULL AND p.archived_at IS NULL
 		GROUP BY p.id, p.nama, u.username, p.created_at
 		ORDER BY p.created_at DESC
 		LIMIT ? OFFSET ?
@@ -150,6 +171,7 @@ func (r *repository) GetAllTasks(limit int, offset int) ([]dashboardmodel.AdminT
 		FROM tasks t
 		LEFT JOIN projects p ON t.project_id = p.id
 		LEFT JOIN users u ON t.assignee_id = u.id
+		WHERE t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL
 		ORDER BY t.created_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -165,19 +187,20 @@ func (r *repository) GetAllTasks(limit int, offset int) ([]dashboardmodel.AdminT
 func (r *repository) GetRecentActivity(limit int) ([]dashboardmodel.RecentActivity, error) {
 	var activities []dashboardmodel.RecentActivity
 
+	// sumber activity feed adalah task_history
 	query := `
 		SELECT 
-			t.id,
-			'task_update' as type,
-			u.id as user_id,
-			u.username as user_name,
-			t.title as message,
-			t.id as target_id,
+			h.id,
+			'task_' || h.action as type,
+			h.actor_id as user_id,
+			COALESCE(u.username, 'system') as user_name,
+			h.task_title as message,
+			h.task_id as target_id,
 			'task' as target_type,
-			t.updated_at as created_at
-		FROM tasks t
-		LEFT JOIN users u ON t.assignee_id = u.id
-		ORDER BY t.updated_at DESC
+			h.created_at
+		FROM task_history h
+		LEFT JOIN users u ON h.actor_id = u.id
+		ORDER BY h.created_at DESC
 		LIMIT ?
 	`
 
@@ -192,7 +215,7 @@ func (r *repository) GetRecentActivity(limit int) ([]dashboardmodel.RecentActivi
 func (r *repository) GetOverdueTasks() (int64, error) {
 	var count int64
 	now := time.Now()
-	err := r.db.Table("tasks").
+	err := r.tasks().
 		Where("status != ? AND due_date IS NOT NULL AND due_date < ?", "done", now).
 		Count(&count).Error
 	return count, err
@@ -226,18 +249,18 @@ func (r *repository) GetManagerDashboardStats(managerID uuid.UUID) (*dashboardmo
 func (r *repository) GetManagerProjectCountByStatus(managerID uuid.UUID) (dashboardmodel.ManagerProjectStats, error) {
 	var stats dashboardmodel.ManagerProjectStats
 
-	r.db.Table("projects").Where("manager_id = ?", managerID).Count(&stats.TotalProjects)
+	r.projects("p").Where("p.manager_id = ?", managerID).Count(&stats.TotalProjects)
 
-	r.db.Table("projects p").
-		Joins("LEFT JOIN tasks t ON p.id = t.project_id").
-		Where("p.manager_id = ? AND t.status IN ('todo', 'in-progress')", managerID).
+	r.projects("p").
+		Joins("JOIN tasks t ON p.id = t.project_id AND t.deleted_at IS NULL AND t.archived_at IS NULL").
+		Where("p.manager_id = ? AND t.status IN ('todo', 'in-progress', 'in-review')", managerID).
 		Distinct("p.id").
 		Count(&stats.ActiveProjects)
 
-	r.db.Table("projects p").
+	r.projects("p").
 		Where("p.manager_id = ?", managerID).
-		Where("NOT EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND status != 'done')").
-		Where("EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id)").
+		Where("NOT EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND deleted_at IS NULL AND archived_at IS NULL AND status != 'done')").
+		Where("EXISTS (SELECT 1 FROM tasks WHERE project_id = p.id AND deleted_at IS NULL AND archived_at IS NULL)").
 		Count(&stats.CompletedProjects)
 
 	return stats, nil
@@ -248,27 +271,38 @@ func (r *repository) GetManagerTaskCountByStatus(managerID uuid.UUID) (dashboard
 
 	r.db.Table("tasks t").
 		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
 		Where("p.manager_id = ?", managerID).
 		Count(&stats.TotalTasks)
 
 	r.db.Table("tasks t").
 		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
 		Where("p.manager_id = ? AND t.status = ?", managerID, "todo").
 		Count(&stats.TodoTasks)
 
 	r.db.Table("tasks t").
 		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
 		Where("p.manager_id = ? AND t.status = ?", managerID, "in-progress").
 		Count(&stats.InProgressTasks)
 
 	r.db.Table("tasks t").
 		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
+		Where("p.manager_id = ? AND t.status = ?", managerID, "in-review").
+		Count(&stats.InReviewTasks)
+
+	r.db.Table("tasks t").
+		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
 		Where("p.manager_id = ? AND t.status = ?", managerID, "done").
 		Count(&stats.DoneTasks)
 
 	now := time.Now()
 	r.db.Table("tasks t").
 		Joins("JOIN projects p ON t.project_id = p.id").
+		Where("t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL").
 		Where("p.manager_id = ? AND t.status != ? AND t.due_date IS NOT NULL AND t.due_date < ?", managerID, "done", now).
 		Count(&stats.OverdueTasks)
 
@@ -279,7 +313,7 @@ func (r *repository) GetManagerProjectMembers(managerID uuid.UUID) (int64, error
 	var count int64
 	err := r.db.Table("project_members pm").
 		Joins("JOIN projects p ON pm.project_id = p.id").
-		Where("p.manager_id = ?", managerID).
+		Where("p.manager_id = ? AND p.deleted_at IS NULL AND p.archived_at IS NULL", managerID).
 		Distinct("pm.user_id").
 		Count(&count).Error
 	return count, err
@@ -296,6 +330,7 @@ func (r *repository) GetManagerProjects(managerID uuid.UUID, limit int, offset i
 			COUNT(DISTINCT t.id) as total_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'todo' THEN t.id END) as todo_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'in-progress' THEN t.id END) as in_progress_tasks,
+			COUNT(DISTINCT CASE WHEN t.status = 'in-review' THEN t.id END) as in_review_tasks,
 			COUNT(DISTINCT CASE WHEN t.status = 'done' THEN t.id END) as done_tasks,
 			CASE 
 				WHEN COUNT(DISTINCT t.id) = 0 THEN 0
@@ -304,8 +339,8 @@ func (r *repository) GetManagerProjects(managerID uuid.UUID, limit int, offset i
 			p.created_at
 		FROM projects p
 		LEFT JOIN project_members pm ON p.id = pm.project_id
-		LEFT JOIN tasks t ON p.id = t.project_id
-		WHERE p.manager_id = ?
+		LEFT JOIN tasks t ON p.id = t.project_id AND t.deleted_at IS NULL AND t.archived_at IS NULL
+		WHERE p.manager_id = ? AND p.deleted_at IS NULL AND p.archived_at IS NULL
 		GROUP BY p.id, p.nama, p.created_at
 		ORDER BY p.created_at DESC
 		LIMIT ? OFFSET ?
@@ -334,7 +369,7 @@ func (r *repository) GetManagerProjectsTasks(managerID uuid.UUID, limit int, off
 		FROM tasks t
 		LEFT JOIN projects p ON t.project_id = p.id
 		LEFT JOIN users u ON t.assignee_id = u.id
-		WHERE p.manager_id = ?
+		WHERE p.manager_id = ? AND t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL
 		ORDER BY t.created_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -347,17 +382,169 @@ func (r *repository) GetManagerProjectsTasks(managerID uuid.UUID, limit int, off
 	return tasks, nil
 }
 
+// GetManagerPendingReviews mengambil review pending di project manager, yang paling lama menunggu lebih dulu
+func (r *repository) GetManagerPendingReviews(managerID uuid.UUID, limit int) ([]dashboardmodel.PendingReview, error) {
+	reviews := []dashboardmodel.PendingReview{}
+
+	query := `
+		SELECT 
+			tr.id as review_id,
+			t.id as task_id,
+			t.title as task_title,
+			p.id as project_id,
+			p.nama as project_name,
+			tr.submitted_by,
+			u.username as submitter_name,
+			COALESCE(tr.note, '') as note,
+			t.due_date,
+			tr.submitted_at
+		FROM task_reviews tr
+		JOIN tasks t ON tr.task_id = t.id
+		JOIN projects p ON t.project_id = p.id
+		LEFT JOIN users u ON tr.submitted_by = u.id
+		WHERE tr.decision = 'pending' AND p.manager_id = ?
+			AND t.deleted_at IS NULL AND t.archived_at IS NULL AND p.deleted_at IS NULL AND p.archived_at IS NULL
+		ORDER BY tr.submitted_at ASC
+		LIMIT ?
+	`
+
+	err := r.db.Raw(query, managerID, limit).Scan(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *repository) GetDefaultView(userID uuid.UUID, dashboard string) (*dashboardmodel.DashboardView, error) {
+	views := []dashboardmodel.DashboardView{}
+
+	query := `
+		SELECT
+			sv.id as view_id,
+			sv.name,
+			sv.scope,
+			sv.project_id,
+			p.nama as project_name,
+			sv.filters,
+			sv.sort
+		FROM saved_view_pins pin
+		JOIN saved_views sv ON pin.view_id = sv.id
+		LEFT JOIN projects p ON sv.project_id = p.id
+		WHERE pin.user_id = @user AND pin.dashboard = @dashboard
+			AND (sv.project_id IS NULL OR (p.deleted_at IS NULL
+				AND (p.manager_id = @user OR EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = @user))))
+			AND (sv.owner_id = @user OR sv.visibility = 'shared')
+		LIMIT 1
+	`
+
+	err := r.db.Raw(query, sql.Named("user", userID), sql.Named("dashboard", dashboard)).Scan(&views).Error
+	if err != nil || len(views) == 0 {
+		return nil, err
+	}
+	return &views[0], nil
+}
+
+// timeRangeFilter menyusun kondisi log_date untuk alias tabel time_logs
+func timeRangeFilter(alias string, period dashboardmodel.TimeRange) (string, []interface{}) {
+	condition := ""
+	args := []interface{}{}
+	if period.From != nil {
+		condition += " AND " + alias + ".log_date >= ?"
+		args = append(args, *period.From)
+	}
+	if period.To != nil {
+		condition += " AND " + alias + ".log_date <= ?"
+		args = append(args, *period.To)
+	}
+	return condition, args
+}
+
+func (r *repository) GetManagerTimeByProject(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeProjectSummary, error) {
+	var summaries []dashboardmodel.TimeProjectSummary
+
+	periodFilter, args := timeRangeFilter("tl", period)
+
+	// remaining memakai semua catatan waktu, bukan hanya yang ada di rentang tanggal
+	query := `
+		SELECT 
+			p.id,
+			p.nama as name,
+			COUNT(t.original_estimate_minutes) as estimated_tasks,
+			COALESCE(SUM(t.original_estimate_minutes), 0) as estimate_minutes,
+			COALESCE(SUM(logged.minutes), 0) as logged_minutes,
+			COALESCE(SUM(GREATEST(t.original_estimate_minutes - COALESCE(spent.minutes, 0), 0)), 0) as remaining_minutes
+		FROM projects p
+		LEFT JOIN tasks t ON p.id = t.project_id AND t.deleted_at IS NULL AND t.archived_at IS NULL
+		LEFT JOIN (
+			SELECT tl.task_id, SUM(tl.duration_minutes) as minutes
+			FROM time_logs tl
+			GROUP BY tl.task_id
+		) spent ON spent.task_id = t.id
+		LEFT JOIN (
+			SELECT tl.task_id, SUM(tl.duration_minutes) as minutes
+			FROM time_logs tl
+			WHERE true` + periodFilter + `
+			GROUP BY tl.task_id
+		) logged ON logged.task_id = t.id
+		WHERE p.manager_id = ? AND p.deleted_at IS NULL AND p.archived_at IS NULL
+		GROUP BY p.id, p.nama, p.created_at
+		ORDER BY p.created_at DESC
+	`
+
+	args = append(args, managerID)
+	err := r.db.Raw(query, args...).Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func (r *repository) GetManagerTimeByUser(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeUserSummary, error) {
+	var summaries []dashboardmodel.TimeUserSummary
+
+	periodFilter, periodArgs := timeRangeFilter("tl", period)
+
+	query := `
+		SELECT 
+			u.id as user_id,
+			u.username,
+			COUNT(DISTINCT tl.task_id) as total_tasks,
+			COUNT(tl.id) as total_entries,
+			COALESCE(SUM(tl.duration_minutes), 0) as logged_minutes
+		FROM time_logs tl
+		JOIN tasks t ON tl.task_id = t.id
+		JOIN projects p ON t.project_id = p.id
+		JOIN users u ON tl.user_id = u.id
+		WHERE p.manager_id = ? AND t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL` + periodFilter + `
+		GROUP BY u.id, u.username
+		ORDER BY logged_minutes DESC, u.username
+	`
+
+	args := append([]interface{}{managerID}, periodArgs...)
+	err := r.db.Raw(query, args...).Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
 func (r *repository) GetOverdueTasksByUser(userID uuid.UUID) (int64, error) {
 	var count int64
 	now := time.Now()
-	err := r.db.Table("tasks").
-		Where("assignee_id = ? AND status != ? AND due_date IS NOT NULL AND due_date < ?", userID, "done", now).
+	err := r.tasks().
+		Where(staffAssigned+" AND status != ? AND due_date IS NOT NULL AND due_date < ?", userID, "done", now).
 		Count(&count).Error
 	return count, err
 }
 
 // ==================== Staff Dashboard Methods ====================
 
+// staffAssigned mencocokkan task yang dikerjakan staff, baik sebagai assignee utama maupun tambahan
+const staffAssigned = "EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = ?)"
+
 func (r *repository) GetStaffDashboardStats(staffID uuid.UUID) (*dashboardmodel.StaffDashboardStats, error) {
 	taskStats, err := r.GetStaffTaskCountByStatus(staffID)
 	if err != nil {
@@ -378,14 +565,15 @@ func (r *repository) GetStaffDashboardStats(staffID uuid.UUID) (*dashboardmodel.
 func (r *repository) GetStaffTaskCountByStatus(staffID uuid.UUID) (dashboardmodel.StaffTaskStats, error) {
 	var stats dashboardmodel.StaffTaskStats
 
-	r.db.Table("tasks").Where("assignee_id = ?", staffID).Count(&stats.TotalTasks)
-	r.db.Table("tasks").Where("assignee_id = ? AND status = ?", staffID, "todo").Count(&stats.TodoTasks)
-	r.db.Table("tasks").Where("assignee_id = ? AND status = ?", staffID, "in-progress").Count(&stats.InProgressTasks)
-	r.db.Table("tasks").Where("assignee_id = ? AND status = ?", staffID, "done").Count(&stats.DoneTasks)
+	r.tasks().Where(staffAssigned, staffID).Count(&stats.TotalTasks)
+	r.tasks().Where(staffAssigned+" AND status = ?", staffID, "todo").Count(&stats.TodoTasks)
+	r.tasks().Where(staffAssigned+" AND status = ?", staffID, "in-progress").Count(&stats.InProgressTasks)
+	r.tasks().Where(staffAssigned+" AND status = ?", staffID, "in-review").Count(&stats.InReviewTasks)
+	r.tasks().Where(staffAssigned+" AND status = ?", staffID, "done").Count(&stats.DoneTasks)
 
 	now := time.Now()
-	r.db.Table("tasks").
-		Where("assignee_id = ? AND status != ? AND due_date IS NOT NULL AND due_date < ?", staffID, "done", now).
+	r.tasks().
+		Where(staffAssigned+" AND status != ? AND due_date IS NOT NULL AND due_date < ?", staffID, "done", now).
 		Count(&stats.OverdueTasks)
 
 	return stats, nil
@@ -393,9 +581,9 @@ func (r *repository) GetStaffTaskCountByStatus(staffID uuid.UUID) (dashboardmode
 
 func (r *repository) GetStaffProjectCount(staffID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Table("projects p").
-		Joins("JOIN tasks t ON p.id = t.project_id").
-		Where("t.assignee_id = ?", staffID).
+	err := r.projects("p").
+		Joins("JOIN tasks ON p.id = tasks.project_id AND tasks.deleted_at IS NULL AND tasks.archived_at IS NULL").
+		Where(staffAssigned, staffID).
 		Distinct("p.id").
 		Count(&count).Error
 	return count, err
@@ -412,10 +600,12 @@ func (r *repository) GetStaffTasks(staffID uuid.UUID, limit int, offset int) ([]
 			t.status,
 			t.due_date,
 			CASE WHEN t.status != 'done' AND t.due_date IS NOT NULL AND t.due_date < NOW() THEN true ELSE false END as is_overdue,
+			ta.is_primary,
 			t.created_at
 		FROM tasks t
+		JOIN task_assignees ta ON ta.task_id = t.id AND ta.user_id = ?
 		LEFT JOIN projects p ON t.project_id = p.id
-		WHERE t.assignee_id = ?
+		WHERE t.deleted_at IS NULL AND t.archived_at IS NULL AND p.archived_at IS NULL
 		ORDER BY t.created_at DESC
 		LIMIT ? OFFSET ?
 	`
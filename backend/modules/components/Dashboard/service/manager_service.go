@@ -11,6 +11,8 @@ type ManagerDashboardService interface {
 	GetManagerDashboard(managerID uuid.UUID) (*dashboardmodel.ManagerDashboardResponse, error)
 	GetManagerProjects(managerID uuid.UUID, limit int, offset int) ([]dashboardmodel.ManagerProjectDetail, error)
 	GetManagerProjectsTasks(managerID uuid.UUID, limit int, offset int) ([]dashboardmodel.ManagerTaskDetail, error)
+	GetManagerTimeByProject(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeProjectSummary, error)
+	GetManagerTimeByUser(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeUserSummary, error)
 }
 
 type managerDashboardService struct {
@@ -40,16 +42,28 @@ func (s *managerDashboardService) GetManagerDashboard(managerID uuid.UUID) (*das
 		return nil, err
 	}
 
+	reviews, err := s.repo.GetManagerPendingReviews(managerID, 10)
+	if err != nil {
+		return nil, err
+	}
+
 	activity, err := s.repo.GetRecentActivity(10)
 	if err != nil {
 		return nil, err
 	}
 
+	defaultView, err := s.repo.GetDefaultView(managerID, "manager")
+	if err != nil {
+		return nil, err
+	}
+
 	return &dashboardmodel.ManagerDashboardResponse{
 		Stats:           *stats,
 		MyProjects:      projects,
 		MyProjectsTasks: tasks,
+		PendingReviews:  reviews,
 		RecentActivity:  activity,
+		DefaultView:     defaultView,
 	}, nil
 }
 
@@ -60,3 +74,11 @@ func (s *managerDashboardService) GetManagerProjects(managerID uuid.UUID, limit
 func (s *managerDashboardService) GetManagerProjectsTasks(managerID uuid.UUID, limit int, offset int) ([]dashboardmodel.ManagerTaskDetail, error) {
 	return s.repo.GetManagerProjectsTasks(managerID, limit, offset)
 }
+
+func (s *managerDashboardService) GetManagerTimeByProject(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeProjectSummary, error) {
+	return s.repo.GetManagerTimeByProject(managerID, period)
+}
+
+func (s *managerDashboardService) GetManagerTimeByUser(managerID uuid.UUID, period dashboardmodel.TimeRange) ([]dashboardmodel.TimeUserSummary, error) {
+	return s.repo.GetManagerTimeByUser(managerID, period)
+}
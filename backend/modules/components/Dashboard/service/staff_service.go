@@ -39,10 +39,16 @@ func (s *staffDashboardService) GetStaffDashboard(staffID uuid.UUID) (*dashboard
 		return nil, err
 	}
 
+	defaultView, err := s.repo.GetDefaultView(staffID, "staff")
+	if err != nil {
+		return nil, err
+	}
+
 	return &dashboardmodel.StaffDashboardResponse{
 		Stats:          *stats,
 		MyTasks:        tasks,
 		RecentActivity: activity,
+		DefaultView:    defaultView,
 	}, nil
 }
 
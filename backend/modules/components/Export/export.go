@@ -0,0 +1,176 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format adalah jenis file export
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	JSON Format = "json"
+)
+
+// ParseFormat membaca query parameter format, nilai kosong berarti CSV
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "", CSV:
+		return CSV, nil
+	case XLSX:
+		return XLSX, nil
+	case JSON:
+		return JSON, nil
+	default:
+		return CSV, errors.New("format harus bernilai csv, xlsx atau json")
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case JSON:
+		return "application/json"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Record adalah satu baris export. Row dipakai CSV dan XLSX, JSON memakai hasil json.Marshal record
+type Record interface {
+	Row() []string
+}
+
+// Layout menjelaskan isi file: Header dan Sheet untuk CSV/XLSX, Meta dan Key untuk JSON.
+// File JSON berbentuk satu objek berisi field Meta ditambah array record di Key
+type Layout struct {
+	Header []string
+	Sheet  string
+	Meta   map[string]interface{}
+	Key    string
+}
+
+// Writer menulis record satu per satu ke w tanpa menyimpan semuanya di memori.
+// Flush mengirim data yang masih dibuffer, Close menutup struktur file dan wajib dipanggil di akhir
+type Writer interface {
+	Write(record Record) error
+	Flush() error
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer, layout Layout) (Writer, error) {
+	switch format {
+	case XLSX:
+		return newXLSXWriter(w, layout)
+	case JSON:
+		return newJSONWriter(w, layout)
+	default:
+		return newCSVWriter(w, layout)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, layout Layout) (Writer, error) {
+	// BOM supaya Excel membaca file sebagai UTF-8
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	writer := &csvWriter{writer: csv.NewWriter(w)}
+	if err := writer.writer.Write(layout.Header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (c *csvWriter) Write(record Record) error {
+	row := record.Row()
+	for i, value := range row {
+		row[i] = escapeFormula(value)
+	}
+	return c.writer.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+// escapeFormula mencegah isi sel dijalankan sebagai formula saat CSV dibuka di spreadsheet, angka negatif dibiarkan
+func escapeFormula(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type jsonWriter struct {
+	writer *bufio.Writer
+	count  int
+}
+
+func newJSONWriter(w io.Writer, layout Layout) (Writer, error) {
+	meta := layout.Meta
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	head, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	key, err := json.Marshal(layout.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	// objek meta dibuka kembali supaya array record bisa ditulis bertahap
+	writer := &jsonWriter{writer: bufio.NewWriter(w)}
+	writer.writer.Write(head[:len(head)-1])
+	if len(meta) > 0 {
+		writer.writer.WriteByte(',')
+	}
+	writer.writer.Write(key)
+	_, err = writer.writer.WriteString(":[")
+	return writer, err
+}
+
+func (j *jsonWriter) Write(record Record) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		j.writer.WriteByte(',')
+	}
+	j.count++
+	_, err = j.writer.Write(raw)
+	return err
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.writer.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	if _, err := j.writer.WriteString("]}"); err != nil {
+		return err
+	}
+	return j.Flush()
+}
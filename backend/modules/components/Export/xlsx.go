@@ -0,0 +1,134 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// bagian workbook yang isinya tetap, sheet ditulis terakhir supaya barisnya bisa dialirkan langsung ke zip
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	xlsxSheetHead = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetTail = `</sheetData></worksheet>`
+)
+
+// xlsxWriter menulis workbook minimal berisi satu sheet dengan sel inline string, tanpa library tambahan
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer, layout Layout) (Writer, error) {
+	sheetName := layout.Sheet
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheetName))
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(sheet)}
+	writer.sheet.WriteString(xlsxSheetHead)
+	if err := writer.writeRow(layout.Header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (x *xlsxWriter) Write(record Record) error {
+	return x.writeRow(record.Row())
+}
+
+func (x *xlsxWriter) writeRow(values []string) error {
+	x.rows++
+	x.sheet.WriteString(`<row r="` + strconv.Itoa(x.rows) + `">`)
+	for _, value := range values {
+		if isXLSXNumber(value) {
+			x.sheet.WriteString(`<c><v>` + value + `</v></c>`)
+			continue
+		}
+		x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(x.sheet, []byte(value)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+// isXLSXNumber: bilangan bulat ditulis sebagai angka, kecuali yang diawali 0 supaya kode seperti 007 tidak berubah
+func isXLSXNumber(value string) bool {
+	if value == "" || len(value) > 15 || (len(value) > 1 && value[0] == '0') {
+		return false
+	}
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+// Flush hanya mengosongkan buffer sheet, zip baru lengkap setelah Close
+func (x *xlsxWriter) Flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetTail); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
@@ -0,0 +1,117 @@
+package importmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// format file yang bisa diimport
+const (
+	SourceCSV    = "csv"
+	SourceTrello = "trello"
+	SourceJira   = "jira"
+)
+
+// field task yang bisa dipetakan dari kolom CSV
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldStatus      = "status"
+	FieldAssignee    = "assignee"
+	FieldDueDate     = "due_date"
+)
+
+// ImportRequest dikirim sebagai multipart form bersama file export di field "file"
+type ImportRequest struct {
+	Source string `form:"source" binding:"required,oneof=csv trello jira"`
+	// Mapping berisi JSON ImportMapping, boleh kosong untuk memakai tebakan otomatis
+	Mapping string `form:"mapping"`
+	DryRun  bool   `form:"dry_run"`
+}
+
+// ImportMapping memetakan data sumber ke gintugas. Semua map boleh sebagian, yang tidak dipetakan ditebak otomatis
+type ImportMapping struct {
+	// Columns: field task (title, description, status, assignee, due_date) -> header kolom CSV, tidak dipakai untuk Trello
+	Columns map[string]string `json:"columns"`
+	// Statuses: status Jira/CSV atau nama list Trello -> todo, in-progress, in-review, done
+	Statuses map[string]string `json:"statuses"`
+	// DefaultStatus dipakai untuk status sumber yang tidak bisa ditebak, default todo
+	DefaultStatus string `json:"default_status"`
+	// Users: nama, username atau account id sumber -> email user gintugas. Nilai sumber yang sudah berupa email dicocokkan langsung
+	Users map[string]string `json:"users"`
+	// AddMembers menambahkan user yang cocok tetapi belum menjadi member project, jika false task-nya dibuat tanpa assignee
+	AddMembers bool `json:"add_members"`
+}
+
+// Record adalah satu task hasil parsing file sebelum dipetakan ke gintugas
+type Record struct {
+	Row         int
+	Title       string
+	Description string
+	Status      string
+	// Assignees berisi user sumber, yang pertama menjadi assignee utama
+	Assignees   []string
+	DueDate     string
+	Comments    []RecordComment
+	Attachments []RecordAttachment
+}
+
+type RecordComment struct {
+	Author    string
+	Content   string
+	CreatedAt *time.Time
+}
+
+type RecordAttachment struct {
+	Name string
+	URL  string
+}
+
+// UserMatch menunjukkan user sumber yang cocok dengan user gintugas lewat email
+type UserMatch struct {
+	Source   string     `json:"source"`
+	Email    string     `json:"email,omitempty"`
+	UserID   *uuid.UUID `json:"user_id"`
+	IsMember bool       `json:"is_member"`
+}
+
+// ImportTask adalah rencana satu task. Errors membuat seluruh import ditolak, Warnings hanya informasi
+type ImportTask struct {
+	Row         int        `json:"row"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	AssigneeID  *uuid.UUID `json:"assignee_id"`
+	DueDate     *time.Time `json:"due_date"`
+	Comments    int        `json:"comments"`
+	Attachments int        `json:"attachments"`
+	Warnings    []string   `json:"warnings,omitempty"`
+	Errors      []string   `json:"errors,omitempty"`
+	// TaskID terisi setelah task benar-benar dibuat
+	TaskID *uuid.UUID `json:"task_id,omitempty"`
+}
+
+// ImportPreview menampilkan isi file dan mapping yang akan dipakai tanpa menyimpan apa pun
+type ImportPreview struct {
+	Source    string        `json:"source"`
+	Columns   []string      `json:"columns,omitempty"`
+	Statuses  []string      `json:"statuses"`
+	Mapping   ImportMapping `json:"mapping"`
+	Users     []UserMatch   `json:"users"`
+	TaskCount int           `json:"task_count"`
+	// Tasks berisi maksimal PreviewLimit task pertama
+	Tasks []ImportTask `json:"tasks"`
+}
+
+const PreviewLimit = 20
+
+type ImportResult struct {
+	DryRun bool `json:"dry_run"`
+	// Valid false berarti ada task dengan Errors dan tidak ada yang dibuat
+	Valid       bool         `json:"valid"`
+	Created     int          `json:"created"`
+	Comments    int          `json:"comments"`
+	Attachments int          `json:"attachments"`
+	Members     []uuid.UUID  `json:"members"`
+	Tasks       []ImportTask `json:"tasks"`
+}
@@ -0,0 +1,71 @@
+package importrepository
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	projectmodel "gintugas/modules/components/Project/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	commentmodel "gintugas/modules/components/command/model"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportRepository interface {
+	// GetUsersByEmails mencocokkan email tanpa membedakan huruf besar dan kecil
+	GetUsersByEmails(emails []string) ([]usermodels.User, error)
+	GetProjectMemberIDs(projectID uuid.UUID) ([]uuid.UUID, error)
+	AddProjectMember(projectID uuid.UUID, userID uuid.UUID) error
+	CreateComment(comment *commentmodel.Comments) error
+
+	// Transaction memberi repository import dan task yang memakai transaksi yang sama
+	Transaction(fn func(repo ImportRepository, tasks taskrepository.TaskRepository) error) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) GetUsersByEmails(emails []string) ([]usermodels.User, error) {
+	users := []usermodels.User{}
+	if len(emails) == 0 {
+		return users, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+	err := r.db.Where("LOWER(email) IN ?", lowered).Find(&users).Error
+	return users, err
+}
+
+func (r *importRepository) GetProjectMemberIDs(projectID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&projectmodel.ProjectMember{}).
+		Where("project_id = ?", projectID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *importRepository) AddProjectMember(projectID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Create(&projectmodel.ProjectMember{
+		ProjectID: projectID.String(),
+		UserID:    userID.String(),
+	}).Error
+}
+
+func (r *importRepository) CreateComment(comment *commentmodel.Comments) error {
+	return r.db.Omit(clause.Associations).Create(comment).Error
+}
+
+func (r *importRepository) Transaction(fn func(repo ImportRepository, tasks taskrepository.TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&importRepository{db: tx}, taskrepository.NewTaskRepository(tx))
+	})
+}
@@ -0,0 +1,217 @@
+package importservice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	importmodel "gintugas/modules/components/Import/model"
+	"io"
+	"strings"
+	"time"
+)
+
+// header yang dicoba jika kolom tidak dipetakan, dicocokkan tanpa membedakan huruf besar dan kecil
+var csvColumnGuesses = map[string][]string{
+	importmodel.FieldTitle:       {"title", "name", "summary", "task"},
+	importmodel.FieldDescription: {"description", "desc", "details"},
+	importmodel.FieldStatus:      {"status", "state", "list"},
+	importmodel.FieldAssignee:    {"assignee", "assignee_email", "assigned to", "owner"},
+	importmodel.FieldDueDate:     {"due_date", "due date", "due", "deadline"},
+}
+
+// kolom Jira yang dipakai jika tidak dipetakan
+var jiraColumnGuesses = map[string][]string{
+	importmodel.FieldTitle:       {"summary"},
+	importmodel.FieldDescription: {"description"},
+	importmodel.FieldStatus:      {"status"},
+	importmodel.FieldAssignee:    {"assignee"},
+	importmodel.FieldDueDate:     {"due date"},
+}
+
+// format tanggal Jira, misalnya 12/Jan/24 10:00 AM
+var jiraDateLayouts = []string{"02/Jan/06 3:04 PM", "2/Jan/06 3:04 PM", "02/Jan/06", "2/Jan/06"}
+
+// parseCSV membaca CSV biasa atau export CSV Jira. Kolom Comment/Comments dan Attachment/Attachments boleh muncul berkali-kali
+func parseCSV(data []byte, source string, columns map[string]string) ([]string, map[string]string, []importmodel.Record, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, nil, nil, errors.New("file CSV kosong atau tidak valid")
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+
+	guesses := csvColumnGuesses
+	if source == importmodel.SourceJira {
+		guesses = jiraColumnGuesses
+	}
+
+	index := map[string]int{}
+	resolved := map[string]string{}
+	for _, field := range []string{importmodel.FieldTitle, importmodel.FieldDescription, importmodel.FieldStatus, importmodel.FieldAssignee, importmodel.FieldDueDate} {
+		candidates := guesses[field]
+		if column, ok := columns[field]; ok {
+			candidates = []string{column}
+		}
+		i := findColumn(headers, candidates)
+		if i < 0 {
+			if _, ok := columns[field]; ok {
+				return nil, nil, nil, fmt.Errorf("kolom %s untuk %s tidak ada di file", columns[field], field)
+			}
+			continue
+		}
+		index[field] = i
+		resolved[field] = headers[i]
+	}
+	for field := range columns {
+		if _, ok := csvColumnGuesses[field]; !ok {
+			return nil, nil, nil, fmt.Errorf("field %s tidak bisa dipetakan", field)
+		}
+	}
+	if _, ok := index[importmodel.FieldTitle]; !ok {
+		return nil, nil, nil, errors.New("kolom title tidak ditemukan, petakan lewat mapping.columns.title")
+	}
+
+	var commentColumns, attachmentColumns []int
+	for i, header := range headers {
+		switch strings.ToLower(header) {
+		case "comment", "comments":
+			commentColumns = append(commentColumns, i)
+		case "attachment", "attachments":
+			attachmentColumns = append(attachmentColumns, i)
+		}
+	}
+
+	records := []importmodel.Record{}
+	for row := 2; ; row++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("baris %d tidak valid: %v", row, err)
+		}
+		if isBlankRow(values) {
+			continue
+		}
+
+		cell := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(values) {
+				return ""
+			}
+			return strings.TrimSpace(values[i])
+		}
+
+		record := importmodel.Record{
+			Row:         row,
+			Title:       cell(importmodel.FieldTitle),
+			Description: cell(importmodel.FieldDescription),
+			Status:      cell(importmodel.FieldStatus),
+			DueDate:     cell(importmodel.FieldDueDate),
+		}
+		if assignee := cell(importmodel.FieldAssignee); assignee != "" {
+			record.Assignees = []string{assignee}
+		}
+
+		for _, i := range commentColumns {
+			if i < len(values) && strings.TrimSpace(values[i]) != "" {
+				record.Comments = append(record.Comments, parseCSVComment(source, values[i]))
+			}
+		}
+		for _, i := range attachmentColumns {
+			if i < len(values) && strings.TrimSpace(values[i]) != "" {
+				record.Attachments = append(record.Attachments, parseCSVAttachment(source, values[i]))
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return headers, resolved, records, nil
+}
+
+func findColumn(headers []string, candidates []string) int {
+	for _, candidate := range candidates {
+		for i, header := range headers {
+			if strings.EqualFold(header, candidate) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isBlankRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCSVComment: Jira menulis komentar sebagai "tanggal;account id;isi", CSV biasa hanya isinya
+func parseCSVComment(source, value string) importmodel.RecordComment {
+	value = strings.TrimSpace(value)
+	if source == importmodel.SourceJira {
+		parts := strings.SplitN(value, ";", 3)
+		if len(parts) == 3 {
+			if createdAt, err := parseJiraDate(parts[0]); err == nil {
+				return importmodel.RecordComment{
+					Author:    strings.TrimSpace(parts[1]),
+					Content:   strings.TrimSpace(parts[2]),
+					CreatedAt: &createdAt,
+				}
+			}
+		}
+	}
+	return importmodel.RecordComment{Content: value}
+}
+
+// parseCSVAttachment: Jira menulis attachment sebagai "tanggal;account id;nama file;url", CSV biasa hanya url
+func parseCSVAttachment(source, value string) importmodel.RecordAttachment {
+	value = strings.TrimSpace(value)
+	if source == importmodel.SourceJira {
+		parts := strings.SplitN(value, ";", 4)
+		if len(parts) == 4 {
+			return importmodel.RecordAttachment{Name: strings.TrimSpace(parts[2]), URL: strings.TrimSpace(parts[3])}
+		}
+	}
+	name := value
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		name = value[i+1:]
+	}
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	return importmodel.RecordAttachment{Name: name, URL: value}
+}
+
+func parseJiraDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range jiraDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal %s tidak dikenali", value)
+}
+
+// parseDueDate menerima YYYY-MM-DD, RFC3339 dan format tanggal Jira
+func parseDueDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	if date, err := parseJiraDate(value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("due_date %s tidak dikenali, gunakan YYYY-MM-DD atau RFC3339", value)
+}
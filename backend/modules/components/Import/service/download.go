@@ -0,0 +1,92 @@
+package importservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	outbound "gintugas/modules/components/Outbound"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// batas dan tipe file sama dengan upload attachment biasa
+const maxAttachmentSize = 10 * 1024 * 1024
+
+var allowedAttachmentExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".pdf": true, ".doc": true,
+	".docx": true, ".xls": true, ".xlsx": true, ".txt": true, ".zip": true, ".rar": true,
+}
+
+// attachmentClient menolak url yang mengarah ke jaringan internal server
+var attachmentClient = outbound.NewClient(30 * time.Second)
+
+// validateAttachmentURL memeriksa url dan ekstensi file tanpa mengunduh
+func validateAttachmentURL(name, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url attachment %s tidak valid", name)
+	}
+	if !allowedAttachmentExts[strings.ToLower(filepath.Ext(name))] {
+		return fmt.Errorf("tipe file attachment %s tidak diizinkan", name)
+	}
+	return nil
+}
+
+type downloadedFile struct {
+	Path     string
+	Size     int64
+	MimeType string
+}
+
+// downloadAttachment mengunduh file ke folder upload dengan nama baru, file dihapus lagi jika gagal
+func downloadAttachment(ctx context.Context, uploadPath, name, rawURL string) (*downloadedFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := attachmentClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server mengembalikan status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxAttachmentSize {
+		return nil, errors.New("ukuran file maksimal 10MB")
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	target := filepath.Join(uploadPath, uuid.New().String()+ext)
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := io.Copy(file, io.LimitReader(resp.Body, maxAttachmentSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > maxAttachmentSize {
+		err = errors.New("ukuran file maksimal 10MB")
+	}
+	if err != nil {
+		os.Remove(target)
+		return nil, err
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(ext)
+	}
+	return &downloadedFile{Path: target, Size: size, MimeType: mimeType}, nil
+}
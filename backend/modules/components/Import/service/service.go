@@ -0,0 +1,650 @@
+package importservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	importmodel "gintugas/modules/components/Import/model"
+	importrepository "gintugas/modules/components/Import/repository"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	attachmentmodel "gintugas/modules/components/attachments/models"
+	commentmodel "gintugas/modules/components/command/model"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	maxImportFileSize = 10 * 1024 * 1024
+	maxImportTasks    = 1000
+
+	// attachment diunduh di dalam request, jadi jumlah, total ukuran dan waktunya dibatasi per import
+	maxImportAttachments        = 100
+	maxImportAttachmentsPerTask = 10
+	maxImportAttachmentBytes    = 200 * 1024 * 1024
+	importDownloadTimeout       = 3 * time.Minute
+)
+
+// status task gintugas, sama dengan enum task_status
+var taskStatuses = map[string]bool{
+	"todo":        true,
+	"in-progress": true,
+	"in-review":   true,
+	"done":        true,
+}
+
+type ImportService interface {
+	PreviewImport(ctx *gin.Context) (*importmodel.ImportPreview, error)
+	ImportTasks(ctx *gin.Context) (*importmodel.ImportResult, error)
+}
+
+type importService struct {
+	repo       importrepository.ImportRepository
+	taskRepo   taskrepository.TaskRepository
+	uploadPath string
+	events     taskmodel.TaskEventPublisher
+}
+
+func NewImportService(repo importrepository.ImportRepository, taskRepo taskrepository.TaskRepository, uploadPath string, events taskmodel.TaskEventPublisher) ImportService {
+	return &importService{
+		repo:       repo,
+		taskRepo:   taskRepo,
+		uploadPath: uploadPath,
+		events:     events,
+	}
+}
+
+// importJob adalah file yang sudah diparsing beserta mapping yang sudah dilengkapi tebakan otomatis
+type importJob struct {
+	projectID uuid.UUID
+	userID    uuid.UUID
+	request   importmodel.ImportRequest
+	columns   []string
+	mapping   importmodel.ImportMapping
+	statuses  []string
+	users     map[string]*importmodel.UserMatch
+	records   []importmodel.Record
+}
+
+// plannedTask adalah task yang siap dibuat, info juga dikembalikan ke client
+type plannedTask struct {
+	info           importmodel.ImportTask
+	task           taskmodel.Task
+	extraAssignees []uuid.UUID
+	comments       []commentmodel.Comments
+	attachments    []importmodel.RecordAttachment
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+// PreviewImport membaca file dan menampilkan kolom, status dan user yang ditemukan beserta mapping yang akan dipakai.
+// Mapping hasil preview bisa diubah lalu dikirim lagi saat import
+func (s *importService) PreviewImport(ctx *gin.Context) (*importmodel.ImportPreview, error) {
+	job, err := s.loadJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := s.plan(job)
+	tasks := []importmodel.ImportTask{}
+	for i := 0; i < len(planned) && i < importmodel.PreviewLimit; i++ {
+		tasks = append(tasks, planned[i].info)
+	}
+
+	users := []importmodel.UserMatch{}
+	for _, match := range job.users {
+		users = append(users, *match)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Source < users[j].Source })
+
+	return &importmodel.ImportPreview{
+		Source:    job.request.Source,
+		Columns:   job.columns,
+		Statuses:  job.statuses,
+		Mapping:   job.mapping,
+		Users:     users,
+		TaskCount: len(planned),
+		Tasks:     tasks,
+	}, nil
+}
+
+// ImportTasks membuat semua task, komentar dan attachment dalam satu transaksi. Jika ada task dengan error tidak ada yang dibuat.
+// dry_run menjalankan semua validasi tanpa menyimpan dan tanpa mengunduh attachment. Import tidak mengirim email ke assignee
+func (s *importService) ImportTasks(ctx *gin.Context) (*importmodel.ImportResult, error) {
+	job, err := s.loadJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := s.plan(job)
+	result := &importmodel.ImportResult{
+		DryRun:  job.request.DryRun,
+		Valid:   true,
+		Members: s.newMembers(job, planned),
+	}
+	for _, item := range planned {
+		if len(item.info.Errors) > 0 {
+			result.Valid = false
+		}
+	}
+
+	if !result.Valid || job.request.DryRun {
+		for _, item := range planned {
+			result.Tasks = append(result.Tasks, item.info)
+			if result.Valid {
+				result.Created++
+				result.Comments += len(item.comments)
+				result.Attachments += len(item.attachments)
+			}
+		}
+		return result, nil
+	}
+
+	// attachment diunduh sebelum transaksi, file hanya dipertahankan jika transaksi berhasil.
+	// Import dibatalkan jika total ukuran atau waktu unduh melewati batas
+	var downloaded []string
+	defer func() {
+		for _, path := range downloaded {
+			os.Remove(path)
+		}
+	}()
+	downloadCtx, cancel := context.WithTimeout(ctx.Request.Context(), importDownloadTimeout)
+	defer cancel()
+
+	var totalBytes int64
+	files := make([][]attachmentmodel.Attachment, len(planned))
+	for i := range planned {
+		item := &planned[i]
+		for _, attachment := range item.attachments {
+			file, err := downloadAttachment(downloadCtx, s.uploadPath, attachment.Name, attachment.URL)
+			if downloadCtx.Err() != nil {
+				return nil, fmt.Errorf("waktu unduh attachment melebihi %v, kurangi attachment di file import", importDownloadTimeout)
+			}
+			if err != nil {
+				item.info.Warnings = append(item.info.Warnings, fmt.Sprintf("attachment %s dilewati: %v", attachment.Name, err))
+				continue
+			}
+			downloaded = append(downloaded, file.Path)
+			if totalBytes += file.Size; totalBytes > maxImportAttachmentBytes {
+				return nil, fmt.Errorf("total ukuran attachment maksimal %dMB per import", maxImportAttachmentBytes/1024/1024)
+			}
+			files[i] = append(files[i], attachmentmodel.Attachment{
+				FilePath:   file.Path,
+				FileName:   attachment.Name,
+				FileSize:   file.Size,
+				MimeType:   file.MimeType,
+				UploadedBy: job.userID,
+			})
+		}
+	}
+
+	err = s.repo.Transaction(func(repo importrepository.ImportRepository, tasks taskrepository.TaskRepository) error {
+		for _, member := range result.Members {
+			if err := repo.AddProjectMember(job.projectID, member); err != nil {
+				return err
+			}
+		}
+
+		for i := range planned {
+			item := &planned[i]
+			if err := s.createTask(repo, tasks, job, item, files[i]); err != nil {
+				return fmt.Errorf("gagal import baris %d: %v", item.info.Row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	downloaded = nil
+
+	for i, item := range planned {
+		s.events.PublishTaskEvent(taskmodel.TaskEvent{
+			Action:  taskmodel.HistoryCreated,
+			Task:    item.task,
+			Changes: taskmodel.DiffTask(nil, &item.task),
+			ActorID: &job.userID,
+			Source:  taskmodel.EventSourceImport,
+		})
+		result.Tasks = append(result.Tasks, item.info)
+		result.Created++
+		result.Comments += len(item.comments)
+		result.Attachments += len(files[i])
+	}
+	return result, nil
+}
+
+func (s *importService) createTask(repo importrepository.ImportRepository, tasks taskrepository.TaskRepository, job *importJob, item *plannedTask, files []attachmentmodel.Attachment) error {
+	task := &item.task
+	if err := tasks.CreateTask(task); err != nil {
+		return err
+	}
+	if err := tasks.ReplacePrimaryAssignee(task.ID, nil, task.AssigneeID, false); err != nil {
+		return err
+	}
+	for _, userID := range item.extraAssignees {
+		err := tasks.UpsertTaskAssignee(&taskmodel.TaskAssignee{TaskID: task.ID, UserID: userID})
+		if err != nil {
+			return err
+		}
+	}
+
+	// task in-review langsung punya review pending supaya muncul di antrean review manager
+	if task.Status == taskmodel.StatusInReview {
+		err := tasks.CreateTaskReview(&taskmodel.TaskReview{TaskID: task.ID, SubmittedBy: &job.userID, Note: "imported"})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		TaskTitle: task.Title,
+		ActorID:   &job.userID,
+		Action:    taskmodel.HistoryCreated,
+		Changes:   taskmodel.DiffTask(nil, task),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range item.comments {
+		item.comments[i].TaskID = task.ID
+		if err := repo.CreateComment(&item.comments[i]); err != nil {
+			return err
+		}
+	}
+	for i := range files {
+		files[i].TaskID = task.ID
+		if err := tasks.CreateAttachment(&files[i]); err != nil {
+			return err
+		}
+	}
+
+	item.info.TaskID = &task.ID
+	return nil
+}
+
+// loadJob memeriksa akses, membaca form dan file lalu melengkapi mapping status dan user
+func (s *importService) loadJob(ctx *gin.Context) (*importJob, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format project ID")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectUUID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ManagerID != userUUID {
+		return nil, errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	if project.ArchivedAt != nil {
+		return nil, archive.ErrReadOnly
+	}
+
+	job := &importJob{projectID: projectUUID, userID: userUUID}
+	if err := ctx.ShouldBind(&job.request); err != nil {
+		return nil, err
+	}
+
+	if job.request.Mapping != "" {
+		if err := json.Unmarshal([]byte(job.request.Mapping), &job.mapping); err != nil {
+			return nil, fmt.Errorf("mapping harus berupa JSON yang valid: %v", err)
+		}
+	}
+	if job.mapping.DefaultStatus == "" {
+		job.mapping.DefaultStatus = "todo"
+	}
+	if !taskStatuses[job.mapping.DefaultStatus] {
+		return nil, fmt.Errorf("default_status tidak valid: %s", job.mapping.DefaultStatus)
+	}
+	for source, status := range job.mapping.Statuses {
+		if !taskStatuses[status] {
+			return nil, fmt.Errorf("status tidak valid untuk %s: %s", source, status)
+		}
+	}
+
+	data, err := readImportFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.request.Source {
+	case importmodel.SourceTrello:
+		if len(job.mapping.Columns) > 0 {
+			return nil, errors.New("mapping.columns tidak dipakai untuk import Trello")
+		}
+		job.records, err = parseTrello(data)
+	default:
+		job.columns, job.mapping.Columns, job.records, err = parseCSV(data, job.request.Source, job.mapping.Columns)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(job.records) == 0 {
+		return nil, errors.New("file tidak berisi task")
+	}
+	if len(job.records) > maxImportTasks {
+		return nil, fmt.Errorf("maksimal %d task per import", maxImportTasks)
+	}
+
+	attachments := 0
+	for _, record := range job.records {
+		if len(record.Attachments) > maxImportAttachmentsPerTask {
+			return nil, fmt.Errorf("baris %d: maksimal %d attachment per task", record.Row, maxImportAttachmentsPerTask)
+		}
+		attachments += len(record.Attachments)
+	}
+	if attachments > maxImportAttachments {
+		return nil, fmt.Errorf("maksimal %d attachment per import, file berisi %d", maxImportAttachments, attachments)
+	}
+
+	job.resolveStatuses()
+	if err := s.resolveUsers(job, project.ManagerID); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func readImportFile(ctx *gin.Context) ([]byte, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, errors.New("gagal mengambil file: " + err.Error())
+	}
+	if header.Size > maxImportFileSize {
+		return nil, errors.New("ukuran file import maksimal 10MB")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// resolveStatuses melengkapi mapping.statuses untuk setiap status sumber yang belum dipetakan
+func (job *importJob) resolveStatuses() {
+	mapped := map[string]string{}
+	for source, status := range job.mapping.Statuses {
+		mapped[strings.ToLower(strings.TrimSpace(source))] = status
+	}
+
+	resolved := map[string]string{}
+	job.statuses = []string{}
+	for _, record := range job.records {
+		if record.Status == "" {
+			continue
+		}
+		if _, ok := resolved[record.Status]; ok {
+			continue
+		}
+		status, ok := mapped[strings.ToLower(record.Status)]
+		if !ok {
+			status = guessStatus(record.Status, job.mapping.DefaultStatus)
+		}
+		resolved[record.Status] = status
+		job.statuses = append(job.statuses, record.Status)
+	}
+	job.mapping.Statuses = resolved
+}
+
+// guessStatus menebak status dari nama status Jira atau list Trello yang umum
+func guessStatus(source, fallback string) string {
+	value := strings.ToLower(strings.TrimSpace(source))
+	value = strings.NewReplacer("_", " ", "-", " ").Replace(value)
+	switch {
+	case value == "in progress":
+		return "in-progress"
+	case taskStatuses[value]:
+		return value
+	}
+
+	for _, word := range []string{"done", "complete", "closed", "resolved", "finished", "selesai"} {
+		if strings.Contains(value, word) {
+			return "done"
+		}
+	}
+	for _, word := range []string{"review", "approval", "verify"} {
+		if strings.Contains(value, word) {
+			return taskmodel.StatusInReview
+		}
+	}
+	for _, word := range []string{"progress", "doing", "testing", "active", "dikerjakan"} {
+		if strings.Contains(value, word) {
+			return "in-progress"
+		}
+	}
+	for _, word := range []string{"to do", "todo", "backlog", "open", "new", "selected"} {
+		if strings.Contains(value, word) {
+			return "todo"
+		}
+	}
+	return fallback
+}
+
+// resolveUsers mencocokkan user sumber (assignee dan penulis komentar) ke user gintugas lewat email
+func (s *importService) resolveUsers(job *importJob, managerID uuid.UUID) error {
+	mapped := map[string]string{}
+	for source, email := range job.mapping.Users {
+		mapped[strings.ToLower(strings.TrimSpace(source))] = strings.TrimSpace(email)
+	}
+
+	job.users = map[string]*importmodel.UserMatch{}
+	add := func(source string) {
+		if source == "" || job.users[source] != nil {
+			return
+		}
+		email, ok := mapped[strings.ToLower(source)]
+		if !ok && strings.Contains(source, "@") {
+			email = source
+		}
+		job.users[source] = &importmodel.UserMatch{Source: source, Email: email}
+	}
+	for _, record := range job.records {
+		for _, assignee := range record.Assignees {
+			add(assignee)
+		}
+		for _, comment := range record.Comments {
+			add(comment.Author)
+		}
+	}
+
+	var emails []string
+	for _, match := range job.users {
+		if match.Email != "" {
+			emails = append(emails, match.Email)
+		}
+	}
+	users, err := s.repo.GetUsersByEmails(emails)
+	if err != nil {
+		return err
+	}
+	byEmail := map[string]uuid.UUID{}
+	for _, user := range users {
+		byEmail[strings.ToLower(user.Email)] = user.ID
+	}
+
+	memberIDs, err := s.repo.GetProjectMemberIDs(job.projectID)
+	if err != nil {
+		return err
+	}
+	members := map[uuid.UUID]bool{managerID: true}
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+
+	resolved := map[string]string{}
+	for source, match := range job.users {
+		if id, ok := byEmail[strings.ToLower(match.Email)]; ok {
+			match.UserID = &id
+			match.IsMember = members[id]
+		}
+		if match.Email != "" {
+			resolved[source] = match.Email
+		}
+	}
+	job.mapping.Users = resolved
+	return nil
+}
+
+// canAssign: user yang belum menjadi member hanya boleh menjadi assignee jika add_members
+func (job *importJob) canAssign(match *importmodel.UserMatch) bool {
+	return match.UserID != nil && (match.IsMember || job.mapping.AddMembers)
+}
+
+// plan memetakan setiap record ke task tanpa menyimpan apa pun
+func (s *importService) plan(job *importJob) []plannedTask {
+	planned := make([]plannedTask, 0, len(job.records))
+	for _, record := range job.records {
+		item := plannedTask{
+			info: importmodel.ImportTask{Row: record.Row},
+			task: taskmodel.Task{
+				ProjectID:   job.projectID,
+				Description: record.Description,
+				Status:      job.mapping.DefaultStatus,
+			},
+		}
+		warn := func(format string, args ...interface{}) {
+			item.info.Warnings = append(item.info.Warnings, fmt.Sprintf(format, args...))
+		}
+
+		title := record.Title
+		if title == "" {
+			item.info.Errors = append(item.info.Errors, "title kosong")
+		}
+		if utf8.RuneCountInString(title) > 200 {
+			title = string([]rune(title)[:200])
+			warn("title dipotong menjadi 200 karakter")
+		}
+		item.task.Title = title
+
+		if record.Status != "" {
+			item.task.Status = job.mapping.Statuses[record.Status]
+		}
+
+		seen := map[uuid.UUID]bool{}
+		for _, source := range record.Assignees {
+			match := job.users[source]
+			switch {
+			case match.UserID == nil:
+				warn("user %s tidak ditemukan, petakan lewat mapping.users", source)
+			case !job.canAssign(match):
+				warn("user %s bukan member project, aktifkan add_members untuk menambahkannya", source)
+			case seen[*match.UserID]:
+			case item.task.AssigneeID == nil:
+				item.task.AssigneeID = match.UserID
+				seen[*match.UserID] = true
+			default:
+				item.extraAssignees = append(item.extraAssignees, *match.UserID)
+				seen[*match.UserID] = true
+			}
+		}
+
+		if record.DueDate != "" {
+			dueDate, err := parseDueDate(record.DueDate)
+			if err != nil {
+				warn("%v, due_date dikosongkan", err)
+			} else {
+				item.task.DueDate = &dueDate
+			}
+		}
+
+		for _, comment := range record.Comments {
+			content := strings.TrimSpace(comment.Content)
+			if content == "" {
+				continue
+			}
+			created := commentmodel.Comments{Content: content}
+			if comment.CreatedAt != nil {
+				created.CreatedAt = *comment.CreatedAt
+				created.UpdatedAt = *comment.CreatedAt
+			}
+			// komentar dari user yang tidak dikenal disimpan atas nama pengimport dengan nama penulis aslinya
+			if match := job.users[comment.Author]; match != nil && match.UserID != nil {
+				created.UserID = match.UserID
+			} else {
+				userID := job.userID
+				created.UserID = &userID
+				if comment.Author != "" {
+					created.Content = fmt.Sprintf("Komentar asli oleh %s:\n%s", comment.Author, content)
+				}
+			}
+			item.comments = append(item.comments, created)
+		}
+
+		for _, attachment := range record.Attachments {
+			if err := validateAttachmentURL(attachment.Name, attachment.URL); err != nil {
+				warn("%v, attachment dilewati", err)
+				continue
+			}
+			item.attachments = append(item.attachments, attachment)
+		}
+
+		item.info.Title = item.task.Title
+		item.info.Status = item.task.Status
+		item.info.AssigneeID = item.task.AssigneeID
+		item.info.DueDate = item.task.DueDate
+		item.info.Comments = len(item.comments)
+		item.info.Attachments = len(item.attachments)
+		planned = append(planned, item)
+	}
+	return planned
+}
+
+// newMembers mengembalikan user bukan member yang dipakai sebagai assignee, hanya jika add_members
+func (s *importService) newMembers(job *importJob, planned []plannedTask) []uuid.UUID {
+	members := []uuid.UUID{}
+	if !job.mapping.AddMembers {
+		return members
+	}
+
+	nonMembers := map[uuid.UUID]bool{}
+	for _, match := range job.users {
+		if match.UserID != nil && !match.IsMember {
+			nonMembers[*match.UserID] = true
+		}
+	}
+
+	for _, item := range planned {
+		assignees := item.extraAssignees
+		if item.task.AssigneeID != nil {
+			assignees = append([]uuid.UUID{*item.task.AssigneeID}, assignees...)
+		}
+		for _, id := range assignees {
+			if nonMembers[id] {
+				delete(nonMembers, id)
+				members = append(members, id)
+			}
+		}
+	}
+	return members
+}
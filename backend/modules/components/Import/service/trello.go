@@ -0,0 +1,131 @@
+package importservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	importmodel "gintugas/modules/components/Import/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trelloBoard hanya berisi bagian export JSON board Trello yang dipakai
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		IDList      string   `json:"idList"`
+		Due         *string  `json:"due"`
+		Closed      bool     `json:"closed"`
+		IDMembers   []string `json:"idMembers"`
+		Attachments []struct {
+			Name     string `json:"name"`
+			URL      string `json:"url"`
+			IsUpload bool   `json:"isUpload"`
+		} `json:"attachments"`
+	} `json:"cards"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Actions []struct {
+		Type            string    `json:"type"`
+		Date            time.Time `json:"date"`
+		IDMemberCreator string    `json:"idMemberCreator"`
+		Data            struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+	} `json:"actions"`
+}
+
+// parseTrello membaca export JSON board Trello. Nama list menjadi status, card dan list yang diarsipkan dilewati.
+// User dicocokkan lewat username Trello karena export tidak berisi email
+func parseTrello(data []byte) ([]importmodel.Record, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("file JSON Trello tidak valid: %v", err)
+	}
+	if board.Lists == nil || board.Cards == nil {
+		return nil, errors.New("file bukan export board Trello: lists dan cards tidak ditemukan")
+	}
+
+	lists := map[string]string{}
+	for _, list := range board.Lists {
+		if !list.Closed {
+			lists[list.ID] = list.Name
+		}
+	}
+	usernames := map[string]string{}
+	for _, member := range board.Members {
+		usernames[member.ID] = member.Username
+	}
+
+	// actions diexport dari yang terbaru, komentar disimpan dari yang terlama
+	actions := board.Actions
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Date.Before(actions[j].Date)
+	})
+	comments := map[string][]importmodel.RecordComment{}
+	for _, action := range actions {
+		if action.Type != "commentCard" || strings.TrimSpace(action.Data.Text) == "" {
+			continue
+		}
+		createdAt := action.Date
+		comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], importmodel.RecordComment{
+			Author:    usernames[action.IDMemberCreator],
+			Content:   action.Data.Text,
+			CreatedAt: &createdAt,
+		})
+	}
+
+	records := []importmodel.Record{}
+	for i, card := range board.Cards {
+		listName, ok := lists[card.IDList]
+		if card.Closed || !ok {
+			continue
+		}
+
+		record := importmodel.Record{
+			Row:         i + 1,
+			Title:       strings.TrimSpace(card.Name),
+			Description: card.Desc,
+			Status:      listName,
+			Comments:    comments[card.ID],
+		}
+		if card.Due != nil {
+			record.DueDate = *card.Due
+		}
+		for _, id := range card.IDMembers {
+			if username, ok := usernames[id]; ok {
+				record.Assignees = append(record.Assignees, username)
+			}
+		}
+
+		// attachment berupa link tidak punya file, disimpan di description
+		var links []string
+		for _, attachment := range card.Attachments {
+			if attachment.IsUpload {
+				record.Attachments = append(record.Attachments, importmodel.RecordAttachment{Name: attachment.Name, URL: attachment.URL})
+			} else {
+				links = append(links, fmt.Sprintf("- %s: %s", attachment.Name, attachment.URL))
+			}
+		}
+		if len(links) > 0 {
+			record.Description = strings.TrimSpace(record.Description + "\n\nLinks:\n" + strings.Join(links, "\n"))
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
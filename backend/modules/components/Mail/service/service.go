@@ -4,10 +4,20 @@ import (
 	"fmt"
 	"net/smtp"
 	"os"
+	"strings"
+	"time"
 )
 
 type MailService interface {
 	SendTaskAssignmentNotification(to string, taskTitle string, projectName string) error
+	SendTaskUpdateNotification(to string, taskTitle string, projectName string, changedFields []string) error
+	SendTaskDigest(to string, projectName string, lines []string) error
+	SendReviewRequestNotification(to string, taskTitle string, projectName string, submitter string) error
+	SendReviewDecisionNotification(to string, taskTitle string, projectName string, approved bool, comment string) error
+	SendDueReminder(to string, taskTitle string, projectName string, dueDate time.Time) error
+	SendOverdueNotice(to string, taskTitle string, projectName string, dueDate time.Time) error
+	SendOverdueEscalation(to string, taskTitle string, projectName string, dueDate time.Time, assignees []string) error
+	SendAutomationNotification(to string, ruleName string, taskTitle string, projectName string, message string) error
 }
 
 type mailService struct {
@@ -29,10 +39,79 @@ func NewMailService() MailService {
 }
 
 func (s *mailService) SendTaskAssignmentNotification(to string, taskTitle string, projectName string) error {
-	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
-
 	subject := "New Task Assignment"
 	body := fmt.Sprintf("You have been assigned to the task '%s' in project '%s'.", taskTitle, projectName)
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) SendTaskUpdateNotification(to string, taskTitle string, projectName string, changedFields []string) error {
+	subject := "Task Updated"
+	body := fmt.Sprintf("The task '%s' in project '%s' you are watching has been updated (%s).",
+		taskTitle, projectName, strings.Join(changedFields, ", "))
+	return s.send(to, subject, body)
+}
+
+// SendTaskDigest merangkum beberapa perubahan task dalam satu email, satu baris per task
+func (s *mailService) SendTaskDigest(to string, projectName string, lines []string) error {
+	subject := "Task Updates"
+	body := fmt.Sprintf("The following tasks in project '%s' have been updated:\r\n\r\n- %s",
+		projectName, strings.Join(lines, "\r\n- "))
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) SendReviewRequestNotification(to string, taskTitle string, projectName string, submitter string) error {
+	subject := "Task Review Requested"
+	body := fmt.Sprintf("%s submitted the task '%s' in project '%s' for your review.", submitter, taskTitle, projectName)
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) SendReviewDecisionNotification(to string, taskTitle string, projectName string, approved bool, comment string) error {
+	subject := "Task Review Rejected"
+	body := fmt.Sprintf("Your review request for the task '%s' in project '%s' was rejected.", taskTitle, projectName)
+	if approved {
+		subject = "Task Review Approved"
+		body = fmt.Sprintf("Your review request for the task '%s' in project '%s' was approved and the task is now done.", taskTitle, projectName)
+	}
+	if comment != "" {
+		body += "\r\n\r\nComment: " + comment
+	}
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) SendDueReminder(to string, taskTitle string, projectName string, dueDate time.Time) error {
+	subject := "Task Due Soon"
+	body := fmt.Sprintf("Reminder: the task '%s' in project '%s' is due on %s.", taskTitle, projectName, dueDate.Format("2006-01-02"))
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) SendOverdueNotice(to string, taskTitle string, projectName string, dueDate time.Time) error {
+	subject := "Task Overdue"
+	body := fmt.Sprintf("The task '%s' in project '%s' was due on %s and is now overdue.", taskTitle, projectName, dueDate.Format("2006-01-02"))
+	return s.send(to, subject, body)
+}
+
+// SendOverdueEscalation memberi tahu manager project tentang task yang masih overdue setelah masa tenggang
+func (s *mailService) SendOverdueEscalation(to string, taskTitle string, projectName string, dueDate time.Time, assignees []string) error {
+	subject := "Overdue Task Escalation"
+	assignedTo := "nobody"
+	if len(assignees) > 0 {
+		assignedTo = strings.Join(assignees, ", ")
+	}
+	body := fmt.Sprintf("The task '%s' in project '%s' was due on %s and is still not done.\r\n\r\nAssigned to: %s",
+		taskTitle, projectName, dueDate.Format("2006-01-02"), assignedTo)
+	return s.send(to, subject, body)
+}
+
+// SendAutomationNotification dikirim oleh action send_notification automation rule
+func (s *mailService) SendAutomationNotification(to string, ruleName string, taskTitle string, projectName string, message string) error {
+	subject := fmt.Sprintf("[%s] %s", projectName, taskTitle)
+	body := fmt.Sprintf("%s\r\n\r\nTask: %s\r\nProject: %s\r\nAutomation rule: %s", message, taskTitle, projectName, ruleName)
+	return s.send(to, subject, body)
+}
+
+func (s *mailService) send(to string, subject string, body string) error {
+	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
+
 	message := fmt.Sprintf("To: %s\r\n"+
 		"Subject: %s\r\n"+
 		"\r\n"+
@@ -0,0 +1,59 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// batas redirect untuk request ke url yang diberikan user
+const maxRedirects = 5
+
+// NewClient membuat http.Client untuk url yang diberikan user (attachment import, webhook automation).
+// Alamat internal ditolak saat koneksi dibuka sehingga redirect dan DNS yang berubah ikut diperiksa,
+// dan proxy dari environment tidak dipakai supaya pemeriksaan itu berlaku untuk alamat tujuan sebenarnya
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: nil,
+			DialContext: (&net.Dialer{
+				Timeout: 10 * time.Second,
+				Control: DenyInternalAddress,
+			}).DialContext,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("redirect lebih dari %d kali", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errors.New("redirect hanya boleh ke url http atau https")
+			}
+			return nil
+		},
+	}
+}
+
+// IsInternalIP bernilai true untuk alamat loopback, private, link-local (termasuk metadata cloud 169.254.169.254),
+// multicast dan unspecified
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// DenyInternalAddress dipasang sebagai net.Dialer.Control supaya url dari user tidak bisa dipakai untuk mengakses
+// jaringan internal server
+func DenyInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsInternalIP(ip) {
+		return fmt.Errorf("alamat %s tidak diizinkan", host)
+	}
+	return nil
+}
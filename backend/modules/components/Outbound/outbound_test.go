@@ -0,0 +1,79 @@
+package outbound
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsInternalIP(t *testing.T) {
+	tests := []struct {
+		ip       string
+		internal bool
+	}{
+		// IPv4
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"10.0.0.1", true},
+		{"172.16.0.1", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"169.254.169.254", true},
+		{"169.254.0.1", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"93.184.216.34", false},
+
+		// IPv6
+		{"::1", true},
+		{"::", true},
+		{"fc00::1", true},
+		{"fd12:3456:789a::1", true},
+		{"fe80::1", true},
+		{"ff02::1", true},
+		{"2001:4860:4860::8888", false},
+		{"2606:4700:4700::1111", false},
+
+		// IPv4-mapped IPv6 diperiksa sebagai alamat IPv4-nya
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::ffff:192.168.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"::ffff:8.8.8.8", false},
+	}
+
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("gagal parse %q", tt.ip)
+		}
+		if got := IsInternalIP(ip); got != tt.internal {
+			t.Errorf("IsInternalIP(%s) = %v, want %v", tt.ip, got, tt.internal)
+		}
+	}
+}
+
+func TestDenyInternalAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"8.8.8.8:443", true},
+		{"[2001:4860:4860::8888]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"[::ffff:169.254.169.254]:80", false},
+		{"[fe80::1%eth0]:80", false},
+		// Control dipanggil setelah DNS, alamat yang bukan IP berarti tidak bisa diperiksa
+		{"localhost:80", false},
+		{"8.8.8.8", false},
+	}
+
+	for _, tt := range tests {
+		err := DenyInternalAddress("tcp", tt.address, nil)
+		if got := err == nil; got != tt.allowed {
+			t.Errorf("DenyInternalAddress(%q): allowed = %v, want %v (err: %v)", tt.address, got, tt.allowed, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType adalah media type JSON Merge Patch (RFC 7396)
+const ContentType = "application/merge-patch+json"
+
+// Document adalah body JSON Merge Patch. Field yang tidak dikirim berarti tidak berubah,
+// field bernilai null berarti nilainya dihapus
+type Document map[string]json.RawMessage
+
+// Bind membaca body request sebagai merge patch dan menolak field di luar allowed
+func Bind(ctx *gin.Context, allowed ...string) (Document, error) {
+	if contentType := ctx.GetHeader("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != ContentType && mediaType != "application/json") {
+			return nil, fmt.Errorf("content-type harus %s atau application/json", ContentType)
+		}
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, errors.New("gagal membaca body request")
+	}
+
+	// patch selain object akan mengganti seluruh resource, tidak didukung di sini
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil, errors.New("body patch harus berupa JSON object")
+	}
+
+	var doc Document
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, errors.New("body patch bukan JSON yang valid")
+	}
+
+	if err := doc.Allow(allowed...); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Allow menolak field di luar allowed, dipakai juga untuk patch yang tertanam di body lain
+func (d Document) Allow(allowed ...string) error {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, field := range allowed {
+		allowedSet[field] = true
+	}
+
+	var unknown []string
+	for field := range d {
+		if !allowedSet[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("field tidak dikenal: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Has mengecek apakah field dikirim di patch (termasuk bernilai null)
+func (d Document) Has(field string) bool {
+	_, ok := d[field]
+	return ok
+}
+
+// IsNull mengecek apakah field dikirim dengan nilai null
+func (d Document) IsNull(field string) bool {
+	raw, ok := d[field]
+	return ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// Decode membaca nilai field ke v
+func (d Document) Decode(field string, v interface{}) error {
+	raw, ok := d[field]
+	if !ok {
+		return fmt.Errorf("field %s tidak ada", field)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("field %s tidak valid", field)
+	}
+	return nil
+}
+
+// String membaca field bertipe string, null ditolak jika field wajib ada nilainya
+func (d Document) String(field string, nullable bool) (string, error) {
+	if d.IsNull(field) {
+		if !nullable {
+			return "", fmt.Errorf("field %s tidak boleh null", field)
+		}
+		return "", nil
+	}
+
+	var value string
+	if err := d.Decode(field, &value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
@@ -0,0 +1,92 @@
+package planningmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SprintPlanned = "planned"
+	SprintActive  = "active"
+	SprintClosed  = "closed"
+)
+
+type Sprint struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID uuid.UUID  `json:"project_id" gorm:"type:uuid;not null"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+	Goal      string     `json:"goal" gorm:"type:text"`
+	StartDate time.Time  `json:"start_date" gorm:"type:date;not null"`
+	EndDate   time.Time  `json:"end_date" gorm:"type:date;not null"`
+	Status    string     `json:"status" gorm:"type:sprint_status;default:'planned'"`
+	StartedAt *time.Time `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	CreatedBy *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Sprint) TableName() string {
+	return "sprints"
+}
+
+type Milestone struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID   uuid.UUID  `json:"project_id" gorm:"type:uuid;not null"`
+	Name        string     `json:"name" gorm:"type:varchar(100);not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	TargetDate  time.Time  `json:"target_date" gorm:"type:date;not null"`
+	CreatedBy   *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Milestone) TableName() string {
+	return "milestones"
+}
+
+// TaskProgress dihitung sama seperti progress project di dashboard: persentase task done dari semua task
+type TaskProgress struct {
+	TotalTasks      int64   `json:"total_tasks"`
+	TodoTasks       int64   `json:"todo_tasks"`
+	InProgressTasks int64   `json:"in_progress_tasks"`
+	InReviewTasks   int64   `json:"in_review_tasks"`
+	DoneTasks       int64   `json:"done_tasks"`
+	Progress        float64 `json:"progress"`
+}
+
+type SprintDetail struct {
+	Sprint
+	TaskProgress
+}
+
+type MilestoneDetail struct {
+	Milestone
+	TaskProgress
+	IsOverdue bool `json:"is_overdue"`
+}
+
+type SprintRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Goal      string `json:"goal"`
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD
+}
+
+// CloseSprintRequest: task yang belum selesai dipindah ke CarryOverTo, tanpa CarryOverTo kembali ke backlog
+type CloseSprintRequest struct {
+	CarryOverTo *uuid.UUID `json:"carry_over_to"`
+}
+
+type CloseSprintResult struct {
+	Sprint        *SprintDetail `json:"sprint"`
+	CarriedOver   int           `json:"carried_over"`
+	CarriedOverTo *uuid.UUID    `json:"carried_over_to"`
+}
+
+type MilestoneRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	TargetDate  string `json:"target_date" binding:"required"` // YYYY-MM-DD
+}
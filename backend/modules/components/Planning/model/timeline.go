@@ -0,0 +1,63 @@
+package planningmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jenis konflik jadwal di timeline
+const (
+	ConflictStartAfterDue = "start_after_due"
+	ConflictOverbooked    = "overbooked"
+)
+
+// TimelineBar adalah satu task sebagai batang tanggal. Start dan End inklusif: task yang hanya punya salah satu
+// tanggal tampil sebagai batang satu hari, task tanpa tanggal sama sekali masuk Unscheduled dengan Start dan End null
+type TimelineBar struct {
+	TaskID      uuid.UUID  `json:"task_id"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	Start       *time.Time `json:"start"`
+	End         *time.Time `json:"end"`
+	StartDate   *time.Time `json:"start_date"`
+	DueDate     *time.Time `json:"due_date"`
+	SprintID    *uuid.UUID `json:"sprint_id"`
+	MilestoneID *uuid.UUID `json:"milestone_id"`
+	IsPrimary   bool       `json:"is_primary"`
+	Critical    bool       `json:"critical"`
+	Conflicts   []string   `json:"conflicts"`
+}
+
+// TimelineLane berisi batang task milik satu assignee. AssigneeID null adalah lane task yang belum di-assign;
+// task dengan beberapa assignee muncul di lane setiap assignee-nya
+type TimelineLane struct {
+	AssigneeID *uuid.UUID    `json:"assignee_id"`
+	Username   string        `json:"username"`
+	Bars       []TimelineBar `json:"bars"`
+}
+
+// TimelineConflict menjelaskan satu konflik jadwal. Untuk overbooked, From sampai To adalah rentang hari saat
+// assignee memegang lebih dari MaxParallel task sekaligus dan ParallelTasks jumlah tertingginya
+type TimelineConflict struct {
+	Type          string      `json:"type"`
+	AssigneeID    *uuid.UUID  `json:"assignee_id,omitempty"`
+	TaskIDs       []uuid.UUID `json:"task_ids"`
+	From          *time.Time  `json:"from,omitempty"`
+	To            *time.Time  `json:"to,omitempty"`
+	ParallelTasks int         `json:"parallel_tasks,omitempty"`
+	Message       string      `json:"message"`
+}
+
+type Timeline struct {
+	ProjectID   uuid.UUID          `json:"project_id"`
+	From        *time.Time         `json:"from"`
+	To          *time.Time         `json:"to"`
+	Finish      *time.Time         `json:"finish"`
+	MaxParallel int                `json:"max_parallel"`
+	Lanes       []TimelineLane     `json:"lanes"`
+	Unscheduled []TimelineBar      `json:"unscheduled"`
+	Conflicts   []TimelineConflict `json:"conflicts"`
+	// CriticalPath berisi task yang menentukan Finish, diurutkan dari yang paling awal
+	CriticalPath []uuid.UUID `json:"critical_path"`
+}
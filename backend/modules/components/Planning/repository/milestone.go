@@ -0,0 +1,63 @@
+package planningrepository
+
+import (
+	planningmodel "gintugas/modules/components/Planning/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// milestone dianggap terlambat jika target_date sudah lewat dan masih ada task yang belum done
+func (r *planningRepository) milestoneDetails() *gorm.DB {
+	return r.db.Table("milestones m").
+		Select("m.*," + progressColumns + `,
+			m.target_date < CURRENT_DATE AND COUNT(DISTINCT CASE WHEN t.status <> 'done' THEN t.id END) > 0 as is_overdue`).
+		Joins("LEFT JOIN tasks t ON t.milestone_id = m.id AND t.deleted_at IS NULL AND t.archived_at IS NULL").
+		Group("m.id")
+}
+
+func (r *planningRepository) CreateMilestone(milestone *planningmodel.Milestone) error {
+	return r.db.Create(milestone).Error
+}
+
+func (r *planningRepository) GetMilestoneByID(id uuid.UUID) (*planningmodel.Milestone, error) {
+	var milestone planningmodel.Milestone
+	err := r.db.Where("id = ?", id).First(&milestone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *planningRepository) GetMilestoneDetail(id uuid.UUID) (*planningmodel.MilestoneDetail, error) {
+	var milestone planningmodel.MilestoneDetail
+	err := r.milestoneDetails().
+		Where("m.id = ?", id).
+		Take(&milestone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *planningRepository) GetProjectMilestones(projectID uuid.UUID) ([]planningmodel.MilestoneDetail, error) {
+	milestones := []planningmodel.MilestoneDetail{}
+	err := r.milestoneDetails().
+		Where("m.project_id = ?", projectID).
+		Order("m.target_date ASC, m.created_at ASC").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *planningRepository) UpdateMilestone(milestone *planningmodel.Milestone) error {
+	milestone.UpdatedAt = time.Now()
+	return r.db.Model(milestone).
+		Select("*").
+		Omit("id", "project_id", "created_by", "created_at").
+		Updates(milestone).Error
+}
+
+func (r *planningRepository) DeleteMilestone(id uuid.UUID) error {
+	return r.db.Delete(&planningmodel.Milestone{}, "id = ?", id).Error
+}
@@ -0,0 +1,120 @@
+package planningrepository
+
+import (
+	planningmodel "gintugas/modules/components/Planning/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PlanningRepository interface {
+	CreateSprint(sprint *planningmodel.Sprint) error
+	GetSprintByID(id uuid.UUID) (*planningmodel.Sprint, error)
+	GetSprintDetail(id uuid.UUID) (*planningmodel.SprintDetail, error)
+	GetProjectSprints(projectID uuid.UUID, status string) ([]planningmodel.SprintDetail, error)
+	UpdateSprint(sprint *planningmodel.Sprint) error
+	DeleteSprint(id uuid.UUID) error
+	GetUnfinishedSprintTasks(sprintID uuid.UUID) ([]taskmodel.Task, error)
+
+	CreateMilestone(milestone *planningmodel.Milestone) error
+	GetMilestoneByID(id uuid.UUID) (*planningmodel.Milestone, error)
+	GetMilestoneDetail(id uuid.UUID) (*planningmodel.MilestoneDetail, error)
+	GetProjectMilestones(projectID uuid.UUID) ([]planningmodel.MilestoneDetail, error)
+	UpdateMilestone(milestone *planningmodel.Milestone) error
+	DeleteMilestone(id uuid.UUID) error
+
+	GetTimelineTasks(projectID uuid.UUID, from, to *time.Time) ([]taskmodel.Task, error)
+
+	// Transaction memberi repository planning dan task yang memakai transaksi yang sama
+	Transaction(fn func(repo PlanningRepository, tasks taskrepository.TaskRepository) error) error
+}
+
+type planningRepository struct {
+	db *gorm.DB
+}
+
+func NewPlanningRepository(db *gorm.DB) PlanningRepository {
+	return &planningRepository{db: db}
+}
+
+// progressColumns sama dengan perhitungan progress project di dashboard, alias tabel task harus t
+const progressColumns = `
+	COUNT(DISTINCT t.id) as total_tasks,
+	COUNT(DISTINCT CASE WHEN t.status = 'todo' THEN t.id END) as todo_tasks,
+	COUNT(DISTINCT CASE WHEN t.status = 'in-progress' THEN t.id END) as in_progress_tasks,
+	COUNT(DISTINCT CASE WHEN t.status = 'in-review' THEN t.id END) as in_review_tasks,
+	COUNT(DISTINCT CASE WHEN t.status = 'done' THEN t.id END) as done_tasks,
+	CASE 
+		WHEN COUNT(DISTINCT t.id) = 0 THEN 0
+		ELSE (COUNT(DISTINCT CASE WHEN t.status = 'done' THEN t.id END) * 100.0) / COUNT(DISTINCT t.id)
+	END as progress`
+
+func (r *planningRepository) sprintDetails() *gorm.DB {
+	return r.db.Table("sprints s").
+		Select("s.*," + progressColumns).
+		Joins("LEFT JOIN tasks t ON t.sprint_id = s.id AND t.deleted_at IS NULL AND t.archived_at IS NULL").
+		Group("s.id")
+}
+
+func (r *planningRepository) CreateSprint(sprint *planningmodel.Sprint) error {
+	return r.db.Create(sprint).Error
+}
+
+func (r *planningRepository) GetSprintByID(id uuid.UUID) (*planningmodel.Sprint, error) {
+	var sprint planningmodel.Sprint
+	err := r.db.Where("id = ?", id).First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *planningRepository) GetSprintDetail(id uuid.UUID) (*planningmodel.SprintDetail, error) {
+	var sprint planningmodel.SprintDetail
+	err := r.sprintDetails().
+		Where("s.id = ?", id).
+		Take(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *planningRepository) GetProjectSprints(projectID uuid.UUID, status string) ([]planningmodel.SprintDetail, error) {
+	sprints := []planningmodel.SprintDetail{}
+	query := r.sprintDetails().Where("s.project_id = ?", projectID)
+	if status != "" {
+		query = query.Where("s.status = ?", status)
+	}
+	err := query.Order("s.start_date ASC, s.created_at ASC").Find(&sprints).Error
+	return sprints, err
+}
+
+func (r *planningRepository) UpdateSprint(sprint *planningmodel.Sprint) error {
+	sprint.UpdatedAt = time.Now()
+	return r.db.Model(sprint).
+		Select("*").
+		Omit("id", "project_id", "created_by", "created_at").
+		Updates(sprint).Error
+}
+
+func (r *planningRepository) DeleteSprint(id uuid.UUID) error {
+	return r.db.Delete(&planningmodel.Sprint{}, "id = ?", id).Error
+}
+
+func (r *planningRepository) GetUnfinishedSprintTasks(sprintID uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.db.Where("sprint_id = ? AND status <> ?", sprintID, "done").
+		Order("created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *planningRepository) Transaction(fn func(repo PlanningRepository, tasks taskrepository.TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&planningRepository{db: tx}, taskrepository.NewTaskRepository(tx))
+	})
+}
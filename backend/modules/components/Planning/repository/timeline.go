@@ -0,0 +1,35 @@
+package planningrepository
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetTimelineTasks mengambil task project yang belum diarsipkan beserta assignee-nya. from dan to membatasi task
+// yang rentang tanggalnya beririsan, task tanpa tanggal selalu ikut supaya bisa tampil sebagai unscheduled.
+// LEAST/GREATEST di postgres mengabaikan NULL sehingga task yang hanya punya satu tanggal tetap terbaca
+func (r *planningRepository) GetTimelineTasks(projectID uuid.UUID, from, to *time.Time) ([]taskmodel.Task, error) {
+	query := r.db.Model(&taskmodel.Task{}).
+		Where("tasks.project_id = ? AND tasks.archived_at IS NULL", projectID).
+		Preload("Assignees", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_primary DESC, created_at ASC")
+		}).
+		Preload("Assignees.User")
+
+	const unscheduled = "tasks.start_date IS NULL AND tasks.due_date IS NULL"
+	if from != nil {
+		query = query.Where(unscheduled+" OR GREATEST(tasks.start_date, tasks.due_date) >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where(unscheduled+" OR LEAST(tasks.start_date, tasks.due_date) <= ?", *to)
+	}
+
+	var tasks []taskmodel.Task
+	err := query.
+		Order("LEAST(tasks.start_date, tasks.due_date) ASC NULLS LAST, tasks.created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
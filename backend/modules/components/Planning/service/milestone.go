@@ -0,0 +1,155 @@
+package planningservice
+
+import (
+	"errors"
+	patch "gintugas/modules/components/Patch"
+	planningmodel "gintugas/modules/components/Planning/model"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (s *planningService) CreateMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	var req planningmodel.MilestoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	name, err := validateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	targetDate, err := parseDate("target_date", req.TargetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, _ := currentUserID(ctx)
+	milestone := &planningmodel.Milestone{
+		ProjectID:   projectUUID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		TargetDate:  targetDate,
+		CreatedBy:   &userUUID,
+	}
+
+	if err := s.repo.CreateMilestone(milestone); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMilestoneDetail(milestone.ID)
+}
+
+func (s *planningService) GetProjectMilestones(ctx *gin.Context) ([]planningmodel.MilestoneDetail, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectReader(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetProjectMilestones(projectUUID)
+}
+
+func (s *planningService) loadMilestone(ctx *gin.Context) (*planningmodel.Milestone, error) {
+	milestoneUUID, err := uuid.Parse(ctx.Param("milestone_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format milestone ID")
+	}
+
+	milestone, err := s.repo.GetMilestoneByID(milestoneUUID)
+	if err != nil {
+		return nil, errors.New("milestone tidak ditemukan")
+	}
+	return milestone, nil
+}
+
+func (s *planningService) GetMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error) {
+	milestone, err := s.loadMilestone(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectReader(ctx, milestone.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMilestoneDetail(milestone.ID)
+}
+
+// UpdateMilestone mengubah name, description dan target_date (JSON Merge Patch)
+func (s *planningService) UpdateMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error) {
+	milestone, err := s.loadMilestone(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, milestone.ProjectID); err != nil {
+		return nil, err
+	}
+
+	doc, err := patch.Bind(ctx, "name", "description", "target_date")
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Has("name") {
+		name, err := doc.String("name", false)
+		if err != nil {
+			return nil, err
+		}
+		if milestone.Name, err = validateName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Has("description") {
+		description, err := doc.String("description", true)
+		if err != nil {
+			return nil, err
+		}
+		milestone.Description = strings.TrimSpace(description)
+	}
+
+	if doc.Has("target_date") {
+		value, err := doc.String("target_date", false)
+		if err != nil {
+			return nil, err
+		}
+		if milestone.TargetDate, err = parseDate("target_date", value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMilestone(milestone); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMilestoneDetail(milestone.ID)
+}
+
+// DeleteMilestone: task di milestone tetap ada, milestone_id menjadi kosong
+func (s *planningService) DeleteMilestone(ctx *gin.Context) error {
+	milestone, err := s.loadMilestone(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateProjectManager(ctx, milestone.ProjectID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMilestone(milestone.ID)
+}
@@ -0,0 +1,421 @@
+package planningservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	patch "gintugas/modules/components/Patch"
+	planningmodel "gintugas/modules/components/Planning/model"
+	planningrepository "gintugas/modules/components/Planning/repository"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PlanningService interface {
+	CreateSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error)
+	GetProjectSprints(ctx *gin.Context) ([]planningmodel.SprintDetail, error)
+	GetSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error)
+	UpdateSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error)
+	DeleteSprint(ctx *gin.Context) error
+	StartSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error)
+	CloseSprint(ctx *gin.Context) (*planningmodel.CloseSprintResult, error)
+
+	CreateMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error)
+	GetProjectMilestones(ctx *gin.Context) ([]planningmodel.MilestoneDetail, error)
+	GetMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error)
+	UpdateMilestone(ctx *gin.Context) (*planningmodel.MilestoneDetail, error)
+	DeleteMilestone(ctx *gin.Context) error
+
+	GetProjectTimeline(ctx *gin.Context) (*planningmodel.Timeline, error)
+}
+
+type planningService struct {
+	repo        planningrepository.PlanningRepository
+	taskRepo    taskrepository.TaskRepository
+	maxParallel int
+}
+
+func NewPlanningService(repo planningrepository.PlanningRepository, taskRepo taskrepository.TaskRepository, maxParallel int) PlanningService {
+	return &planningService{
+		repo:        repo,
+		taskRepo:    taskRepo,
+		maxParallel: maxParallel,
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+func (s *planningService) validateProjectManager(ctx *gin.Context, projectID uuid.UUID) error {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	if project.ManagerID != userUUID {
+		return errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	// validateProjectManager hanya dipakai operasi yang mengubah sprint atau milestone
+	if project.ArchivedAt != nil {
+		return archive.ErrReadOnly
+	}
+	return nil
+}
+
+// validateProjectReader: sprint dan milestone bisa dilihat admin, manager dan member project
+func (s *planningService) validateProjectReader(ctx *gin.Context, projectID uuid.UUID) error {
+	if ctx.GetString("user_role") == "admin" {
+		return nil
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	isMember, err := s.taskRepo.IsProjectMember(projectID, userUUID)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa member project: %v", err)
+	}
+	if !isMember {
+		return errors.New("forbidden: hanya member project yang bisa melihat data ini")
+	}
+	return nil
+}
+
+func parseDate(field, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s harus berformat YYYY-MM-DD", field)
+	}
+	return date, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name tidak boleh kosong")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", errors.New("name maksimal 100 karakter")
+	}
+	return name, nil
+}
+
+func (s *planningService) CreateSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	var req planningmodel.SprintRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	name, err := validateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	startDate, err := parseDate("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseDate("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date tidak boleh sebelum start_date")
+	}
+
+	userUUID, _ := currentUserID(ctx)
+	sprint := &planningmodel.Sprint{
+		ProjectID: projectUUID,
+		Name:      name,
+		Goal:      strings.TrimSpace(req.Goal),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Status:    planningmodel.SprintPlanned,
+		CreatedBy: &userUUID,
+	}
+
+	if err := s.repo.CreateSprint(sprint); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSprintDetail(sprint.ID)
+}
+
+func (s *planningService) GetProjectSprints(ctx *gin.Context) ([]planningmodel.SprintDetail, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectReader(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	status := ctx.Query("status")
+	if status != "" && status != planningmodel.SprintPlanned && status != planningmodel.SprintActive && status != planningmodel.SprintClosed {
+		return nil, fmt.Errorf("status sprint tidak valid: %s", status)
+	}
+
+	return s.repo.GetProjectSprints(projectUUID, status)
+}
+
+func (s *planningService) loadSprint(ctx *gin.Context) (*planningmodel.Sprint, error) {
+	sprintUUID, err := uuid.Parse(ctx.Param("sprint_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format sprint ID")
+	}
+
+	sprint, err := s.repo.GetSprintByID(sprintUUID)
+	if err != nil {
+		return nil, errors.New("sprint tidak ditemukan")
+	}
+	return sprint, nil
+}
+
+func (s *planningService) GetSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error) {
+	sprint, err := s.loadSprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectReader(ctx, sprint.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSprintDetail(sprint.ID)
+}
+
+// UpdateSprint mengubah name, goal, start_date dan end_date (JSON Merge Patch). Sprint yang sudah ditutup tidak bisa diubah
+func (s *planningService) UpdateSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error) {
+	sprint, err := s.loadSprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, sprint.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status == planningmodel.SprintClosed {
+		return nil, errors.New("sprint yang sudah ditutup tidak bisa diubah")
+	}
+
+	doc, err := patch.Bind(ctx, "name", "goal", "start_date", "end_date")
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Has("name") {
+		name, err := doc.String("name", false)
+		if err != nil {
+			return nil, err
+		}
+		if sprint.Name, err = validateName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Has("goal") {
+		goal, err := doc.String("goal", true)
+		if err != nil {
+			return nil, err
+		}
+		sprint.Goal = strings.TrimSpace(goal)
+	}
+
+	for field, target := range map[string]*time.Time{"start_date": &sprint.StartDate, "end_date": &sprint.EndDate} {
+		if !doc.Has(field) {
+			continue
+		}
+		value, err := doc.String(field, false)
+		if err != nil {
+			return nil, err
+		}
+		if *target, err = parseDate(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if sprint.EndDate.Before(sprint.StartDate) {
+		return nil, errors.New("end_date tidak boleh sebelum start_date")
+	}
+
+	if err := s.repo.UpdateSprint(sprint); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSprintDetail(sprint.ID)
+}
+
+// DeleteSprint: task di sprint kembali ke backlog. Sprint yang sedang berjalan harus ditutup dulu
+func (s *planningService) DeleteSprint(ctx *gin.Context) error {
+	sprint, err := s.loadSprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateProjectManager(ctx, sprint.ProjectID); err != nil {
+		return err
+	}
+
+	if sprint.Status == planningmodel.SprintActive {
+		return errors.New("sprint yang sedang berjalan tidak bisa dihapus, tutup sprint terlebih dahulu")
+	}
+
+	return s.repo.DeleteSprint(sprint.ID)
+}
+
+// StartSprint mengaktifkan sprint, satu project hanya boleh punya satu sprint aktif
+func (s *planningService) StartSprint(ctx *gin.Context) (*planningmodel.SprintDetail, error) {
+	sprint, err := s.loadSprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, sprint.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status != planningmodel.SprintPlanned {
+		return nil, fmt.Errorf("hanya sprint berstatus planned yang bisa dimulai (status: %s)", sprint.Status)
+	}
+
+	active, err := s.repo.GetProjectSprints(sprint.ProjectID, planningmodel.SprintActive)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		return nil, fmt.Errorf("sprint %s masih berjalan, tutup sprint tersebut terlebih dahulu", active[0].Name)
+	}
+
+	now := time.Now()
+	sprint.Status = planningmodel.SprintActive
+	sprint.StartedAt = &now
+
+	if err := s.repo.UpdateSprint(sprint); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSprintDetail(sprint.ID)
+}
+
+// CloseSprint menutup sprint aktif. Task yang belum done dipindah ke carry_over_to atau kembali ke backlog
+func (s *planningService) CloseSprint(ctx *gin.Context) (*planningmodel.CloseSprintResult, error) {
+	sprint, err := s.loadSprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, sprint.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status != planningmodel.SprintActive {
+		return nil, fmt.Errorf("hanya sprint yang sedang berjalan yang bisa ditutup (status: %s)", sprint.Status)
+	}
+
+	// body boleh kosong
+	var req planningmodel.CloseSprintRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if req.CarryOverTo != nil {
+		target, err := s.repo.GetSprintByID(*req.CarryOverTo)
+		if err != nil {
+			return nil, errors.New("sprint tujuan carry over tidak ditemukan")
+		}
+		if target.ID == sprint.ID || target.ProjectID != sprint.ProjectID {
+			return nil, errors.New("sprint tujuan carry over harus sprint lain di project yang sama")
+		}
+		if target.Status == planningmodel.SprintClosed {
+			return nil, errors.New("sprint tujuan carry over sudah ditutup")
+		}
+	}
+
+	actor, _ := currentUserID(ctx)
+	carried := 0
+	err = s.repo.Transaction(func(repo planningrepository.PlanningRepository, tasks taskrepository.TaskRepository) error {
+		now := time.Now()
+		sprint.Status = planningmodel.SprintClosed
+		sprint.ClosedAt = &now
+		if err := repo.UpdateSprint(sprint); err != nil {
+			return err
+		}
+
+		unfinished, err := repo.GetUnfinishedSprintTasks(sprint.ID)
+		if err != nil {
+			return err
+		}
+
+		for i := range unfinished {
+			task := &unfinished[i]
+			before := *task
+
+			task.SprintID = req.CarryOverTo
+			task.UpdatedAt = now
+			if err := tasks.UpdateTask(task); err != nil {
+				return err
+			}
+			err := tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+				TaskID:    task.ID,
+				ProjectID: task.ProjectID,
+				TaskTitle: task.Title,
+				ActorID:   &actor,
+				Action:    taskmodel.HistoryUpdated,
+				Changes:   taskmodel.DiffTask(&before, task),
+			})
+			if err != nil {
+				return err
+			}
+			carried++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := s.repo.GetSprintDetail(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &planningmodel.CloseSprintResult{
+		Sprint:        detail,
+		CarriedOver:   carried,
+		CarriedOverTo: req.CarryOverTo,
+	}, nil
+}
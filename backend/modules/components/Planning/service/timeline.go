@@ -0,0 +1,364 @@
+package planningservice
+
+import (
+	"errors"
+	"fmt"
+	planningmodel "gintugas/modules/components/Planning/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxParallelTasks dipakai jika TIMELINE_MAX_PARALLEL_TASKS tidak diisi: satu assignee dianggap overbooked
+// begitu memegang dua task yang rentang tanggalnya beririsan
+const DefaultMaxParallelTasks = 1
+
+// MaxParallelTasksFromEnv membaca TIMELINE_MAX_PARALLEL_TASKS, nilai kosong atau tidak valid memakai DefaultMaxParallelTasks
+func MaxParallelTasksFromEnv() int {
+	limit, err := strconv.Atoi(os.Getenv("TIMELINE_MAX_PARALLEL_TASKS"))
+	if err != nil || limit <= 0 {
+		return DefaultMaxParallelTasks
+	}
+	return limit
+}
+
+const day = 24 * time.Hour
+
+// GetProjectTimeline mengembalikan task project sebagai batang tanggal per assignee untuk tampilan gantt.
+// Query: from dan to (YYYY-MM-DD) membatasi rentang, max_parallel mengganti batas task bersamaan per assignee
+func (s *planningService) GetProjectTimeline(ctx *gin.Context) (*planningmodel.Timeline, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectReader(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	var from, to *time.Time
+	if value := ctx.Query("from"); value != "" {
+		date, err := parseDate("from", value)
+		if err != nil {
+			return nil, err
+		}
+		from = &date
+	}
+	if value := ctx.Query("to"); value != "" {
+		date, err := parseDate("to", value)
+		if err != nil {
+			return nil, err
+		}
+		to = &date
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, errors.New("to tidak boleh sebelum from")
+	}
+
+	maxParallel := s.maxParallel
+	if value := ctx.Query("max_parallel"); value != "" {
+		maxParallel, err = strconv.Atoi(value)
+		if err != nil || maxParallel <= 0 {
+			return nil, errors.New("max_parallel harus bilangan bulat positif")
+		}
+	}
+
+	tasks, err := s.repo.GetTimelineTasks(projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimeline(projectUUID, tasks, from, to, maxParallel), nil
+}
+
+// timelineItem adalah batang task sebelum dibagi ke lane assignee
+type timelineItem struct {
+	task  *taskmodel.Task
+	start time.Time
+	end   time.Time
+}
+
+func buildTimeline(projectID uuid.UUID, tasks []taskmodel.Task, from, to *time.Time, maxParallel int) *planningmodel.Timeline {
+	timeline := &planningmodel.Timeline{
+		ProjectID:    projectID,
+		From:         from,
+		To:           to,
+		MaxParallel:  maxParallel,
+		Lanes:        []planningmodel.TimelineLane{},
+		Unscheduled:  []planningmodel.TimelineBar{},
+		Conflicts:    []planningmodel.TimelineConflict{},
+		CriticalPath: []uuid.UUID{},
+	}
+
+	items := []timelineItem{}
+	taskConflicts := map[uuid.UUID][]string{}
+	for i := range tasks {
+		task := &tasks[i]
+		if task.StartDate == nil && task.DueDate == nil {
+			timeline.Unscheduled = append(timeline.Unscheduled, newTimelineBar(task, nil, nil))
+			continue
+		}
+
+		item := timelineItem{task: task}
+		switch {
+		case task.StartDate == nil:
+			item.start, item.end = *task.DueDate, *task.DueDate
+		case task.DueDate == nil:
+			item.start, item.end = *task.StartDate, *task.StartDate
+		default:
+			item.start, item.end = *task.StartDate, *task.DueDate
+		}
+
+		// batang tetap digambar dari tanggal terkecil supaya task yang jadwalnya terbalik masih terlihat
+		if item.end.Before(item.start) {
+			item.start, item.end = item.end, item.start
+			taskConflicts[task.ID] = append(taskConflicts[task.ID], planningmodel.ConflictStartAfterDue)
+			timeline.Conflicts = append(timeline.Conflicts, planningmodel.TimelineConflict{
+				Type:    planningmodel.ConflictStartAfterDue,
+				TaskIDs: []uuid.UUID{task.ID},
+				From:    task.StartDate,
+				To:      task.DueDate,
+				Message: fmt.Sprintf("task %q dimulai %s, setelah due date %s", task.Title, task.StartDate.Format("2006-01-02"), task.DueDate.Format("2006-01-02")),
+			})
+		}
+		items = append(items, item)
+	}
+
+	critical := criticalTasks(items, timeline)
+
+	lanes := map[uuid.UUID]*planningmodel.TimelineLane{}
+	laneItems := map[uuid.UUID][]timelineItem{}
+	unassigned := &planningmodel.TimelineLane{Bars: []planningmodel.TimelineBar{}}
+	for _, item := range items {
+		if len(item.task.Assignees) == 0 {
+			unassigned.Bars = append(unassigned.Bars, newTimelineBar(item.task, &item.start, &item.end))
+			continue
+		}
+		for _, assignee := range item.task.Assignees {
+			lane, ok := lanes[assignee.UserID]
+			if !ok {
+				userID := assignee.UserID
+				lane = &planningmodel.TimelineLane{AssigneeID: &userID, Bars: []planningmodel.TimelineBar{}}
+				if assignee.User != nil {
+					lane.Username = assignee.User.Username
+				}
+				lanes[assignee.UserID] = lane
+			}
+			bar := newTimelineBar(item.task, &item.start, &item.end)
+			bar.IsPrimary = assignee.IsPrimary
+			lane.Bars = append(lane.Bars, bar)
+			laneItems[assignee.UserID] = append(laneItems[assignee.UserID], item)
+		}
+	}
+
+	sorted := make([]*planningmodel.TimelineLane, 0, len(lanes))
+	for _, lane := range lanes {
+		sorted = append(sorted, lane)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Username) < strings.ToLower(sorted[j].Username)
+	})
+
+	for _, lane := range sorted {
+		overbooked := findOverbooked(lane.AssigneeID, laneItems[*lane.AssigneeID], maxParallel)
+		timeline.Conflicts = append(timeline.Conflicts, overbooked...)
+
+		// tanda overbooked hanya berlaku di lane assignee yang bersangkutan
+		flagged := map[uuid.UUID]bool{}
+		for _, conflict := range overbooked {
+			for _, id := range conflict.TaskIDs {
+				flagged[id] = true
+			}
+		}
+		for i := range lane.Bars {
+			bar := &lane.Bars[i]
+			bar.Critical = critical[bar.TaskID]
+			bar.Conflicts = append(bar.Conflicts, taskConflicts[bar.TaskID]...)
+			if flagged[bar.TaskID] {
+				bar.Conflicts = append(bar.Conflicts, planningmodel.ConflictOverbooked)
+			}
+		}
+		timeline.Lanes = append(timeline.Lanes, *lane)
+	}
+
+	if len(unassigned.Bars) > 0 {
+		for i := range unassigned.Bars {
+			bar := &unassigned.Bars[i]
+			bar.Critical = critical[bar.TaskID]
+			bar.Conflicts = append(bar.Conflicts, taskConflicts[bar.TaskID]...)
+		}
+		timeline.Lanes = append(timeline.Lanes, *unassigned)
+	}
+
+	return timeline
+}
+
+func newTimelineBar(task *taskmodel.Task, start, end *time.Time) planningmodel.TimelineBar {
+	return planningmodel.TimelineBar{
+		TaskID:      task.ID,
+		Title:       task.Title,
+		Status:      task.Status,
+		Start:       start,
+		End:         end,
+		StartDate:   task.StartDate,
+		DueDate:     task.DueDate,
+		SprintID:    task.SprintID,
+		MilestoneID: task.MilestoneID,
+		Conflicts:   []string{},
+	}
+}
+
+// findOverbooked menyapu hari-hari di lane satu assignee dan mengembalikan setiap rentang hari saat task yang
+// belum done lebih dari maxParallel. Batang inklusif, jadi task berakhir di hari berikutnya setelah End
+func findOverbooked(assigneeID *uuid.UUID, items []timelineItem, maxParallel int) []planningmodel.TimelineConflict {
+	type event struct {
+		date  time.Time
+		delta int
+		id    uuid.UUID
+	}
+	events := []event{}
+	for _, item := range items {
+		if item.task.Status == "done" {
+			continue
+		}
+		events = append(events,
+			event{date: item.start, delta: 1, id: item.task.ID},
+			event{date: item.end.Add(day), delta: -1, id: item.task.ID},
+		)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].date.Equal(events[j].date) {
+			return events[i].date.Before(events[j].date)
+		}
+		// task yang selesai diproses dulu supaya task berurutan tidak dihitung beririsan
+		return events[i].delta < events[j].delta
+	})
+
+	conflicts := []planningmodel.TimelineConflict{}
+	active := map[uuid.UUID]bool{}
+	var open *planningmodel.TimelineConflict
+	var involved map[uuid.UUID]bool
+	for i := 0; i < len(events); {
+		date := events[i].date
+		for ; i < len(events) && events[i].date.Equal(date); i++ {
+			if events[i].delta > 0 {
+				active[events[i].id] = true
+			} else {
+				delete(active, events[i].id)
+			}
+		}
+
+		if len(active) > maxParallel {
+			if open == nil {
+				start := date
+				open = &planningmodel.TimelineConflict{
+					Type:       planningmodel.ConflictOverbooked,
+					AssigneeID: assigneeID,
+					From:       &start,
+				}
+				involved = map[uuid.UUID]bool{}
+			}
+			for id := range active {
+				if !involved[id] {
+					involved[id] = true
+					open.TaskIDs = append(open.TaskIDs, id)
+				}
+			}
+			if len(active) > open.ParallelTasks {
+				open.ParallelTasks = len(active)
+			}
+			continue
+		}
+
+		if open != nil {
+			end := date.Add(-day)
+			open.To = &end
+			open.Message = fmt.Sprintf("assignee memegang %d task bersamaan pada %s sampai %s, batasnya %d",
+				open.ParallelTasks, open.From.Format("2006-01-02"), end.Format("2006-01-02"), maxParallel)
+			conflicts = append(conflicts, *open)
+			open = nil
+		}
+	}
+	return conflicts
+}
+
+// criticalTasks mengisi Finish dan CriticalPath timeline. Task belum punya dependency, jadi urutan kerja diambil dari
+// assignee yang sama: mulai dari task yang berakhir di Finish, task lain milik assignee yang sama yang berakhir tepat
+// sebelum (tanpa jeda) task kritis dimulai ikut kritis, karena keterlambatannya langsung menggeser Finish.
+// Task done tidak dihitung karena tidak lagi memengaruhi jadwal
+func criticalTasks(items []timelineItem, timeline *planningmodel.Timeline) map[uuid.UUID]bool {
+	critical := map[uuid.UUID]bool{}
+
+	var open []timelineItem
+	var finish time.Time
+	for _, item := range items {
+		if item.task.Status == "done" {
+			continue
+		}
+		open = append(open, item)
+		if item.end.After(finish) {
+			finish = item.end
+		}
+	}
+	if len(open) == 0 {
+		return critical
+	}
+	timeline.Finish = &finish
+
+	queue := []timelineItem{}
+	for _, item := range open {
+		if item.end.Equal(finish) {
+			critical[item.task.ID] = true
+			queue = append(queue, item)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, item := range open {
+			if critical[item.task.ID] || !sharesAssignee(item.task, current.task) {
+				continue
+			}
+			// berakhir sehari sebelum atau di dalam rentang awal task kritis
+			if item.start.Before(current.start) && !item.end.Add(day).Before(current.start) {
+				critical[item.task.ID] = true
+				queue = append(queue, item)
+			}
+		}
+	}
+
+	path := []timelineItem{}
+	for _, item := range open {
+		if critical[item.task.ID] {
+			path = append(path, item)
+		}
+	}
+	sort.SliceStable(path, func(i, j int) bool {
+		if !path[i].start.Equal(path[j].start) {
+			return path[i].start.Before(path[j].start)
+		}
+		return path[i].end.Before(path[j].end)
+	})
+	for _, item := range path {
+		timeline.CriticalPath = append(timeline.CriticalPath, item.task.ID)
+	}
+	return critical
+}
+
+func sharesAssignee(a, b *taskmodel.Task) bool {
+	for _, left := range a.Assignees {
+		for _, right := range b.Assignees {
+			if left.UserID == right.UserID {
+				return true
+			}
+		}
+	}
+	return false
+}
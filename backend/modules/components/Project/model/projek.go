@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Project struct {
@@ -13,7 +14,12 @@ type Project struct {
 	Description string            `json:"deskripsi" gorm:"column:deskripsi;type:text"`
 	ManagerID   uuid.UUID         `json:"manager_id" gorm:"column:manager_id;type:uuid"`
 	Manager     usermodels.User   `json:"manager,omitempty" gorm:"foreignKey:ManagerID"`
+	Version     int               `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
+	ArchivedAt  *time.Time        `json:"archived_at"`
+	ArchivedBy  *uuid.UUID        `json:"archived_by" gorm:"type:uuid"`
+	DeletedAt   gorm.DeletedAt    `json:"-"`
+	DeletedBy   *uuid.UUID        `json:"-" gorm:"type:uuid"`
 	Members     []usermodels.User `json:"members,omitempty" gorm:"many2many:project_members;"`
 }
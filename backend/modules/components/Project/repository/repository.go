@@ -3,8 +3,11 @@ package projectrepo
 import (
 	"database/sql"
 	"errors"
+	archive "gintugas/modules/components/Archive"
 	. "gintugas/modules/components/Auth/model"
+	concurrency "gintugas/modules/components/Concurrency"
 	. "gintugas/modules/components/Project/model"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,11 +16,15 @@ type Repository interface {
 	GetManagerByProjectRepository(projectID uuid.UUID) (User, error)
 	GetUserByIDRepository(userID uuid.UUID) (User, error)
 	CreateProjekRepository(projek Project) (Project, error)
-	GetAllProjekRepository() (result []Project, err error)
+	GetAllProjekRepository(archived archive.Filter) (result []Project, err error)
 	GetProjekRepository(id uuid.UUID) (Project, error)
-	DeleteProjekRepository(id uuid.UUID) (err error)
+	// DeleteProjekRepository memindahkan project beserta task-nya ke trash
+	DeleteProjekRepository(id uuid.UUID, deletedBy uuid.UUID) (err error)
 	UpdateProjekRepository(projek Project) (Project, error)
 	GetProjekByIDRepository(id uuid.UUID) (Project, error)
+
+	// ArchiveProjekRepository mengisi archived_at (archivedBy nil berarti batal arsip), versi project ikut naik
+	ArchiveProjekRepository(id uuid.UUID, version int, archivedBy *uuid.UUID) (Project, error)
 }
 
 type repository struct {
@@ -32,11 +39,11 @@ func (r *repository) CreateProjekRepository(projek Project) (Project, error) {
 	query := `
         INSERT INTO projects (nama, deskripsi, manager_id) 
         VALUES ($1, $2, $3) 
-        RETURNING id, created_at, updated_at
+        RETURNING id, version, created_at, updated_at
     `
 
 	err := r.db.QueryRow(query, projek.Nama, projek.Description, projek.ManagerID).
-		Scan(&projek.ID, &projek.CreatedAt, &projek.UpdatedAt)
+		Scan(&projek.ID, &projek.Version, &projek.CreatedAt, &projek.UpdatedAt)
 
 	if err != nil {
 		return Project{}, err
@@ -45,21 +52,29 @@ func (r *repository) CreateProjekRepository(projek Project) (Project, error) {
 	return projek, nil
 }
 
-func (r *repository) GetAllProjekRepository() (result []Project, err error) {
+func (r *repository) GetAllProjekRepository(archived archive.Filter) (result []Project, err error) {
 	query := `
 		SELECT
 			b.id,
 			b.nama,
 			b.deskripsi,
 			b.manager_id,
+			b.version,
 			b.created_at,
+			b.archived_at,
+			b.archived_by,
 			k.id as manager_user_id,      
 			k.username as manager_username,
 			k.email as manager_email,
 			k.role as manager_role      
 		FROM projects b
 		LEFT JOIN users k ON b.manager_id = k.id
-		ORDER BY b.id`
+		WHERE b.deleted_at IS NULL`
+
+	if condition := archived.Condition("b.archived_at IS NOT NULL"); condition != "" {
+		query += " AND " + condition
+	}
+	query += " ORDER BY b.id"
 
 	rows, err := r.db.Query(query)
 	if err != nil {
@@ -75,7 +90,10 @@ func (r *repository) GetAllProjekRepository() (result []Project, err error) {
 			&projek.Nama,
 			&projek.Description,
 			&projek.ManagerID,
+			&projek.Version,
 			&projek.CreatedAt,
+			&projek.ArchivedAt,
+			&projek.ArchivedBy,
 			&projek.Manager.ID,
 			&projek.Manager.Username,
 			&projek.Manager.Email,
@@ -97,14 +115,17 @@ func (r *repository) GetProjekRepository(id uuid.UUID) (Project, error) {
             b.nama,
             b.deskripsi,
             b.manager_id,
+            b.version,
             b.created_at,
+            b.archived_at,
+            b.archived_by,
             k.id as manager_user_id,      
             k.username as manager_username,
             k.email as manager_email,
             k.role as manager_role
         FROM projects b
         LEFT JOIN users k ON b.manager_id = k.id
-        WHERE b.id = $1`
+        WHERE b.id = $1 AND b.deleted_at IS NULL`
 
 	var projek Project
 	err := r.db.QueryRow(query, id).Scan(
@@ -112,7 +133,10 @@ func (r *repository) GetProjekRepository(id uuid.UUID) (Project, error) {
 		&projek.Nama,
 		&projek.Description,
 		&projek.ManagerID,
+		&projek.Version,
 		&projek.CreatedAt,
+		&projek.ArchivedAt,
+		&projek.ArchivedBy,
 		&projek.Manager.ID,
 		&projek.Manager.Username,
 		&projek.Manager.Email,
@@ -130,10 +154,10 @@ func (r *repository) GetProjekRepository(id uuid.UUID) (Project, error) {
 }
 
 func (r *repository) GetProjekByIDRepository(id uuid.UUID) (Project, error) {
-	query := "SELECT id, nama, deskripsi, manager_id FROM projects WHERE id = $1"
+	query := "SELECT id, nama, deskripsi, manager_id, version, archived_at FROM projects WHERE id = $1 AND deleted_at IS NULL"
 
 	var project Project
-	err := r.db.QueryRow(query, id).Scan(&project.ID, &project.Nama, &project.Description, &project.ManagerID)
+	err := r.db.QueryRow(query, id).Scan(&project.ID, &project.Nama, &project.Description, &project.ManagerID, &project.Version, &project.ArchivedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -183,45 +207,88 @@ func (r *repository) GetUserByIDRepository(userID uuid.UUID) (User, error) {
 	return user, nil
 }
 
+// UpdateProjekRepository hanya berhasil jika projk.Version masih sama dengan versi di database
 func (r *repository) UpdateProjekRepository(projk Project) (Project, error) {
-	sql := `UPDATE projects 
-            SET nama = $1, deskripsi = $2, manager_id = $3, updated_at = NOW() 
-            WHERE id = $4 
-            RETURNING id, nama, deskripsi, manager_id, created_at, updated_at`
+	query := `UPDATE projects 
+            SET nama = $1, deskripsi = $2, manager_id = $3, updated_at = NOW(), version = version + 1 
+            WHERE id = $4 AND version = $5 AND deleted_at IS NULL 
+            RETURNING id, nama, deskripsi, manager_id, version, created_at, updated_at`
 
 	var updatedProjek Project
-	err := r.db.QueryRow(sql, projk.Nama, projk.Description, projk.ManagerID, projk.ID).
+	err := r.db.QueryRow(query, projk.Nama, projk.Description, projk.ManagerID, projk.ID, projk.Version).
 		Scan(&updatedProjek.ID,
 			&updatedProjek.Nama,
 			&updatedProjek.Description,
 			&updatedProjek.ManagerID,
+			&updatedProjek.Version,
 			&updatedProjek.CreatedAt,
 			&updatedProjek.UpdatedAt)
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return Project{}, concurrency.ErrPreconditionFailed
+		}
 		return Project{}, errors.New("gagal mengupdate projek: " + err.Error())
 	}
 
 	return updatedProjek, nil
 }
 
-func (r *repository) DeleteProjekRepository(id uuid.UUID) (err error) {
+// ArchiveProjekRepository memakai optimistic locking yang sama dengan UpdateProjekRepository
+func (r *repository) ArchiveProjekRepository(id uuid.UUID, version int, archivedBy *uuid.UUID) (Project, error) {
+	query := `UPDATE projects
+            SET archived_at = CASE WHEN $3::uuid IS NULL THEN NULL ELSE NOW() END, archived_by = $3,
+                updated_at = NOW(), version = version + 1
+            WHERE id = $1 AND version = $2 AND deleted_at IS NULL
+            RETURNING id, nama, deskripsi, manager_id, version, created_at, updated_at, archived_at, archived_by`
+
+	var project Project
+	err := r.db.QueryRow(query, id, version, archivedBy).
+		Scan(&project.ID,
+			&project.Nama,
+			&project.Description,
+			&project.ManagerID,
+			&project.Version,
+			&project.CreatedAt,
+			&project.UpdatedAt,
+			&project.ArchivedAt,
+			&project.ArchivedBy)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Project{}, concurrency.ErrPreconditionFailed
+		}
+		return Project{}, errors.New("gagal mengarsipkan projek: " + err.Error())
+	}
 
-	sql := "DELETE FROM projects WHERE id = $1"
+	return project, nil
+}
 
-	result, err := r.db.Exec(sql, id)
+// DeleteProjekRepository memberi task project deleted_at yang sama dengan project-nya,
+// sehingga restore project hanya mengembalikan task yang ikut terhapus bersama project
+func (r *repository) DeleteProjekRepository(id uuid.UUID, deletedBy uuid.UUID) (err error) {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return errors.New("gagal menghapus projects: " + err.Error())
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	var deletedAt time.Time
+	err = tx.QueryRow(`UPDATE projects SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at`, id, deletedBy).Scan(&deletedAt)
 	if err != nil {
-		return errors.New("gagal memeriksa rows affected: " + err.Error())
+		if err == sql.ErrNoRows {
+			return errors.New("projects tidak ditemukan")
+		}
+		return errors.New("gagal menghapus projects: " + err.Error())
 	}
 
-	if rowsAffected == 0 {
-		return errors.New("projects tidak ditemukan")
+	_, err = tx.Exec(`UPDATE tasks SET deleted_at = $2, deleted_by = $3
+		WHERE project_id = $1 AND deleted_at IS NULL`, id, deletedAt, deletedBy)
+	if err != nil {
+		return errors.New("gagal menghapus task projects: " + err.Error())
 	}
 
-	return nil
+	return tx.Commit()
 }
@@ -2,9 +2,14 @@ package projectservice
 
 import (
 	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	concurrency "gintugas/modules/components/Concurrency"
+	patch "gintugas/modules/components/Patch"
 	. "gintugas/modules/components/Project/model"
 	. "gintugas/modules/components/Project/repository"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,7 +20,10 @@ type Service interface {
 	GetAllProjekService(ctx *gin.Context) (result []Project, err error)
 	GetProjekService(ctx *gin.Context) (result Project, err error)
 	UpdateProjekService(ctx *gin.Context) (u Project, err error)
+	PatchProjekService(ctx *gin.Context) (u Project, err error)
 	DeleteProjekService(ctx *gin.Context) (err error)
+	ArchiveProjekService(ctx *gin.Context) (Project, error)
+	UnarchiveProjekService(ctx *gin.Context) (Project, error)
 }
 
 type userService struct {
@@ -75,7 +83,12 @@ func (s *userService) CreateProjekService(ctx *gin.Context) (Project, error) {
 }
 
 func (s *userService) GetAllProjekService(ctx *gin.Context) (result []Project, err error) {
-	projeks, err := s.repository.GetAllProjekRepository()
+	archived, err := archive.ParseFilter(ctx.Query("archived"))
+	if err != nil {
+		return nil, err
+	}
+
+	projeks, err := s.repository.GetAllProjekRepository(archived)
 	if err != nil {
 		return nil, errors.New("gagal mengambil data projek: " + err.Error())
 	}
@@ -129,6 +142,14 @@ func (s *userService) UpdateProjekService(ctx *gin.Context) (u Project, err erro
 		return Project{}, errors.New("forbidden: hanya manager yang bisa update project")
 	}
 
+	if existingProjek.ArchivedAt != nil {
+		return Project{}, archive.ErrReadOnly
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, existingProjek.Version); err != nil {
+		return Project{}, err
+	}
+
 	var projects Project
 	if err := ctx.ShouldBindJSON(&projects); err != nil {
 		return Project{}, errors.New("data request tidak valid")
@@ -143,6 +164,7 @@ func (s *userService) UpdateProjekService(ctx *gin.Context) (u Project, err erro
 	}
 
 	projects.ManagerID = existingProjek.ManagerID
+	projects.Version = existingProjek.Version
 
 	projects.ID = id
 
@@ -159,6 +181,80 @@ func (s *userService) UpdateProjekService(ctx *gin.Context) (u Project, err erro
 	return updatedProject, nil
 }
 
+// PatchProjekService mengubah sebagian field project (JSON Merge Patch), null pada deskripsi mengosongkan deskripsi
+func (s *userService) PatchProjekService(ctx *gin.Context) (u Project, err error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return Project{}, errors.New("ID projek tidak valid")
+	}
+
+	currentUserID, exists := ctx.Get("user_id")
+	if !exists {
+		return Project{}, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(currentUserID))
+	if err != nil {
+		return Project{}, errors.New("invalid user id format: " + err.Error())
+	}
+
+	projek, err := s.repository.GetProjekByIDRepository(id)
+	if err != nil {
+		return Project{}, errors.New("project tidak ditemukan")
+	}
+
+	if projek.ManagerID != userUUID {
+		return Project{}, errors.New("forbidden: hanya manager yang bisa update project")
+	}
+
+	if projek.ArchivedAt != nil {
+		return Project{}, archive.ErrReadOnly
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, projek.Version); err != nil {
+		return Project{}, err
+	}
+
+	doc, err := patch.Bind(ctx, "nama", "deskripsi")
+	if err != nil {
+		return Project{}, err
+	}
+
+	if doc.Has("nama") {
+		nama, err := doc.String("nama", false)
+		if err != nil {
+			return Project{}, err
+		}
+		nama = strings.TrimSpace(nama)
+		if nama == "" {
+			return Project{}, errors.New("nama projek tidak boleh kosong")
+		}
+		if utf8.RuneCountInString(nama) > 150 {
+			return Project{}, errors.New("nama projek maksimal 150 karakter")
+		}
+		projek.Nama = nama
+	}
+
+	if doc.Has("deskripsi") {
+		projek.Description, err = doc.String("deskripsi", true)
+		if err != nil {
+			return Project{}, err
+		}
+	}
+
+	updatedProject, err := s.repository.UpdateProjekRepository(projek)
+	if err != nil {
+		return Project{}, err
+	}
+
+	manager, err := s.repository.GetUserByIDRepository(updatedProject.ManagerID)
+	if err == nil {
+		updatedProject.Manager = manager
+	}
+
+	return updatedProject, nil
+}
+
 func (s *userService) DeleteProjekService(ctx *gin.Context) (err error) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -166,10 +262,81 @@ func (s *userService) DeleteProjekService(ctx *gin.Context) (err error) {
 		return errors.New("ID Projek tidak valid")
 	}
 
-	err = s.repository.DeleteProjekRepository(id)
+	uid, exists := ctx.Get("user_id")
+	if !exists {
+		return errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(uid))
+	if err != nil {
+		return errors.New("invalid user id format: " + err.Error())
+	}
+
+	err = s.repository.DeleteProjekRepository(id, userUUID)
 	if err != nil {
 		return err
 	}
 
 	return
 }
+
+// ArchiveProjekService menjadikan project beserta task-nya read-only dan menyembunyikannya dari listing default
+func (s *userService) ArchiveProjekService(ctx *gin.Context) (Project, error) {
+	return s.setProjekArchived(ctx, true)
+}
+
+func (s *userService) UnarchiveProjekService(ctx *gin.Context) (Project, error) {
+	return s.setProjekArchived(ctx, false)
+}
+
+func (s *userService) setProjekArchived(ctx *gin.Context, archived bool) (Project, error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return Project{}, errors.New("ID projek tidak valid")
+	}
+
+	currentUserID, exists := ctx.Get("user_id")
+	if !exists {
+		return Project{}, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(currentUserID))
+	if err != nil {
+		return Project{}, errors.New("invalid user id format: " + err.Error())
+	}
+
+	projek, err := s.repository.GetProjekByIDRepository(id)
+	if err != nil {
+		return Project{}, errors.New("project tidak ditemukan")
+	}
+
+	if projek.ManagerID != userUUID {
+		return Project{}, errors.New("forbidden: hanya manager yang bisa mengarsipkan project")
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, projek.Version); err != nil {
+		return Project{}, err
+	}
+
+	var archivedBy *uuid.UUID
+	if archived {
+		if projek.ArchivedAt != nil {
+			return Project{}, errors.New("project sudah diarsipkan")
+		}
+		archivedBy = &userUUID
+	} else if projek.ArchivedAt == nil {
+		return Project{}, errors.New("project tidak sedang diarsipkan")
+	}
+
+	updatedProject, err := s.repository.ArchiveProjekRepository(id, projek.Version, archivedBy)
+	if err != nil {
+		return Project{}, err
+	}
+
+	manager, err := s.repository.GetUserByIDRepository(updatedProject.ManagerID)
+	if err == nil {
+		updatedProject.Manager = manager
+	}
+
+	return updatedProject, nil
+}
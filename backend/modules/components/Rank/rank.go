@@ -0,0 +1,228 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// Key posisi memakai fractional indexing: bagian integer berpanjang variabel lalu bagian pecahan,
+// semuanya base62 sehingga urutan string (byte, COLLATE "C") sama dengan urutan posisi.
+// Karakter pertama menentukan panjang integer: 'a' = 1 digit, 'b' = 2 digit, ...; 'Z' = 1 digit, 'Y' = 2 digit, ... untuk nilai negatif.
+// Memindahkan satu item cukup membuat satu key baru di antara dua tetangganya.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// First adalah key untuk list yang masih kosong
+const First = "a0"
+
+var smallestInteger = "A" + strings.Repeat("0", 26)
+
+var ErrInvalidKey = errors.New("rank: key posisi tidak valid")
+
+// Between mengembalikan key di antara before dan after. String kosong berarti tanpa batas
+func Between(before, after string) (string, error) {
+	if before != "" {
+		if err := validate(before); err != nil {
+			return "", err
+		}
+	}
+	if after != "" {
+		if err := validate(after); err != nil {
+			return "", err
+		}
+	}
+	if before != "" && after != "" && before >= after {
+		return "", errors.New("rank: key sebelum harus lebih kecil dari key sesudah")
+	}
+
+	switch {
+	case before == "" && after == "":
+		return First, nil
+
+	case before == "":
+		intAfter := integerPart(after)
+		fracAfter := after[len(intAfter):]
+		if intAfter == smallestInteger {
+			return intAfter + midpoint("", fracAfter, true), nil
+		}
+		if intAfter < after {
+			return intAfter, nil
+		}
+		prev, ok := decrementInteger(intAfter)
+		if !ok {
+			return "", errors.New("rank: tidak bisa membuat key sebelum key terkecil")
+		}
+		return prev, nil
+
+	case after == "":
+		intBefore := integerPart(before)
+		fracBefore := before[len(intBefore):]
+		next, ok := incrementInteger(intBefore)
+		if !ok {
+			return intBefore + midpoint(fracBefore, "", false), nil
+		}
+		return next, nil
+	}
+
+	intBefore := integerPart(before)
+	fracBefore := before[len(intBefore):]
+	intAfter := integerPart(after)
+	fracAfter := after[len(intAfter):]
+	if intBefore == intAfter {
+		return intBefore + midpoint(fracBefore, fracAfter, true), nil
+	}
+	next, ok := incrementInteger(intBefore)
+	if ok && next < after {
+		return next, nil
+	}
+	return intBefore + midpoint(fracBefore, "", false), nil
+}
+
+func validate(key string) error {
+	if key == smallestInteger {
+		return ErrInvalidKey
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return ErrInvalidKey
+		}
+	}
+	length, ok := integerLength(key[0])
+	if !ok || len(key) < length {
+		return ErrInvalidKey
+	}
+	// pecahan tidak boleh diakhiri digit nol supaya selalu ada ruang di depannya
+	if len(key) > length && key[len(key)-1] == digits[0] {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+func integerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	}
+	return 0, false
+}
+
+func integerPart(key string) string {
+	length, _ := integerLength(key[0])
+	return key[:length]
+}
+
+// midpoint mengembalikan pecahan di antara a dan b. hasBound false berarti b tidak dibatasi
+func midpoint(a, b string, hasBound bool) string {
+	if hasBound && b == "" {
+		hasBound = false
+	}
+
+	if hasBound {
+		// salin prefix yang sama, a dianggap diisi nol di belakang
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(tail(a, n), b[n:], true)
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if hasBound {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	if hasBound && len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[digitA]) + midpoint(tail(a, 1), "", false)
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func tail(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func incrementInteger(value string) (string, bool) {
+	head := value[0]
+	body := []byte(value[1:])
+
+	carry := true
+	for i := len(body) - 1; carry && i >= 0; i-- {
+		d := strings.IndexByte(digits, body[i]) + 1
+		if d == len(digits) {
+			body[i] = digits[0]
+		} else {
+			body[i] = digits[d]
+			carry = false
+		}
+	}
+	if !carry {
+		return string(head) + string(body), true
+	}
+
+	switch head {
+	case 'Z':
+		return "a" + string(digits[0]), true
+	case 'z':
+		return "", false
+	}
+	head++
+	if head > 'a' {
+		body = append(body, digits[0])
+	} else {
+		body = body[:len(body)-1]
+	}
+	return string(head) + string(body), true
+}
+
+func decrementInteger(value string) (string, bool) {
+	head := value[0]
+	body := []byte(value[1:])
+
+	borrow := true
+	for i := len(body) - 1; borrow && i >= 0; i-- {
+		d := strings.IndexByte(digits, body[i]) - 1
+		if d == -1 {
+			body[i] = digits[len(digits)-1]
+		} else {
+			body[i] = digits[d]
+			borrow = false
+		}
+	}
+	if !borrow {
+		return string(head) + string(body), true
+	}
+
+	switch head {
+	case 'a':
+		return "Z" + string(digits[len(digits)-1]), true
+	case 'A':
+		return "", false
+	}
+	head--
+	if head < 'Z' {
+		body = append(body, digits[len(digits)-1])
+	} else {
+		body = body[:len(body)-1]
+	}
+	return string(head) + string(body), true
+}
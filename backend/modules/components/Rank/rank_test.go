@@ -0,0 +1,171 @@
+package rank
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	largestInteger := "z" + strings.Repeat("z", 26)
+
+	tests := []struct {
+		before string
+		after  string
+		want   string
+	}{
+		{"", "", "a0"},
+		{"", "a0", "Zz"},
+		{"", "Zz", "Zy"},
+		{"a0", "", "a1"},
+		{"a1", "", "a2"},
+		{"a0", "a1", "a0V"},
+		{"a1", "a2", "a1V"},
+		{"a0V", "a1", "a0l"},
+		{"Zz", "a0", "ZzV"},
+		{"Zz", "a1", "a0"},
+		{"", "Y00", "Xzzz"},
+		{"bzz", "", "c000"},
+		{"a0", "a0V", "a0G"},
+		{"a0", "a0G", "a08"},
+		{"b125", "b129", "b127"},
+		{"a0", "a1V", "a1"},
+		{"Zz", "a01", "a0"},
+		{"", "a0V", "a0"},
+		{"", "b999", "b99"},
+		{"a0", "a01", "a00V"},
+		{"a01", "a02", "a01V"},
+		{"a0z", "a1", "a0zV"},
+		// carry dan borrow yang mengubah panjang integer
+		{"az", "", "b00"},
+		{"Zz", "", "a0"},
+		{"Y00", "", "Y01"},
+		{"Yzz", "", "Z0"},
+		{"", "b00", "az"},
+		{"", "Z0", "Yzz"},
+		// batas integer terkecil dan terbesar
+		{"", smallestInteger + "1", smallestInteger + "0V"},
+		{strings.Repeat("z", 26) + "y", "", largestInteger},
+		{largestInteger, "", largestInteger + "V"},
+	}
+
+	for _, tt := range tests {
+		got, err := Between(tt.before, tt.after)
+		if err != nil {
+			t.Errorf("Between(%q, %q) error: %v", tt.before, tt.after, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Between(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	tests := []struct {
+		before string
+		after  string
+	}{
+		{"a1", "a0"},
+		{"a1", "a1"},
+		{"a00", ""},
+		{"", "a00"},
+		{"a0", "a10"},
+		{"0", ""},
+		{"a", ""},
+		{"b1", ""},
+		{"a-", ""},
+		{smallestInteger, ""},
+		{"", smallestInteger},
+	}
+
+	for _, tt := range tests {
+		if got, err := Between(tt.before, tt.after); err == nil {
+			t.Errorf("Between(%q, %q) = %q, want error", tt.before, tt.after, got)
+		}
+	}
+}
+
+// checkOrdered memastikan setiap key valid dan urutan string sama dengan urutan list
+func checkOrdered(t *testing.T, keys []string) {
+	t.Helper()
+	for i, key := range keys {
+		if err := validate(key); err != nil {
+			t.Fatalf("key %q di index %d tidak valid", key, i)
+		}
+		if i > 0 && keys[i-1] >= key {
+			t.Fatalf("key tidak urut di index %d: %q >= %q", i, keys[i-1], key)
+		}
+	}
+}
+
+func TestBetweenAppendAndPrepend(t *testing.T) {
+	keys := []string{}
+	for i := 0; i < 5000; i++ {
+		last := ""
+		if len(keys) > 0 {
+			last = keys[len(keys)-1]
+		}
+		key, err := Between(last, "")
+		if err != nil {
+			t.Fatalf("append ke-%d: %v", i, err)
+		}
+		keys = append(keys, key)
+	}
+	checkOrdered(t, keys)
+
+	keys = []string{}
+	for i := 0; i < 5000; i++ {
+		first := ""
+		if len(keys) > 0 {
+			first = keys[0]
+		}
+		key, err := Between("", first)
+		if err != nil {
+			t.Fatalf("prepend ke-%d: %v", i, err)
+		}
+		keys = append([]string{key}, keys...)
+	}
+	checkOrdered(t, keys)
+}
+
+// menyisipkan berulang kali di tempat yang sama membuat pecahan makin panjang tanpa pernah kehabisan ruang
+func TestBetweenRepeatedInsertSameGap(t *testing.T) {
+	lower, upper := "a0", "a1"
+	for i := 0; i < 500; i++ {
+		key, err := Between(lower, upper)
+		if err != nil {
+			t.Fatalf("insert ke-%d: %v", i, err)
+		}
+		checkOrdered(t, []string{lower, key, upper})
+		if i%2 == 0 {
+			upper = key
+		} else {
+			lower = key
+		}
+	}
+}
+
+func TestBetweenRandomInserts(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	keys := []string{First}
+
+	for i := 0; i < 3000; i++ {
+		// index posisi sisip: 0 berarti di awal, len(keys) berarti di akhir
+		at := random.Intn(len(keys) + 1)
+		before, after := "", ""
+		if at > 0 {
+			before = keys[at-1]
+		}
+		if at < len(keys) {
+			after = keys[at]
+		}
+
+		key, err := Between(before, after)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", before, after, err)
+		}
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	}
+	checkOrdered(t, keys)
+}
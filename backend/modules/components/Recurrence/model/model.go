@@ -0,0 +1,35 @@
+package recurrencemodel
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskRecurrence adalah satu series task berulang. Title, description dan assignee menjadi template occurrence berikutnya
+type TaskRecurrence struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID       uuid.UUID  `json:"project_id" gorm:"type:uuid;not null"`
+	RRule           string     `json:"rrule" gorm:"column:rrule;type:varchar(255);not null"`
+	DTStart         time.Time  `json:"dtstart" gorm:"column:dtstart;type:date;not null"`
+	Title           string     `json:"title" gorm:"type:varchar(200);not null"`
+	Description     string     `json:"description" gorm:"type:text"`
+	AssigneeID      *uuid.UUID `json:"assignee_id" gorm:"type:uuid"`
+	LastOccurrence  time.Time  `json:"last_occurrence" gorm:"type:date;not null"`
+	NextOccurrence  *time.Time `json:"next_occurrence" gorm:"type:date"`
+	OccurrenceCount int        `json:"occurrence_count" gorm:"not null;default:1"`
+	CreatedBy       *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Occurrences []taskmodel.Task `json:"occurrences,omitempty" gorm:"foreignKey:RecurrenceID"`
+}
+
+func (TaskRecurrence) TableName() string {
+	return "task_recurrences"
+}
+
+type RecurrenceRequest struct {
+	RRule string `json:"rrule" binding:"required"`
+}
@@ -0,0 +1,109 @@
+package recurrencerepository
+
+import (
+	recurrencemodel "gintugas/modules/components/Recurrence/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurrenceRepository interface {
+	CreateRecurrence(recurrence *recurrencemodel.TaskRecurrence) error
+	GetRecurrenceByID(id uuid.UUID) (*recurrencemodel.TaskRecurrence, error)
+	UpdateRecurrence(recurrence *recurrencemodel.TaskRecurrence) error
+	DeleteRecurrence(id uuid.UUID) error
+
+	// LockRecurrence membaca series dengan SELECT ... FOR UPDATE, hanya berguna di dalam Transaction
+	LockRecurrence(id uuid.UUID) (*recurrencemodel.TaskRecurrence, error)
+	GetDueRecurrences(today time.Time) ([]recurrencemodel.TaskRecurrence, error)
+	HasLaterOccurrence(recurrenceID uuid.UUID, occurrenceDate time.Time) (bool, error)
+	GetPendingOccurrences(recurrenceID uuid.UUID) ([]taskmodel.Task, error)
+
+	// Transaction memberi repository recurrence dan task yang memakai transaksi yang sama
+	Transaction(fn func(repo RecurrenceRepository, tasks taskrepository.TaskRepository) error) error
+}
+
+type recurrenceRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurrenceRepository(db *gorm.DB) RecurrenceRepository {
+	return &recurrenceRepository{db: db}
+}
+
+func (r *recurrenceRepository) CreateRecurrence(recurrence *recurrencemodel.TaskRecurrence) error {
+	return r.db.Omit(clause.Associations).Create(recurrence).Error
+}
+
+// GetRecurrenceByID juga memuat 20 occurrence terakhir
+func (r *recurrenceRepository) GetRecurrenceByID(id uuid.UUID) (*recurrencemodel.TaskRecurrence, error) {
+	var recurrence recurrencemodel.TaskRecurrence
+	err := r.db.Where("id = ?", id).
+		Preload("Occurrences", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurrence_date DESC").Limit(20)
+		}).
+		First(&recurrence).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+func (r *recurrenceRepository) UpdateRecurrence(recurrence *recurrencemodel.TaskRecurrence) error {
+	recurrence.UpdatedAt = time.Now()
+	return r.db.Model(recurrence).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(recurrence).Error
+}
+
+func (r *recurrenceRepository) DeleteRecurrence(id uuid.UUID) error {
+	return r.db.Delete(&recurrencemodel.TaskRecurrence{}, "id = ?", id).Error
+}
+
+func (r *recurrenceRepository) LockRecurrence(id uuid.UUID) (*recurrencemodel.TaskRecurrence, error) {
+	var recurrence recurrencemodel.TaskRecurrence
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&recurrence).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+func (r *recurrenceRepository) GetDueRecurrences(today time.Time) ([]recurrencemodel.TaskRecurrence, error) {
+	var recurrences []recurrencemodel.TaskRecurrence
+	err := r.db.Where("next_occurrence IS NOT NULL AND next_occurrence <= ?", today).
+		Where("project_id IN (SELECT id FROM projects WHERE deleted_at IS NULL AND archived_at IS NULL)").
+		Find(&recurrences).Error
+	return recurrences, err
+}
+
+func (r *recurrenceRepository) HasLaterOccurrence(recurrenceID uuid.UUID, occurrenceDate time.Time) (bool, error) {
+	var count int64
+	// occurrence di trash ikut dihitung karena tetap memakai slot unik recurrence_id + occurrence_date
+	err := r.db.Unscoped().Model(&taskmodel.Task{}).
+		Where("recurrence_id = ? AND occurrence_date > ?", recurrenceID, occurrenceDate).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPendingOccurrences mengambil occurrence yang belum selesai, tidak diedit sendiri dan tidak diarsipkan
+func (r *recurrenceRepository) GetPendingOccurrences(recurrenceID uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.db.Where("recurrence_id = ? AND status <> ? AND NOT recurrence_exception AND archived_at IS NULL", recurrenceID, "done").
+		Order("occurrence_date ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *recurrenceRepository) Transaction(fn func(repo RecurrenceRepository, tasks taskrepository.TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&recurrenceRepository{db: tx}, taskrepository.NewTaskRepository(tx))
+	})
+}
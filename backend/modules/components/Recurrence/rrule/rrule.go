@@ -0,0 +1,248 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subset RRULE (RFC 5545) yang didukung:
+//
+//	FREQ=DAILY[;INTERVAL=n]
+//	FREQ=WEEKLY[;INTERVAL=n][;BYDAY=MO,WE,FR]
+//	FREQ=MONTHLY[;INTERVAL=n][;BYMONTHDAY=15]
+//
+// ditambah COUNT=n atau UNTIL=YYYYMMDD. Semua perhitungan memakai tanggal (tanpa jam), minggu dimulai hari Senin
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// batas pencarian occurrence berikutnya supaya rule yang tidak pernah cocok tidak membuat loop tanpa akhir
+const maxSearchDays = 366 * 5
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+	Count      int
+	Until      *time.Time
+}
+
+// Parse membaca string RRULE, prefix "RRULE:" boleh ada
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.ToUpper(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule tidak boleh kosong")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("rrule tidak valid: %s", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("rrule: %s ditulis lebih dari sekali", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			if val != Daily && val != Weekly && val != Monthly {
+				return nil, fmt.Errorf("rrule: FREQ %s tidak didukung (DAILY, WEEKLY, MONTHLY)", val)
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 365 {
+				return nil, errors.New("rrule: INTERVAL harus antara 1 dan 365")
+			}
+			rule.Interval = n
+		case "BYDAY":
+			days := map[time.Weekday]bool{}
+			for _, code := range strings.Split(val, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("rrule: BYDAY %s tidak valid", code)
+				}
+				days[day] = true
+			}
+			for day := range days {
+				rule.ByDay = append(rule.ByDay, day)
+			}
+			sort.Slice(rule.ByDay, func(i, j int) bool {
+				return isoWeekday(rule.ByDay[i]) < isoWeekday(rule.ByDay[j])
+			})
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 31 {
+				return nil, errors.New("rrule: BYMONTHDAY harus antara 1 dan 31")
+			}
+			rule.ByMonthDay = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, errors.New("rrule: COUNT harus lebih dari 0")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("rrule: %s tidak didukung", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("rrule: FREQ wajib diisi")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, errors.New("rrule: BYDAY hanya untuk FREQ=WEEKLY")
+	}
+	if rule.ByMonthDay > 0 && rule.Freq != Monthly {
+		return nil, errors.New("rrule: BYMONTHDAY hanya untuk FREQ=MONTHLY")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("rrule: COUNT dan UNTIL tidak boleh dipakai bersamaan")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return truncateDay(t), nil
+		}
+	}
+	return time.Time{}, errors.New("rrule: UNTIL harus berformat YYYYMMDD")
+}
+
+// String mengembalikan bentuk RRULE yang sudah dinormalisasi
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, weekday := range weekdayCodes {
+				if weekday == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next mengembalikan occurrence pertama setelah tanggal after untuk series yang dimulai dtstart.
+// false berarti series sudah berakhir (UNTIL terlewati atau rule tidak pernah cocok). COUNT dicek oleh pemanggil
+func (r *Rule) Next(dtstart, after time.Time) (time.Time, bool) {
+	dtstart = truncateDay(dtstart)
+	after = truncateDay(after)
+
+	candidate := after.AddDate(0, 0, 1)
+	if candidate.Before(dtstart) {
+		candidate = dtstart
+	}
+
+	for i := 0; i < maxSearchDays*r.Interval; i++ {
+		if r.Until != nil && candidate.After(*r.Until) {
+			return time.Time{}, false
+		}
+		if r.matches(dtstart, candidate) {
+			return candidate, true
+		}
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) matches(dtstart, day time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		return daysBetween(dtstart, day)%r.Interval == 0
+
+	case Weekly:
+		weeks := daysBetween(startOfWeek(dtstart), startOfWeek(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []time.Weekday{dtstart.Weekday()}
+		}
+		for _, weekday := range byDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+
+	case Monthly:
+		months := (day.Year()-dtstart.Year())*12 + int(day.Month()-dtstart.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		monthDay := r.ByMonthDay
+		if monthDay == 0 {
+			monthDay = dtstart.Day()
+		}
+		// sesuai RFC 5545, bulan yang tidak punya tanggal tersebut (mis. 31 Februari) dilewati
+		return day.Day() == monthDay
+	}
+	return false
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func isoWeekday(day time.Weekday) int {
+	if day == time.Sunday {
+		return 7
+	}
+	return int(day)
+}
+
+func startOfWeek(t time.Time) time.Time {
+	return t.AddDate(0, 0, -(isoWeekday(t.Weekday()) - 1))
+}
@@ -0,0 +1,235 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{" rrule:freq=weekly;byday=fr,mo,mo ", "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{"FREQ=WEEKLY;BYDAY=SU,MO;INTERVAL=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3", "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3"},
+		{"FREQ=DAILY;UNTIL=20240301T120000Z", "FREQ=DAILY;UNTIL=20240301"},
+		{"FREQ=DAILY;UNTIL=20240301", "FREQ=DAILY;UNTIL=20240301"},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"FREQ=",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=366",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;UNTIL=2024-03-01",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240301",
+		"FREQ=DAILY;WKST=MO",
+	}
+
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) tidak mengembalikan error", input)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		rrule   string
+		dtstart string
+		want    []string
+	}{
+		{
+			name:    "daily interval",
+			rrule:   "FREQ=DAILY;INTERVAL=3",
+			dtstart: "2024-01-01",
+			want:    []string{"2024-01-01", "2024-01-04", "2024-01-07", "2024-01-10"},
+		},
+		{
+			name:    "weekly byday",
+			rrule:   "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			dtstart: "2024-01-01",
+			want:    []string{"2024-01-01", "2024-01-03", "2024-01-05", "2024-01-08"},
+		},
+		{
+			name:    "weekly byday dimulai di tengah minggu",
+			rrule:   "FREQ=WEEKLY;BYDAY=MO,FR",
+			dtstart: "2024-01-03",
+			want:    []string{"2024-01-05", "2024-01-08", "2024-01-12"},
+		},
+		{
+			name:    "weekly interval melewati minggu ganjil",
+			rrule:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH",
+			dtstart: "2024-01-01",
+			want:    []string{"2024-01-02", "2024-01-04", "2024-01-16", "2024-01-18", "2024-01-30"},
+		},
+		{
+			name:    "weekly minggu dimulai hari senin",
+			rrule:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU",
+			dtstart: "2024-01-01",
+			want:    []string{"2024-01-07", "2024-01-21", "2024-02-04"},
+		},
+		{
+			name:    "weekly tanpa byday memakai hari dtstart",
+			rrule:   "FREQ=WEEKLY",
+			dtstart: "2024-01-03",
+			want:    []string{"2024-01-03", "2024-01-10", "2024-01-17"},
+		},
+		{
+			name:    "monthly bymonthday 31 melewati bulan pendek",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=31",
+			dtstart: "2024-01-31",
+			want:    []string{"2024-01-31", "2024-03-31", "2024-05-31", "2024-07-31", "2024-08-31"},
+		},
+		{
+			name:    "monthly tanpa bymonthday memakai tanggal dtstart",
+			rrule:   "FREQ=MONTHLY",
+			dtstart: "2024-01-30",
+			want:    []string{"2024-01-30", "2024-03-30", "2024-04-30"},
+		},
+		{
+			name:    "monthly 29 di tahun kabisat",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=29",
+			dtstart: "2024-01-29",
+			want:    []string{"2024-01-29", "2024-02-29", "2024-03-29"},
+		},
+		{
+			name:    "monthly 29 di tahun biasa",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=29",
+			dtstart: "2023-01-29",
+			want:    []string{"2023-01-29", "2023-03-29"},
+		},
+		{
+			name:    "monthly interval dengan dtstart sebelum bymonthday",
+			rrule:   "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=15",
+			dtstart: "2024-01-10",
+			want:    []string{"2024-01-15", "2024-03-15", "2024-05-15"},
+		},
+		{
+			name:    "monthly melewati tahun",
+			rrule:   "FREQ=MONTHLY;INTERVAL=5",
+			dtstart: "2024-10-05",
+			want:    []string{"2024-10-05", "2025-03-05", "2025-08-05"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rrule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rrule, err)
+			}
+
+			dtstart := date(tt.dtstart)
+			after := dtstart.AddDate(0, 0, -1)
+			for _, want := range tt.want {
+				got, ok := rule.Next(dtstart, after)
+				if !ok {
+					t.Fatalf("Next setelah %s berakhir, want %s", after.Format("2006-01-02"), want)
+				}
+				if got.Format("2006-01-02") != want {
+					t.Fatalf("Next setelah %s = %s, want %s", after.Format("2006-01-02"), got.Format("2006-01-02"), want)
+				}
+				after = got
+			}
+		})
+	}
+}
+
+func TestNextUntil(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;INTERVAL=2;UNTIL=20240105")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dtstart := date("2024-01-01")
+	tests := []struct {
+		after  string
+		want   string
+		wantOK bool
+	}{
+		{"2023-12-31", "2024-01-01", true},
+		{"2024-01-01", "2024-01-03", true},
+		{"2024-01-03", "2024-01-05", true},
+		{"2024-01-05", "", false},
+		{"2024-02-01", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := rule.Next(dtstart, date(tt.after))
+		if ok != tt.wantOK {
+			t.Errorf("Next setelah %s ok = %v, want %v", tt.after, ok, tt.wantOK)
+			continue
+		}
+		if ok && got.Format("2006-01-02") != tt.want {
+			t.Errorf("Next setelah %s = %s, want %s", tt.after, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+// Next hanya memakai tanggal, jam dan zona waktu input diabaikan
+func TestNextIgnoresTimeOfDay(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zone := time.FixedZone("WIB", 7*60*60)
+	dtstart := time.Date(2024, 1, 1, 23, 30, 0, 0, zone)
+	after := time.Date(2024, 1, 1, 1, 0, 0, 0, zone)
+
+	got, ok := rule.Next(dtstart, after)
+	if !ok || !got.Equal(date("2024-01-02")) {
+		t.Fatalf("Next = %v, %v, want 2024-01-02", got, ok)
+	}
+}
+
+// rule yang tidak pernah cocok harus berhenti setelah batas pencarian
+func TestNextBoundedSearch(t *testing.T) {
+	rules := []*Rule{
+		{Freq: Monthly, Interval: 12, ByMonthDay: 31},
+		{Freq: "YEARLY", Interval: 1},
+	}
+
+	for _, rule := range rules {
+		if got, ok := rule.Next(date("2024-02-10"), date("2024-02-10")); ok {
+			t.Errorf("Next(%s) = %s, want series berakhir", rule, got.Format("2006-01-02"))
+		}
+	}
+}
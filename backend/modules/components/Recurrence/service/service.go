@@ -0,0 +1,498 @@
+package recurrenceservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	concurrency "gintugas/modules/components/Concurrency"
+	. "gintugas/modules/components/Mail/service"
+	patch "gintugas/modules/components/Patch"
+	recurrencemodel "gintugas/modules/components/Recurrence/model"
+	recurrencerepository "gintugas/modules/components/Recurrence/repository"
+	"gintugas/modules/components/Recurrence/rrule"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecurrenceService interface {
+	CreateRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error)
+	GetRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error)
+	UpdateRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error)
+	DeleteRecurrence(ctx *gin.Context) error
+
+	// HandleTaskEvent membuat occurrence berikutnya ketika occurrence terakhir diselesaikan
+	HandleTaskEvent(event taskmodel.TaskEvent)
+	// StartScheduler menjalankan pembuatan occurrence yang slot-nya sudah tiba setiap interval
+	StartScheduler(interval time.Duration)
+}
+
+type recurrenceService struct {
+	repo        recurrencerepository.RecurrenceRepository
+	taskRepo    taskrepository.TaskRepository
+	mailService MailService
+	events      taskmodel.TaskEventPublisher
+}
+
+func NewRecurrenceService(repo recurrencerepository.RecurrenceRepository, taskRepo taskrepository.TaskRepository, mailService MailService, events taskmodel.TaskEventPublisher) RecurrenceService {
+	return &recurrenceService{
+		repo:        repo,
+		taskRepo:    taskRepo,
+		mailService: mailService,
+		events:      events,
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+func (s *recurrenceService) validateProjectManager(ctx *gin.Context, projectID uuid.UUID) error {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	if project.ManagerID != userUUID {
+		return errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	return nil
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextSlot menghitung slot setelah occurrence terakhir, nil jika series sudah selesai
+func nextSlot(rule *rrule.Rule, recurrence *recurrencemodel.TaskRecurrence) *time.Time {
+	if rule.Count > 0 && recurrence.OccurrenceCount >= rule.Count {
+		return nil
+	}
+	next, ok := rule.Next(recurrence.DTStart, recurrence.LastOccurrence)
+	if !ok {
+		return nil
+	}
+	return &next
+}
+
+// CreateRecurrence menjadikan task sebagai occurrence pertama dari series baru, due_date task menjadi DTSTART
+func (s *recurrenceService) CreateRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, task.Version); err != nil {
+		return nil, err
+	}
+
+	if task.IsReadOnly() {
+		return nil, archive.ErrReadOnly
+	}
+	if task.RecurrenceID != nil {
+		return nil, errors.New("task ini sudah menjadi bagian dari recurrence")
+	}
+	if task.DueDate == nil {
+		return nil, errors.New("task harus memiliki due_date sebagai tanggal mulai recurrence")
+	}
+
+	var req recurrencemodel.RecurrenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	rule, err := rrule.Parse(req.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtstart := *task.DueDate
+	recurrence := &recurrencemodel.TaskRecurrence{
+		ProjectID:       task.ProjectID,
+		RRule:           rule.String(),
+		DTStart:         dtstart,
+		Title:           task.Title,
+		Description:     task.Description,
+		AssigneeID:      task.AssigneeID,
+		LastOccurrence:  dtstart,
+		OccurrenceCount: 1,
+		CreatedBy:       &userUUID,
+	}
+	recurrence.NextOccurrence = nextSlot(rule, recurrence)
+
+	err = s.repo.Transaction(func(repo recurrencerepository.RecurrenceRepository, tasks taskrepository.TaskRepository) error {
+		if err := repo.CreateRecurrence(recurrence); err != nil {
+			return err
+		}
+
+		task.RecurrenceID = &recurrence.ID
+		task.OccurrenceDate = &dtstart
+		task.UpdatedAt = time.Now()
+		if err := tasks.UpdateTask(task); err != nil {
+			return err
+		}
+
+		return tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+			TaskID:    task.ID,
+			ProjectID: task.ProjectID,
+			TaskTitle: task.Title,
+			ActorID:   &userUUID,
+			Action:    taskmodel.HistoryUpdated,
+			Changes:   []taskmodel.FieldChange{{Field: "recurrence", Old: nil, New: recurrence.RRule}},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+func (s *recurrenceService) loadRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error) {
+	recurrenceUUID, err := uuid.Parse(ctx.Param("recurrence_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format recurrence ID")
+	}
+
+	recurrence, err := s.repo.GetRecurrenceByID(recurrenceUUID)
+	if err != nil {
+		return nil, errors.New("recurrence tidak ditemukan")
+	}
+
+	if err := s.validateProjectManager(ctx, recurrence.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+func (s *recurrenceService) GetRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error) {
+	return s.loadRecurrence(ctx)
+}
+
+// UpdateRecurrence mengubah series (JSON Merge Patch: rrule, title, description, assignee_id).
+// Perubahan template juga diterapkan ke occurrence yang belum selesai, kecuali occurrence yang sudah diedit sendiri
+func (s *recurrenceService) UpdateRecurrence(ctx *gin.Context) (*recurrencemodel.TaskRecurrence, error) {
+	recurrence, err := s.loadRecurrence(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(recurrence.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ArchivedAt != nil {
+		return nil, archive.ErrReadOnly
+	}
+
+	doc, err := patch.Bind(ctx, "rrule", "title", "description", "assignee_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Has("rrule") {
+		value, err := doc.String("rrule", false)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := rrule.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.RRule = rule.String()
+		recurrence.NextOccurrence = nextSlot(rule, recurrence)
+	}
+
+	if doc.Has("title") {
+		title, err := doc.String("title", false)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(title) == "" {
+			return nil, errors.New("title tidak boleh kosong")
+		}
+		recurrence.Title = strings.TrimSpace(title)
+	}
+
+	if doc.Has("description") {
+		recurrence.Description, err = doc.String("description", true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Has("assignee_id") {
+		if doc.IsNull("assignee_id") {
+			recurrence.AssigneeID = nil
+		} else {
+			var assigneeID uuid.UUID
+			if err := doc.Decode("assignee_id", &assigneeID); err != nil {
+				return nil, err
+			}
+			isMember, err := s.taskRepo.IsProjectMember(recurrence.ProjectID, assigneeID)
+			if err != nil {
+				return nil, fmt.Errorf("gagal memeriksa member project: %v", err)
+			}
+			if !isMember {
+				return nil, errors.New("assignee harus menjadi member dari project ini")
+			}
+			recurrence.AssigneeID = &assigneeID
+		}
+	}
+
+	actor, _ := currentUserID(ctx)
+	err = s.repo.Transaction(func(repo recurrencerepository.RecurrenceRepository, tasks taskrepository.TaskRepository) error {
+		if err := repo.UpdateRecurrence(recurrence); err != nil {
+			return err
+		}
+
+		pending, err := repo.GetPendingOccurrences(recurrence.ID)
+		if err != nil {
+			return err
+		}
+
+		for i := range pending {
+			task := &pending[i]
+			before := *task
+
+			task.Title = recurrence.Title
+			task.Description = recurrence.Description
+			task.AssigneeID = recurrence.AssigneeID
+
+			changes := taskmodel.DiffTask(&before, task)
+			if len(changes) == 0 {
+				continue
+			}
+
+			task.UpdatedAt = time.Now()
+			if err := tasks.ReplacePrimaryAssignee(task.ID, before.AssigneeID, task.AssigneeID, false); err != nil {
+				return err
+			}
+			if err := tasks.UpdateTask(task); err != nil {
+				return err
+			}
+			err := tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+				TaskID:    task.ID,
+				ProjectID: task.ProjectID,
+				TaskTitle: task.Title,
+				ActorID:   &actor,
+				Action:    taskmodel.HistoryUpdated,
+				Changes:   changes,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRecurrenceByID(recurrence.ID)
+}
+
+// DeleteRecurrence menghentikan series. Occurrence yang sudah dibuat tetap ada sebagai task biasa
+func (s *recurrenceService) DeleteRecurrence(ctx *gin.Context) error {
+	recurrence, err := s.loadRecurrence(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(recurrence.ProjectID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ArchivedAt != nil {
+		return archive.ErrReadOnly
+	}
+
+	return s.repo.DeleteRecurrence(recurrence.ID)
+}
+
+func (s *recurrenceService) HandleTaskEvent(event taskmodel.TaskEvent) {
+	task := event.Task
+	if event.Action != taskmodel.HistoryUpdated || !event.HasChange("status") || task.Status != "done" {
+		return
+	}
+	if task.RecurrenceID == nil || task.OccurrenceDate == nil {
+		return
+	}
+
+	// occurrence berikutnya sudah ada, tidak perlu dibuat lagi
+	hasLater, err := s.repo.HasLaterOccurrence(*task.RecurrenceID, *task.OccurrenceDate)
+	if err != nil || hasLater {
+		return
+	}
+
+	if _, err := s.generateNext(*task.RecurrenceID, true); err != nil {
+		log.Printf("Gagal membuat occurrence berikutnya: %v", err)
+	}
+}
+
+func (s *recurrenceService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.generateDue()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *recurrenceService) generateDue() {
+	recurrences, err := s.repo.GetDueRecurrences(today())
+	if err != nil {
+		log.Printf("Gagal mengambil recurrence: %v", err)
+		return
+	}
+
+	for _, recurrence := range recurrences {
+		if _, err := s.generateNext(recurrence.ID, false); err != nil {
+			log.Printf("Gagal membuat occurrence untuk recurrence %s: %v", recurrence.ID, err)
+		}
+	}
+}
+
+// generateNext membuat occurrence untuk slot berikutnya. early=true dipakai saat occurrence sebelumnya selesai
+// sehingga slot dibuat walaupun tanggalnya belum tiba. Dari scheduler, slot yang terlewat (mis. server mati)
+// dilewati dan hanya slot terakhir yang sudah tiba yang dibuat
+func (s *recurrenceService) generateNext(recurrenceID uuid.UUID, early bool) (*taskmodel.Task, error) {
+	var created *taskmodel.Task
+
+	err := s.repo.Transaction(func(repo recurrencerepository.RecurrenceRepository, tasks taskrepository.TaskRepository) error {
+		recurrence, err := repo.LockRecurrence(recurrenceID)
+		if err != nil {
+			return err
+		}
+		if recurrence.NextOccurrence == nil {
+			return nil
+		}
+
+		rule, err := rrule.Parse(recurrence.RRule)
+		if err != nil {
+			return err
+		}
+
+		slot := *recurrence.NextOccurrence
+		if !early {
+			if slot.After(today()) {
+				return nil
+			}
+			for rule.Count == 0 || recurrence.OccurrenceCount+1 < rule.Count {
+				next, ok := rule.Next(recurrence.DTStart, slot)
+				if !ok || next.After(today()) {
+					break
+				}
+				slot = next
+				recurrence.OccurrenceCount++
+			}
+		}
+
+		task := &taskmodel.Task{
+			ProjectID:      recurrence.ProjectID,
+			Title:          recurrence.Title,
+			Description:    recurrence.Description,
+			Status:         "todo",
+			AssigneeID:     recurrence.AssigneeID,
+			DueDate:        &slot,
+			RecurrenceID:   &recurrence.ID,
+			OccurrenceDate: &slot,
+		}
+		if err := tasks.CreateTask(task); err != nil {
+			return err
+		}
+		if err := tasks.ReplacePrimaryAssignee(task.ID, nil, task.AssigneeID, false); err != nil {
+			return err
+		}
+		err = tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+			TaskID:    task.ID,
+			ProjectID: task.ProjectID,
+			TaskTitle: task.Title,
+			Action:    taskmodel.HistoryCreated,
+			Changes:   taskmodel.DiffTask(nil, task),
+		})
+		if err != nil {
+			return err
+		}
+
+		recurrence.LastOccurrence = slot
+		recurrence.OccurrenceCount++
+		recurrence.NextOccurrence = nextSlot(rule, recurrence)
+		if err := repo.UpdateRecurrence(recurrence); err != nil {
+			return err
+		}
+
+		created = task
+		return nil
+	})
+	if err != nil || created == nil {
+		return nil, err
+	}
+
+	s.events.PublishTaskEvent(taskmodel.TaskEvent{
+		Action:  taskmodel.HistoryCreated,
+		Task:    *created,
+		Changes: taskmodel.DiffTask(nil, created),
+		Source:  taskmodel.EventSourceRecurrence,
+	})
+	if created.AssigneeID != nil {
+		s.notifyAssignee(created)
+	}
+
+	return created, nil
+}
+
+func (s *recurrenceService) notifyAssignee(task *taskmodel.Task) {
+	assignee, err := s.taskRepo.GetUserByID(*task.AssigneeID)
+	if err != nil {
+		return
+	}
+
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		return
+	}
+
+	err = s.mailService.SendTaskAssignmentNotification(assignee.Email, task.Title, project.Nama)
+	if err != nil {
+		log.Printf("Gagal untuk mengirim notif: %v", err)
+	}
+}
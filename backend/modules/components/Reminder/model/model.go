@@ -0,0 +1,39 @@
+package remindermodel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jenis reminder, reminder sebelum due date memakai BeforeKind
+const (
+	KindOverdue    = "overdue"
+	KindEscalation = "escalation"
+)
+
+// BeforeKind adalah jenis reminder yang dikirim offset sebelum batas waktu task, misalnya before_72h
+func BeforeKind(offset time.Duration) string {
+	return fmt.Sprintf("before_%dh", int(offset.Hours()))
+}
+
+// TaskReminder adalah satu reminder yang sudah dikirim ke satu user, lihat migration Update-018
+type TaskReminder struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TaskID  uuid.UUID `json:"task_id" gorm:"type:uuid;not null"`
+	UserID  uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Kind    string    `json:"kind" gorm:"type:varchar(30);not null"`
+	DueDate time.Time `json:"due_date" gorm:"type:date;not null"`
+	SentAt  time.Time `json:"sent_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (TaskReminder) TableName() string {
+	return "task_reminders"
+}
+
+// RunResult merangkum satu putaran scheduler
+type RunResult struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
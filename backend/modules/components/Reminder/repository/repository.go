@@ -0,0 +1,56 @@
+package reminderrepository
+
+import (
+	remindermodel "gintugas/modules/components/Reminder/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository interface {
+	// GetDueTasks mengambil task aktif yang belum done dengan due_date di antara from dan to (inklusif)
+	GetDueTasks(from, to time.Time) ([]taskmodel.Task, error)
+	// Claim mencatat reminder, false berarti reminder yang sama sudah pernah dicatat dan tidak boleh dikirim lagi
+	Claim(reminder *remindermodel.TaskReminder) (bool, error)
+
+	// Transaction dipakai supaya reminder hanya tercatat jika pengirimannya berhasil
+	Transaction(fn func(repo ReminderRepository) error) error
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// GetDueTasks: task di trash tidak ikut karena soft delete, task dan project yang diarsipkan dicek sendiri
+func (r *reminderRepository) GetDueTasks(from, to time.Time) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.db.Model(&taskmodel.Task{}).
+		Where("tasks.due_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("tasks.status <> 'done' AND tasks.archived_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL)").
+		Preload("Project.Manager").
+		Preload("Assignees.User").
+		Order("tasks.due_date ASC, tasks.id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *reminderRepository) Claim(reminder *remindermodel.TaskReminder) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *reminderRepository) Transaction(fn func(repo ReminderRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&reminderRepository{db: tx})
+	})
+}
@@ -0,0 +1,251 @@
+package reminderservice
+
+import (
+	"fmt"
+	services "gintugas/modules/components/Mail/service"
+	remindermodel "gintugas/modules/components/Reminder/model"
+	reminderrepository "gintugas/modules/components/Reminder/repository"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// nilai default jika REMINDER_BEFORE dan REMINDER_ESCALATION_AFTER tidak diisi
+const (
+	DefaultReminderBefore  = "1d"
+	DefaultEscalationAfter = "2d"
+
+	// catchUpWindow adalah batas keterlambatan reminder yang masih dikirim, misalnya setelah server mati
+	catchUpWindow = 7 * 24 * time.Hour
+)
+
+// Config mengatur kapan reminder dikirim. Before adalah jarak sebelum batas waktu task, EscalateAfter adalah masa
+// tenggang setelah task overdue sebelum manager project diberi tahu
+type Config struct {
+	Before        []time.Duration
+	EscalateAfter time.Duration
+}
+
+type ReminderService interface {
+	// RunDue mengirim semua reminder yang sudah waktunya pada saat now, dipanggil berkala oleh StartScheduler
+	RunDue(now time.Time) (*remindermodel.RunResult, error)
+	StartScheduler(interval time.Duration)
+}
+
+type reminderService struct {
+	repo        reminderrepository.ReminderRepository
+	mailService services.MailService
+	config      Config
+}
+
+func NewReminderService(repo reminderrepository.ReminderRepository, mailService services.MailService, config Config) ReminderService {
+	return &reminderService{
+		repo:        repo,
+		mailService: mailService,
+		config:      config,
+	}
+}
+
+// ConfigFromEnv membaca REMINDER_BEFORE (daftar jarak dipisah koma, misalnya "3d,1d,2h") dan
+// REMINDER_ESCALATION_AFTER (misalnya "2d"), nilai kosong atau tidak valid memakai default
+func ConfigFromEnv() Config {
+	before, err := parseOffsets(os.Getenv("REMINDER_BEFORE"))
+	if err != nil || len(before) == 0 {
+		before, _ = parseOffsets(DefaultReminderBefore)
+	}
+
+	escalateAfter, err := parseOffset(os.Getenv("REMINDER_ESCALATION_AFTER"))
+	if err != nil {
+		escalateAfter, _ = parseOffset(DefaultEscalationAfter)
+	}
+
+	return Config{Before: before, EscalateAfter: escalateAfter}
+}
+
+// parseOffsets mengurutkan hasilnya dari jarak terbesar, duplikat dibuang
+func parseOffsets(value string) ([]time.Duration, error) {
+	offsets := []time.Duration{}
+	seen := map[time.Duration]bool{}
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		offset, err := parseOffset(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+	return offsets, nil
+}
+
+// parseOffset menerima jumlah hari atau jam bulat positif, misalnya "3d" atau "12h"
+func parseOffset(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if len(value) < 2 {
+		return 0, fmt.Errorf("jarak reminder tidak valid: %q", value)
+	}
+
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("jarak reminder tidak valid: %q", value)
+	}
+
+	switch value[len(value)-1] {
+	case 'd':
+		return time.Duration(amount) * 24 * time.Hour, nil
+	case 'h':
+		return time.Duration(amount) * time.Hour, nil
+	}
+	return 0, fmt.Errorf("jarak reminder tidak valid: %q, pakai akhiran d atau h", value)
+}
+
+// deadline: due_date tidak punya jam, jadi task dianggap overdue sejak akhir hari due date menurut zona waktu server
+func deadline(dueDate time.Time) time.Time {
+	return time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+}
+
+// RunDue memilih untuk setiap task paling banyak satu reminder sebelum due date (jarak terkecil yang sudah lewat,
+// jadi setelah server mati beberapa hari user tidak menerima semua reminder yang terlewat sekaligus), notice overdue
+// untuk assignee, dan eskalasi ke manager project. Kejadian yang sudah lewat lebih dari catchUpWindow tidak dikirim
+// lagi supaya task lama yang overdue tidak membanjiri inbox saat fitur ini pertama kali aktif
+func (s *reminderService) RunDue(now time.Time) (*remindermodel.RunResult, error) {
+	maxBefore := time.Duration(0)
+	if len(s.config.Before) > 0 {
+		maxBefore = s.config.Before[0]
+	}
+	from := now.Add(-s.config.EscalateAfter-catchUpWindow).AddDate(0, 0, -1)
+	to := now.Add(maxBefore)
+
+	tasks, err := s.repo.GetDueTasks(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil task yang mendekati due date: %v", err)
+	}
+
+	result := &remindermodel.RunResult{}
+	for i := range tasks {
+		s.runTask(&tasks[i], now, result)
+	}
+	return result, nil
+}
+
+func (s *reminderService) runTask(task *taskmodel.Task, now time.Time, result *remindermodel.RunResult) {
+	if task.DueDate == nil {
+		return
+	}
+	end := deadline(*task.DueDate)
+
+	// task yang sudah diajukan review menunggu manager, assignee tidak perlu diingatkan lagi
+	remindAssignees := task.Status != taskmodel.StatusInReview
+
+	if now.Before(end) {
+		if !remindAssignees {
+			return
+		}
+		kind := ""
+		for _, offset := range s.config.Before {
+			at := end.Add(-offset)
+			if !now.Before(at) && now.Sub(at) < catchUpWindow {
+				kind = remindermodel.BeforeKind(offset)
+			}
+		}
+		if kind != "" {
+			s.notifyAssignees(task, kind, result)
+		}
+		return
+	}
+
+	if remindAssignees && now.Sub(end) < catchUpWindow {
+		s.notifyAssignees(task, remindermodel.KindOverdue, result)
+	}
+
+	escalateAt := end.Add(s.config.EscalateAfter)
+	if !now.Before(escalateAt) && now.Sub(escalateAt) < catchUpWindow {
+		s.escalate(task, result)
+	}
+}
+
+func (s *reminderService) notifyAssignees(task *taskmodel.Task, kind string, result *remindermodel.RunResult) {
+	for _, assignee := range task.Assignees {
+		if assignee.User == nil || assignee.User.Email == "" {
+			continue
+		}
+		to := assignee.User.Email
+		s.deliver(task, assignee.UserID, kind, result, func() error {
+			if kind == remindermodel.KindOverdue {
+				return s.mailService.SendOverdueNotice(to, task.Title, task.Project.Nama, *task.DueDate)
+			}
+			return s.mailService.SendDueReminder(to, task.Title, task.Project.Nama, *task.DueDate)
+		})
+	}
+}
+
+func (s *reminderService) escalate(task *taskmodel.Task, result *remindermodel.RunResult) {
+	manager := task.Project.Manager
+	if manager.Email == "" {
+		return
+	}
+
+	assignees := []string{}
+	for _, assignee := range task.Assignees {
+		if assignee.User != nil {
+			assignees = append(assignees, assignee.User.Username)
+		}
+	}
+
+	s.deliver(task, task.Project.ManagerID, remindermodel.KindEscalation, result, func() error {
+		return s.mailService.SendOverdueEscalation(manager.Email, task.Title, task.Project.Nama, *task.DueDate, assignees)
+	})
+}
+
+// deliver mencatat reminder lalu mengirimnya dalam satu transaksi: reminder yang sudah tercatat dilewati, dan
+// reminder yang gagal dikirim tidak tercatat sehingga dicoba lagi di putaran berikutnya. Instance lain yang mencatat
+// reminder yang sama menunggu transaksi ini selesai, jadi email tidak terkirim dua kali
+func (s *reminderService) deliver(task *taskmodel.Task, userID uuid.UUID, kind string, result *remindermodel.RunResult, send func() error) {
+	reminder := &remindermodel.TaskReminder{
+		TaskID:  task.ID,
+		UserID:  userID,
+		Kind:    kind,
+		DueDate: *task.DueDate,
+	}
+
+	err := s.repo.Transaction(func(repo reminderrepository.ReminderRepository) error {
+		claimed, err := repo.Claim(reminder)
+		if err != nil || !claimed {
+			return err
+		}
+		if err := send(); err != nil {
+			return err
+		}
+		result.Sent++
+		return nil
+	})
+	if err != nil {
+		result.Failed++
+		fmt.Printf("Gagal mengirim reminder %s task %s: %v\n", kind, task.ID, err)
+	}
+}
+
+func (s *reminderService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunDue(time.Now()); err != nil {
+				fmt.Printf("Gagal menjalankan reminder due date: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
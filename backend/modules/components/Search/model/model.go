@@ -0,0 +1,37 @@
+package searchmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SearchQuery struct {
+	Query     string `form:"q" binding:"required"`
+	Types     string `form:"types"`
+	ProjectID string `form:"project_id"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+type SearchFilter struct {
+	Query     string
+	Types     []string
+	ProjectID *uuid.UUID
+	UserID    uuid.UUID
+	IsAdmin   bool
+	Limit     int
+	Offset    int
+}
+
+type SearchResult struct {
+	Type        string    `json:"type"`
+	ID          uuid.UUID `json:"id"`
+	TaskID      uuid.UUID `json:"task_id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	Title       string    `json:"title"`
+	Highlight   string    `json:"highlight"`
+	Rank        float64   `json:"rank"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,158 @@
+package searchrepository
+
+import (
+	"fmt"
+	searchmodel "gintugas/modules/components/Search/model"
+	"html"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepository interface {
+	Search(filter searchmodel.SearchFilter) ([]searchmodel.SearchResult, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &repository{db: db}
+}
+
+// ts_headline tidak meng-escape teks di sekitar kata yang cocok, jadi penanda kata memakai karakter kontrol yang dibuang dulu
+// dari teks sumber. Highlight di-escape sebagai HTML di Go lalu penandanya diganti <mark>
+const (
+	markStart = "\x02"
+	markStop  = "\x03"
+)
+
+var markReplacer = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// headline membuat ekspresi ts_headline untuk teks sumber dengan konfigurasi text search tertentu
+func headline(config, text string) string {
+	return fmt.Sprintf(`ts_headline('%s', translate(%s, chr(2) || chr(3), ''), q.query,
+				'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=20, MinWords=5')`, config, text)
+}
+
+// satu sub-query per jenis data, semuanya dibatasi ke project yang boleh dilihat user
+var searchSources = map[string]string{
+	"task": `
+		SELECT
+			'task' AS type,
+			t.id,
+			t.id AS task_id,
+			t.project_id,
+			p.nama AS project_name,
+			t.title,
+			` + headline("english", "t.title || ' ' || coalesce(t.description, '')") + ` AS highlight,
+			ts_rank(t.search_vector, q.query) AS rank,
+			t.created_at
+		FROM tasks t
+		JOIN projects p ON p.id = t.project_id
+		CROSS JOIN q
+		WHERE t.search_vector @@ q.query
+			AND t.deleted_at IS NULL
+			AND t.project_id IN (SELECT id FROM visible_projects)`,
+
+	"comment": `
+		SELECT
+			'comment' AS type,
+			c.id,
+			c.task_id,
+			t.project_id,
+			p.nama AS project_name,
+			t.title,
+			` + headline("english", "c.content") + ` AS highlight,
+			ts_rank(c.search_vector, q.query) AS rank,
+			c.created_at
+		FROM comments c
+		JOIN tasks t ON t.id = c.task_id
+		JOIN projects p ON p.id = t.project_id
+		CROSS JOIN q
+		WHERE c.search_vector @@ q.query
+			AND c.deleted_at IS NULL
+			AND t.deleted_at IS NULL
+			AND t.project_id IN (SELECT id FROM visible_projects)`,
+
+	"attachment": `
+		SELECT
+			'attachment' AS type,
+			a.id,
+			a.task_id,
+			t.project_id,
+			p.nama AS project_name,
+			a.file_name AS title,
+			` + headline("simple", "regexp_replace(a.file_name, '[._-]+', ' ', 'g')") + ` AS highlight,
+			ts_rank(a.search_vector, q.query) AS rank,
+			a.created_at
+		FROM attachments a
+		JOIN tasks t ON t.id = a.task_id
+		JOIN projects p ON p.id = t.project_id
+		CROSS JOIN q
+		WHERE a.search_vector @@ q.query
+			AND t.deleted_at IS NULL
+			AND t.project_id IN (SELECT id FROM visible_projects)`,
+}
+
+// SearchTypes adalah urutan jenis data yang bisa dicari
+var SearchTypes = []string{"task", "comment", "attachment"}
+
+func (r *repository) Search(filter searchmodel.SearchFilter) ([]searchmodel.SearchResult, error) {
+	results := []searchmodel.SearchResult{}
+
+	var parts []string
+	for _, t := range filter.Types {
+		if source, ok := searchSources[t]; ok {
+			parts = append(parts, source)
+		}
+	}
+	if len(parts) == 0 {
+		return results, nil
+	}
+
+	query := `
+		WITH q AS (
+			SELECT websearch_to_tsquery('indonesian', @query)
+				|| websearch_to_tsquery('english', @query)
+				|| websearch_to_tsquery('simple', @query) AS query
+		),
+		visible_projects AS (
+			SELECT p.id
+			FROM projects p
+			WHERE p.deleted_at IS NULL
+				AND (@is_admin
+					OR p.manager_id = @user_id
+					OR EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = @user_id))
+		)
+		SELECT * FROM (` + strings.Join(parts, "\n\t\tUNION ALL\n") + `
+		) results`
+
+	args := map[string]interface{}{
+		"query":    filter.Query,
+		"is_admin": filter.IsAdmin,
+		"user_id":  filter.UserID,
+		"limit":    filter.Limit,
+		"offset":   filter.Offset,
+	}
+
+	if filter.ProjectID != nil {
+		query += `
+		WHERE project_id = @project_id`
+		args["project_id"] = *filter.ProjectID
+	}
+
+	query += `
+		ORDER BY rank DESC, created_at DESC
+		LIMIT @limit OFFSET @offset`
+
+	if err := r.db.Raw(query, args).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Highlight = markReplacer.Replace(html.EscapeString(results[i].Highlight))
+	}
+
+	return results, nil
+}
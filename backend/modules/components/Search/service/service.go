@@ -0,0 +1,85 @@
+package searchservice
+
+import (
+	"errors"
+	"fmt"
+	searchmodel "gintugas/modules/components/Search/model"
+	searchrepository "gintugas/modules/components/Search/repository"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SearchService interface {
+	Search(ctx *gin.Context) ([]searchmodel.SearchResult, error)
+}
+
+type searchService struct {
+	repo searchrepository.SearchRepository
+}
+
+func NewSearchService(repo searchrepository.SearchRepository) SearchService {
+	return &searchService{
+		repo: repo,
+	}
+}
+
+func (s *searchService) Search(ctx *gin.Context) ([]searchmodel.SearchResult, error) {
+	var query searchmodel.SearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, errors.New("parameter q wajib diisi")
+	}
+
+	filter := searchmodel.SearchFilter{
+		Query:  strings.TrimSpace(query.Query),
+		Types:  searchrepository.SearchTypes,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	if utf8.RuneCountInString(filter.Query) < 2 {
+		return nil, errors.New("kata kunci minimal 2 karakter")
+	}
+
+	if query.Types != "" {
+		filter.Types = nil
+		for _, t := range strings.Split(query.Types, ",") {
+			t = strings.TrimSpace(t)
+			if t != "task" && t != "comment" && t != "attachment" {
+				return nil, fmt.Errorf("types tidak valid: %s", t)
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	if query.ProjectID != "" {
+		projectID, err := uuid.Parse(query.ProjectID)
+		if err != nil {
+			return nil, errors.New("project_id tidak valid")
+		}
+		filter.ProjectID = &projectID
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 50 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return nil, errors.New("invalid user id format: " + err.Error())
+	}
+	filter.UserID = userUUID
+	filter.IsAdmin = ctx.GetString("user_role") == "admin"
+
+	return s.repo.Search(filter)
+}
@@ -0,0 +1,45 @@
+package taskmodel
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskAssignee adalah user yang mengerjakan task, satu task hanya punya satu assignee utama
+type TaskAssignee struct {
+	TaskID    uuid.UUID `json:"task_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	IsPrimary bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *usermodels.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (TaskAssignee) TableName() string {
+	return "task_assignees"
+}
+
+// TaskWatcher adalah user yang ikut menerima notifikasi task tanpa ikut mengerjakan
+type TaskWatcher struct {
+	TaskID    uuid.UUID `json:"task_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *usermodels.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (TaskWatcher) TableName() string {
+	return "task_watchers"
+}
+
+type AssigneeRequest struct {
+	UserID    uuid.UUID `json:"user_id" binding:"required"`
+	IsPrimary bool      `json:"is_primary"`
+}
+
+// WatcherRequest tanpa user_id berarti user yang login menjadi watcher
+type WatcherRequest struct {
+	UserID *uuid.UUID `json:"user_id"`
+}
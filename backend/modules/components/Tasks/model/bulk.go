@@ -0,0 +1,43 @@
+package taskmodel
+
+import (
+	patch "gintugas/modules/components/Patch"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BulkActionUpdate = "update"
+	BulkActionDelete = "delete"
+)
+
+// BulkTaskRequest menerapkan satu perubahan ke banyak task sekaligus. Changes memakai aturan JSON Merge Patch
+// dan hanya menerima status, assignee_id dan due_date
+type BulkTaskRequest struct {
+	TaskIDs []uuid.UUID    `json:"task_ids" binding:"required,min=1,max=100"`
+	Action  string         `json:"action" binding:"required,oneof=update delete"`
+	Changes patch.Document `json:"changes"`
+}
+
+// hasil per task
+const (
+	BulkUpdated   = "updated"
+	BulkUnchanged = "unchanged"
+	BulkDeleted   = "deleted"
+	BulkFailed    = "failed"
+	// BulkSkipped dipakai untuk task yang valid tetapi tidak disimpan karena task lain gagal
+	BulkSkipped = "skipped"
+)
+
+type BulkTaskItemResult struct {
+	TaskID  uuid.UUID `json:"task_id"`
+	Result  string    `json:"result"`
+	Error   string    `json:"error,omitempty"`
+	Version int       `json:"version,omitempty"`
+}
+
+// BulkTaskResult: Applied false berarti tidak ada perubahan yang disimpan sama sekali
+type BulkTaskResult struct {
+	Applied bool                 `json:"applied"`
+	Results []BulkTaskItemResult `json:"results"`
+}
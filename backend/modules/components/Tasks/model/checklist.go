@@ -0,0 +1,50 @@
+package taskmodel
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChecklistItem adalah satu langkah kecil di dalam task, diurutkan berdasarkan Position
+type ChecklistItem struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TaskID     uuid.UUID  `json:"task_id" gorm:"type:uuid;not null"`
+	Content    string     `json:"content" gorm:"type:varchar(500);not null"`
+	Position   string     `json:"position" gorm:"type:text;not null"`
+	IsChecked  bool       `json:"is_checked" gorm:"not null;default:false"`
+	CheckedAt  *time.Time `json:"checked_at"`
+	CheckedBy  *uuid.UUID `json:"checked_by" gorm:"type:uuid"`
+	AssigneeID *uuid.UUID `json:"assignee_id" gorm:"type:uuid"`
+	CreatedBy  *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Assignee *usermodels.User `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+type ChecklistItemRequest struct {
+	Content    string     `json:"content" binding:"required,max=500"`
+	AssigneeID *uuid.UUID `json:"assignee_id"`
+}
+
+// MoveChecklistItemRequest sama seperti MoveTaskRequest: item tepat di atas dan/atau di bawah posisi baru
+type MoveChecklistItemRequest struct {
+	PreviousID *uuid.UUID `json:"previous_id"`
+	NextID     *uuid.UUID `json:"next_id"`
+}
+
+// Checklist adalah daftar item beserta jumlah yang sudah dicentang.
+// SuggestDone bernilai true jika semua item sudah dicentang tetapi task belum done
+type Checklist struct {
+	TaskID      uuid.UUID       `json:"task_id"`
+	Total       int             `json:"total"`
+	Done        int             `json:"done"`
+	SuggestDone bool            `json:"suggest_done"`
+	Items       []ChecklistItem `json:"items"`
+}
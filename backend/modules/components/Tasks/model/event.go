@@ -0,0 +1,36 @@
+package taskmodel
+
+import "github.com/google/uuid"
+
+// sumber event task, kosong untuk perubahan lewat API task
+const (
+	EventSourceAutomation = "automation"
+	EventSourceRecurrence = "recurrence"
+	EventSourceImport     = "import"
+	EventSourceTemplate   = "template"
+)
+
+// TaskEvent dikirim ke subscriber setiap kali task dibuat, diubah atau dihapus
+type TaskEvent struct {
+	Action  string
+	Task    Task
+	Changes []FieldChange
+	ActorID *uuid.UUID
+	// Source menandai event dari proses selain API task, misalnya automation supaya rule tidak memicu dirinya sendiri
+	Source string
+}
+
+// TaskEventPublisher dipakai service lain yang membuat task langsung lewat repository supaya subscriber tetap menerima event
+type TaskEventPublisher interface {
+	PublishTaskEvent(event TaskEvent)
+}
+
+// HasChange mengecek apakah field tertentu ikut berubah di event ini
+func (e TaskEvent) HasChange(field string) bool {
+	for _, change := range e.Changes {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
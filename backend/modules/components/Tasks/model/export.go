@@ -0,0 +1,72 @@
+package taskmodel
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskExport adalah satu task di file export, assignee utama selalu di urutan pertama
+type TaskExport struct {
+	ID           uuid.UUID            `json:"id"`
+	ProjectID    uuid.UUID            `json:"project_id"`
+	ProjectName  string               `json:"project_name,omitempty"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	Status       string               `json:"status"`
+	StartDate    *time.Time           `json:"start_date"`
+	DueDate      *time.Time           `json:"due_date"`
+	Assignees    []TaskExportAssignee `json:"assignees"`
+	CommentCount int                  `json:"comment_count"`
+	Archived     bool                 `json:"archived"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+type TaskExportAssignee struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	IsPrimary bool      `json:"is_primary"`
+}
+
+// TaskExportHeader adalah judul kolom CSV dan XLSX, urutannya sama dengan TaskExport.Row
+var TaskExportHeader = []string{
+	"ID", "Project", "Title", "Status", "Primary Assignee", "Assignees", "Start Date", "Due Date", "Comments", "Archived", "Created At", "Updated At", "Description",
+}
+
+func (t TaskExport) Row() []string {
+	primary := ""
+	assignees := make([]string, 0, len(t.Assignees))
+	for _, assignee := range t.Assignees {
+		if assignee.IsPrimary {
+			primary = assignee.Email
+		}
+		assignees = append(assignees, assignee.Email)
+	}
+
+	return []string{
+		t.ID.String(),
+		t.ProjectName,
+		t.Title,
+		t.Status,
+		primary,
+		strings.Join(assignees, ", "),
+		exportDate(t.StartDate),
+		exportDate(t.DueDate),
+		strconv.Itoa(t.CommentCount),
+		strconv.FormatBool(t.Archived),
+		t.CreatedAt.Format(time.RFC3339),
+		t.UpdatedAt.Format(time.RFC3339),
+		t.Description,
+	}
+}
+
+func exportDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,103 @@
+package taskmodel
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HistoryCreated  = "created"
+	HistoryUpdated  = "updated"
+	HistoryDeleted  = "deleted"
+	HistoryRestored = "restored"
+	// HistoryArchived dan HistoryUnarchived dicatat tanpa daftar perubahan field
+	HistoryArchived   = "archived"
+	HistoryUnarchived = "unarchived"
+)
+
+// FieldChange menyimpan nilai lama dan baru dari satu field task
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type TaskHistory struct {
+	ID        uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TaskID    uuid.UUID     `json:"task_id" gorm:"type:uuid;not null"`
+	ProjectID uuid.UUID     `json:"project_id" gorm:"type:uuid;not null"`
+	TaskTitle string        `json:"task_title" gorm:"type:varchar(200);not null"`
+	ActorID   *uuid.UUID    `json:"actor_id" gorm:"type:uuid"`
+	Action    string        `json:"action" gorm:"type:varchar(20);not null"`
+	Changes   []FieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time     `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Actor *usermodels.User `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
+
+func (TaskHistory) TableName() string {
+	return "task_history"
+}
+
+func uuidValue(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+func intValue(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func dateValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+// taskFields adalah daftar field yang dicatat di history beserta cara membaca nilainya
+var taskFields = []struct {
+	name  string
+	value func(t *Task) interface{}
+}{
+	{"title", func(t *Task) interface{} { return t.Title }},
+	{"description", func(t *Task) interface{} { return t.Description }},
+	{"status", func(t *Task) interface{} { return t.Status }},
+	{"assignee_id", func(t *Task) interface{} { return uuidValue(t.AssigneeID) }},
+	{"due_date", func(t *Task) interface{} { return dateValue(t.DueDate) }},
+	{"start_date", func(t *Task) interface{} { return dateValue(t.StartDate) }},
+	{"sprint_id", func(t *Task) interface{} { return uuidValue(t.SprintID) }},
+	{"milestone_id", func(t *Task) interface{} { return uuidValue(t.MilestoneID) }},
+	{"original_estimate_minutes", func(t *Task) interface{} { return intValue(t.OriginalEstimateMinutes) }},
+}
+
+// DiffTask membandingkan dua versi task dan mengembalikan field yang berubah.
+// before nil berarti task baru dibuat, sehingga semua field yang terisi dianggap berubah
+func DiffTask(before, after *Task) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range taskFields {
+		newValue := field.value(after)
+
+		var oldValue interface{}
+		if before != nil {
+			oldValue = field.value(before)
+		}
+
+		if oldValue == newValue {
+			continue
+		}
+		if before == nil && (newValue == nil || newValue == "") {
+			continue
+		}
+
+		changes = append(changes, FieldChange{Field: field.name, Old: oldValue, New: newValue})
+	}
+	return changes
+}
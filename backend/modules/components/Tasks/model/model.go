@@ -1,11 +1,13 @@
 package taskmodel
 
 import (
+	archive "gintugas/modules/components/Archive"
 	usermodels "gintugas/modules/components/Auth/model"
 	projectmodel "gintugas/modules/components/Project/model"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Task struct {
@@ -16,31 +18,161 @@ type Task struct {
 	Status      string     `json:"status" gorm:"type:task_status;default:'todo'"`
 	AssigneeID  *uuid.UUID `json:"assignee_id" gorm:"type:uuid"`
 	DueDate     *time.Time `json:"due_date" gorm:"type:date"`
+	StartDate   *time.Time `json:"start_date" gorm:"type:date"`
+	Version     int        `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt   time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 
-	Project  projectmodel.Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
-	Assignee *usermodels.User     `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	// Position adalah key fractional indexing untuk urutan board, lihat package rank
+	Position string `json:"position" gorm:"type:text;not null"`
+
+	OriginalEstimateMinutes *int `json:"original_estimate_minutes"`
+	// TimeSpentMinutes dan jumlah checklist hanya dibaca lewat sub-query di repository, tidak pernah disimpan
+	TimeSpentMinutes int `json:"time_spent_minutes" gorm:"->;-:migration"`
+	ChecklistTotal   int `json:"checklist_total" gorm:"->;-:migration"`
+	ChecklistDone    int `json:"checklist_done" gorm:"->;-:migration"`
+
+	SprintID    *uuid.UUID `json:"sprint_id" gorm:"type:uuid"`
+	MilestoneID *uuid.UUID `json:"milestone_id" gorm:"type:uuid"`
+
+	RecurrenceID        *uuid.UUID `json:"recurrence_id" gorm:"type:uuid"`
+	OccurrenceDate      *time.Time `json:"occurrence_date" gorm:"type:date"`
+	RecurrenceException bool       `json:"recurrence_exception" gorm:"not null;default:false"`
+
+	// task yang diarsipkan, atau yang project-nya diarsipkan, hanya bisa dibaca
+	ArchivedAt      *time.Time `json:"archived_at"`
+	ArchivedBy      *uuid.UUID `json:"archived_by" gorm:"type:uuid"`
+	ProjectArchived bool       `json:"project_archived" gorm:"->;-:migration"`
+
+	// DeletedAt membuat Delete gorm menjadi soft delete, task di trash tidak ikut query biasa
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy *uuid.UUID     `json:"-" gorm:"type:uuid"`
+
+	Project   projectmodel.Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Assignee  *usermodels.User     `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	Assignees []TaskAssignee       `json:"assignees,omitempty" gorm:"foreignKey:TaskID"`
+	Watchers  []TaskWatcher        `json:"watchers,omitempty" gorm:"foreignKey:TaskID"`
+}
+
+// IsReadOnly: task yang diarsipkan atau berada di project yang diarsipkan tidak boleh diubah
+func (t *Task) IsReadOnly() bool {
+	return t.ArchivedAt != nil || t.ProjectArchived
 }
 
 type TaskRequest struct {
 	Title       string     `json:"title" binding:"required"`
 	Description string     `json:"description"`
-	Status      string     `json:"status" binding:"omitempty,oneof=todo in-progress done"`
+	Status      string     `json:"status" binding:"omitempty,oneof=todo in-progress in-review done"`
 	AssigneeID  *uuid.UUID `json:"assignee_id"`
 	DueDate     *time.Time `json:"due_date"`
+	StartDate   *time.Time `json:"start_date"`
+
+	OriginalEstimateMinutes *int `json:"original_estimate_minutes" binding:"omitempty,min=0"`
+
+	SprintID    *uuid.UUID `json:"sprint_id"`
+	MilestoneID *uuid.UUID `json:"milestone_id"`
 }
 
 type TaskResponse struct {
-	ID          uuid.UUID             `json:"id"`
-	ProjectID   uuid.UUID             `json:"project_id"`
-	Title       string                `json:"title"`
-	Description string                `json:"description"`
-	Status      string                `json:"status"`
-	AssigneeID  *uuid.UUID            `json:"assignee_id"`
-	DueDate     *time.Time            `json:"due_date"`
-	CreatedAt   time.Time             `json:"created_at"`
-	UpdatedAt   time.Time             `json:"updated_at"`
-	Assignee    *usermodels.User      `json:"assignee,omitempty"`
-	Project     *projectmodel.Project `json:"project,omitempty"`
+	ID          uuid.UUID  `json:"id"`
+	ProjectID   uuid.UUID  `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	AssigneeID  *uuid.UUID `json:"assignee_id"`
+	DueDate     *time.Time `json:"due_date"`
+	StartDate   *time.Time `json:"start_date"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Position    string     `json:"position"`
+
+	OriginalEstimateMinutes  *int `json:"original_estimate_minutes"`
+	TimeSpentMinutes         int  `json:"time_spent_minutes"`
+	RemainingEstimateMinutes *int `json:"remaining_estimate_minutes"`
+
+	ChecklistTotal int `json:"checklist_total"`
+	ChecklistDone  int `json:"checklist_done"`
+
+	SprintID    *uuid.UUID `json:"sprint_id"`
+	MilestoneID *uuid.UUID `json:"milestone_id"`
+
+	RecurrenceID        *uuid.UUID `json:"recurrence_id"`
+	OccurrenceDate      *time.Time `json:"occurrence_date"`
+	RecurrenceException bool       `json:"recurrence_exception"`
+
+	ArchivedAt      *time.Time `json:"archived_at"`
+	ArchivedBy      *uuid.UUID `json:"archived_by"`
+	ProjectArchived bool       `json:"project_archived"`
+	ReadOnly        bool       `json:"read_only"`
+
+	Assignee  *usermodels.User      `json:"assignee,omitempty"`
+	Assignees []TaskAssignee        `json:"assignees,omitempty"`
+	Watchers  []TaskWatcher         `json:"watchers,omitempty"`
+	Project   *projectmodel.Project `json:"project,omitempty"`
+}
+
+// MoveTaskRequest memindahkan task di board. PreviousID adalah task tepat di atas posisi baru dan NextID task tepat di bawahnya;
+// cukup isi salah satu. Tanpa keduanya task dipindah ke paling bawah kolom
+type MoveTaskRequest struct {
+	Status     string     `json:"status"`
+	PreviousID *uuid.UUID `json:"previous_id"`
+	NextID     *uuid.UUID `json:"next_id"`
+}
+
+// MoveToProjectRequest memindahkan task beserta comment dan attachment-nya ke project lain
+type MoveToProjectRequest struct {
+	ProjectID uuid.UUID `json:"project_id" binding:"required"`
+}
+
+// CloneTaskRequest: tanpa ProjectID task disalin ke project yang sama, tanpa Title judul asli dipakai.
+// Include* menentukan data apa saja yang ikut disalin
+type CloneTaskRequest struct {
+	ProjectID          *uuid.UUID `json:"project_id"`
+	Title              string     `json:"title" binding:"max=200"`
+	IncludeAssignees   bool       `json:"include_assignees"`
+	IncludeChecklist   bool       `json:"include_checklist"`
+	IncludeComments    bool       `json:"include_comments"`
+	IncludeAttachments bool       `json:"include_attachments"`
+}
+
+// TaskListQuery menampung query parameter untuk listing task
+type TaskListQuery struct {
+	Status     string `form:"status"`
+	AssigneeID string `form:"assignee_id"`
+	DueFrom    string `form:"due_from"`
+	DueTo      string `form:"due_to"`
+	Overdue    string `form:"overdue"`
+	Sprint     string `form:"sprint_id"`
+	Milestone  string `form:"milestone_id"`
+	Archived   string `form:"archived"`
+	Search     string `form:"q"`
+	Sort       string `form:"sort"`
+	Cursor     string `form:"cursor"`
+	Limit      int    `form:"limit"`
+}
+
+// TaskFilter adalah hasil parsing TaskListQuery yang dipakai repository
+type TaskFilter struct {
+	Statuses   []string
+	AssigneeID *uuid.UUID
+	DueFrom    *time.Time
+	DueTo      *time.Time
+	Overdue    *bool
+	Search     string
+	Sort       string
+	Desc       bool
+	Cursor     string
+	Limit      int
+
+	// uuid.Nil berarti task yang belum masuk sprint/milestone mana pun
+	SprintID    *uuid.UUID
+	MilestoneID *uuid.UUID
+
+	Archived archive.Filter
+}
+
+type TaskPage struct {
+	Tasks      []TaskResponse `json:"tasks"`
+	NextCursor *string        `json:"next_cursor"`
 }
@@ -0,0 +1,51 @@
+package taskmodel
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// status task selama menunggu keputusan review manager
+const StatusInReview = "in-review"
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// TaskReview adalah satu pengajuan review task. Selama status task in-review selalu ada tepat satu review pending
+type TaskReview struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TaskID      uuid.UUID  `json:"task_id" gorm:"type:uuid;not null"`
+	SubmittedBy *uuid.UUID `json:"submitted_by" gorm:"type:uuid"`
+	Note        string     `json:"note" gorm:"type:text"`
+	Decision    string     `json:"decision" gorm:"type:review_decision;default:'pending'"`
+	ReviewerID  *uuid.UUID `json:"reviewer_id" gorm:"type:uuid"`
+	Comment     string     `json:"comment" gorm:"type:text"`
+	SubmittedAt time.Time  `json:"submitted_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+
+	Submitter *usermodels.User `json:"submitter,omitempty" gorm:"foreignKey:SubmittedBy"`
+	Reviewer  *usermodels.User `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID"`
+}
+
+func (TaskReview) TableName() string {
+	return "task_reviews"
+}
+
+// TaskStatusRequest dipakai assignee untuk memindahkan task miliknya sendiri, done hanya lewat approval review
+type TaskStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=todo in-progress"`
+}
+
+type SubmitReviewRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+// ReviewDecisionRequest: comment wajib diisi saat menolak review
+type ReviewDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
@@ -0,0 +1,86 @@
+package taskmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// scope saved view: task satu project, task di semua project tempat user menjadi manager atau member,
+// atau task yang di-assign ke user yang membuka view
+const (
+	ViewScopeProject    = "project"
+	ViewScopeMyProjects = "my_projects"
+	ViewScopeMyTasks    = "my_tasks"
+)
+
+// view shared hanya bisa dibuat untuk scope project dan terlihat oleh manager serta member project tersebut
+const (
+	ViewPrivate = "private"
+	ViewShared  = "shared"
+)
+
+// dashboard yang bisa memakai saved view sebagai default
+const (
+	DashboardManager = "manager"
+	DashboardStaff   = "staff"
+)
+
+// ViewFilters memakai nama dan format yang sama dengan query parameter listing task (lihat TaskListQuery).
+// assignee_id "me" berarti user yang sedang membuka view, berguna untuk view shared
+type ViewFilters struct {
+	Status     string `json:"status,omitempty"`
+	AssigneeID string `json:"assignee_id,omitempty"`
+	DueFrom    string `json:"due_from,omitempty"`
+	DueTo      string `json:"due_to,omitempty"`
+	Overdue    string `json:"overdue,omitempty"`
+	Sprint     string `json:"sprint_id,omitempty"`
+	Milestone  string `json:"milestone_id,omitempty"`
+	Archived   string `json:"archived,omitempty"`
+	Search     string `json:"q,omitempty"`
+}
+
+type SavedView struct {
+	ID         uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID    uuid.UUID   `json:"owner_id" gorm:"type:uuid;not null"`
+	ProjectID  *uuid.UUID  `json:"project_id" gorm:"type:uuid"`
+	Name       string      `json:"name" gorm:"type:varchar(100);not null"`
+	Scope      string      `json:"scope" gorm:"type:saved_view_scope;not null"`
+	Visibility string      `json:"visibility" gorm:"type:saved_view_visibility;not null;default:'private'"`
+	Filters    ViewFilters `json:"filters" gorm:"type:jsonb;serializer:json"`
+	Sort       string      `json:"sort" gorm:"type:varchar(50);not null;default:''"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time   `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	// PinnedOn berisi dashboard tempat view ini menjadi default untuk user yang login
+	PinnedOn []string `json:"pinned_on" gorm:"-"`
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+type SavedViewPin struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Dashboard string    `json:"dashboard" gorm:"type:varchar(20);primaryKey"`
+	ViewID    uuid.UUID `json:"view_id" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (SavedViewPin) TableName() string {
+	return "saved_view_pins"
+}
+
+// SavedViewRequest: project_id wajib untuk scope project dan harus kosong untuk scope lain, visibility kosong berarti private
+type SavedViewRequest struct {
+	Name       string      `json:"name" binding:"required,max=100"`
+	Scope      string      `json:"scope" binding:"required,oneof=project my_projects my_tasks"`
+	ProjectID  *uuid.UUID  `json:"project_id"`
+	Visibility string      `json:"visibility" binding:"omitempty,oneof=private shared"`
+	Filters    ViewFilters `json:"filters"`
+	Sort       string      `json:"sort" binding:"max=50"`
+}
+
+type PinViewRequest struct {
+	Dashboard string `json:"dashboard" binding:"required,oneof=manager staff"`
+}
@@ -0,0 +1,107 @@
+package taskrepository
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// assignedToUser mencocokkan task yang dikerjakan user, baik sebagai assignee utama maupun tambahan
+const assignedToUser = "EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = ?)"
+
+// assignee utama selalu di urutan pertama
+func orderAssignees(db *gorm.DB) *gorm.DB {
+	return db.Order("is_primary DESC, created_at ASC")
+}
+
+func (r *taskRepository) GetTaskAssignees(taskID uuid.UUID) ([]taskmodel.TaskAssignee, error) {
+	assignees := []taskmodel.TaskAssignee{}
+	err := orderAssignees(r.db.Where("task_id = ?", taskID)).
+		Preload("User").
+		Find(&assignees).Error
+	return assignees, err
+}
+
+// UpsertTaskAssignee menambahkan assignee, jika sudah ada hanya status primary-nya yang diperbarui
+func (r *taskRepository) UpsertTaskAssignee(assignee *taskmodel.TaskAssignee) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_primary"}),
+	}).Create(assignee).Error
+}
+
+func (r *taskRepository) RemoveTaskAssignee(taskID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Where("task_id = ? AND user_id = ?", taskID, userID).
+		Delete(&taskmodel.TaskAssignee{}).Error
+}
+
+// DemotePrimaryAssignee menjadikan assignee utama saat ini sebagai assignee biasa
+func (r *taskRepository) DemotePrimaryAssignee(taskID uuid.UUID) error {
+	return r.db.Model(&taskmodel.TaskAssignee{}).
+		Where("task_id = ? AND is_primary", taskID).
+		Update("is_primary", false).Error
+}
+
+func (r *taskRepository) IsTaskAssignee(taskID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&taskmodel.TaskAssignee{}).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *taskRepository) GetTaskWatchers(taskID uuid.UUID) ([]taskmodel.TaskWatcher, error) {
+	watchers := []taskmodel.TaskWatcher{}
+	err := r.db.Where("task_id = ?", taskID).
+		Preload("User").
+		Order("created_at ASC").
+		Find(&watchers).Error
+	return watchers, err
+}
+
+func (r *taskRepository) AddTaskWatcher(watcher *taskmodel.TaskWatcher) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(watcher).Error
+}
+
+func (r *taskRepository) RemoveTaskWatcher(taskID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Where("task_id = ? AND user_id = ?", taskID, userID).
+		Delete(&taskmodel.TaskWatcher{}).Error
+}
+
+func sameAssignee(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ReplacePrimaryAssignee menyamakan task_assignees dengan tasks.assignee_id.
+// Jika keepPrevious, assignee utama lama tetap menjadi assignee tambahan, jika tidak dilepas dari task
+func (r *taskRepository) ReplacePrimaryAssignee(taskID uuid.UUID, previous, next *uuid.UUID, keepPrevious bool) error {
+	if sameAssignee(previous, next) {
+		return nil
+	}
+
+	if previous != nil {
+		var err error
+		if keepPrevious {
+			err = r.DemotePrimaryAssignee(taskID)
+		} else {
+			err = r.RemoveTaskAssignee(taskID, *previous)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if next == nil {
+		return nil
+	}
+	return r.UpsertTaskAssignee(&taskmodel.TaskAssignee{
+		TaskID:    taskID,
+		UserID:    *next,
+		IsPrimary: true,
+	})
+}
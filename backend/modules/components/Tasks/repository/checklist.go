@@ -0,0 +1,87 @@
+package taskrepository
+
+import (
+	rank "gintugas/modules/components/Rank"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+func (r *taskRepository) GetChecklistItems(taskID uuid.UUID) ([]taskmodel.ChecklistItem, error) {
+	items := []taskmodel.ChecklistItem{}
+	err := r.db.Where("task_id = ?", taskID).
+		Preload("Assignee").
+		Order("position ASC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *taskRepository) GetChecklistItem(taskID uuid.UUID, itemID uuid.UUID) (*taskmodel.ChecklistItem, error) {
+	var item taskmodel.ChecklistItem
+	err := r.db.Where("id = ? AND task_id = ?", itemID, taskID).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateChecklistItem menaruh item baru di paling bawah jika posisi belum diisi
+func (r *taskRepository) CreateChecklistItem(item *taskmodel.ChecklistItem) error {
+	if item.Position == "" {
+		last, err := r.GetLastChecklistPosition(item.TaskID, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if item.Position, err = rank.Between(last, ""); err != nil {
+			return err
+		}
+	}
+	return r.db.Omit(clause.Associations).Create(item).Error
+}
+
+func (r *taskRepository) UpdateChecklistItem(item *taskmodel.ChecklistItem) error {
+	item.UpdatedAt = time.Now()
+	return r.db.Model(item).
+		Select("*").
+		Omit("id", "task_id", "created_by", "created_at", clause.Associations).
+		Updates(item).Error
+}
+
+func (r *taskRepository) DeleteChecklistItem(itemID uuid.UUID) error {
+	return r.db.Delete(&taskmodel.ChecklistItem{}, "id = ?", itemID).Error
+}
+
+func (r *taskRepository) GetLastChecklistPosition(taskID uuid.UUID, excludeID uuid.UUID) (string, error) {
+	var positions []string
+	err := r.db.Model(&taskmodel.ChecklistItem{}).
+		Where("task_id = ? AND id <> ?", taskID, excludeID).
+		Order("position DESC").
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+func (r *taskRepository) GetAdjacentChecklistPosition(taskID uuid.UUID, position string, after bool, excludeID uuid.UUID) (string, error) {
+	condition, order := "position > ?", "position ASC"
+	if !after {
+		condition, order = "position < ?", "position DESC"
+	}
+
+	var positions []string
+	err := r.db.Model(&taskmodel.ChecklistItem{}).
+		Where("task_id = ? AND id <> ?", taskID, excludeID).
+		Where(condition, position).
+		Order(order).
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
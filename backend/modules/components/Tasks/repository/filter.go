@@ -0,0 +1,244 @@
+package taskrepository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTaskLimit = 50
+	MaxTaskLimit     = 100
+	DefaultTaskSort  = "created_at"
+	// BoardTaskSort adalah urutan default listing task project: per status lalu posisi di board
+	BoardTaskSort = "position"
+)
+
+// taskSortColumn mendeskripsikan satu kolom sort beserta cara membaca nilainya dari task
+type taskSortColumn struct {
+	expr  string
+	cast  string
+	value func(task *taskmodel.Task) string
+}
+
+var taskSortColumns = map[string][]taskSortColumn{
+	"created_at": {{
+		expr:  "tasks.created_at",
+		cast:  "timestamptz",
+		value: func(t *taskmodel.Task) string { return t.CreatedAt.Format(time.RFC3339Nano) },
+	}},
+	"updated_at": {{
+		expr:  "tasks.updated_at",
+		cast:  "timestamptz",
+		value: func(t *taskmodel.Task) string { return t.UpdatedAt.Format(time.RFC3339Nano) },
+	}},
+	"due_date": {{
+		// task tanpa due date selalu di akhir
+		expr: "COALESCE(tasks.due_date, DATE '9999-12-31')",
+		cast: "date",
+		value: func(t *taskmodel.Task) string {
+			if t.DueDate == nil {
+				return "9999-12-31"
+			}
+			return t.DueDate.Format("2006-01-02")
+		},
+	}},
+	"title": {{
+		expr:  "tasks.title",
+		cast:  "text",
+		value: func(t *taskmodel.Task) string { return t.Title },
+	}},
+	"position": {
+		{
+			// urutan enum task_status sama dengan urutan kolom board
+			expr:  "tasks.status",
+			cast:  "task_status",
+			value: func(t *taskmodel.Task) string { return t.Status },
+		},
+		{
+			expr:  "tasks.position",
+			cast:  "text",
+			value: func(t *taskmodel.Task) string { return t.Position },
+		},
+	},
+}
+
+// IsValidTaskSort mengecek apakah nama sort didukung
+func IsValidTaskSort(sort string) bool {
+	_, ok := taskSortColumns[sort]
+	return ok
+}
+
+// taskCursor menyimpan sort tempat cursor dibuat, supaya cursor tidak dipakai dengan sort lain yang kolomnya berbeda
+type taskCursor struct {
+	Sort   string    `json:"s"`
+	Values []string  `json:"v"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// cursorSort adalah nama sort beserta arahnya, "-" untuk descending
+func cursorSort(sort string, desc bool) string {
+	if desc {
+		return "-" + sort
+	}
+	return sort
+}
+
+func encodeTaskCursor(cursor taskCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeTaskCursor(s string, sort string, columns int) (*taskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("cursor tidak valid")
+	}
+
+	var cursor taskCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || len(cursor.Values) != columns {
+		return nil, errors.New("cursor tidak valid")
+	}
+	if cursor.Sort != sort {
+		return nil, errors.New("cursor dibuat untuk sort lain, mulai lagi tanpa cursor")
+	}
+
+	return &cursor, nil
+}
+
+// taskArchived bernilai true untuk task yang diarsipkan sendiri maupun lewat project-nya
+const taskArchived = "(tasks.archived_at IS NOT NULL OR EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL))"
+
+func applyTaskFilter(query *gorm.DB, filter taskmodel.TaskFilter) *gorm.DB {
+	if len(filter.Statuses) > 0 {
+		query = query.Where("tasks.status IN ?", filter.Statuses)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where(assignedToUser, *filter.AssigneeID)
+	}
+	if filter.DueFrom != nil {
+		query = query.Where("tasks.due_date >= ?", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		query = query.Where("tasks.due_date <= ?", *filter.DueTo)
+	}
+	if filter.Overdue != nil {
+		overdue := "tasks.status <> 'done' AND tasks.due_date IS NOT NULL AND tasks.due_date < CURRENT_DATE"
+		if *filter.Overdue {
+			query = query.Where(overdue)
+		} else {
+			query = query.Where("NOT (" + overdue + ")")
+		}
+	}
+	if filter.SprintID != nil {
+		if *filter.SprintID == uuid.Nil {
+			query = query.Where("tasks.sprint_id IS NULL")
+		} else {
+			query = query.Where("tasks.sprint_id = ?", *filter.SprintID)
+		}
+	}
+	if filter.MilestoneID != nil {
+		if *filter.MilestoneID == uuid.Nil {
+			query = query.Where("tasks.milestone_id IS NULL")
+		} else {
+			query = query.Where("tasks.milestone_id = ?", *filter.MilestoneID)
+		}
+	}
+	if condition := filter.Archived.Condition(taskArchived); condition != "" {
+		query = query.Where(condition)
+	}
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where("(tasks.title ILIKE ? OR tasks.description ILIKE ?)", pattern, pattern)
+	}
+	return query
+}
+
+// findTaskPage menjalankan query dengan filter, sort dan cursor lalu mengembalikan cursor halaman berikutnya
+func findTaskPage(query *gorm.DB, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = DefaultTaskSort
+	}
+	columns, ok := taskSortColumns[sort]
+	if !ok {
+		return nil, "", fmt.Errorf("sort tidak didukung: %s", sort)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultTaskLimit
+	}
+	if limit > MaxTaskLimit {
+		limit = MaxTaskLimit
+	}
+
+	direction, operator := "ASC", ">"
+	if filter.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	query = applyTaskFilter(query, filter)
+
+	exprs := make([]string, 0, len(columns)+1)
+	for _, col := range columns {
+		exprs = append(exprs, col.expr)
+	}
+	exprs = append(exprs, "tasks.id")
+
+	if filter.Cursor != "" {
+		cursor, err := decodeTaskCursor(filter.Cursor, cursorSort(sort, filter.Desc), len(columns))
+		if err != nil {
+			return nil, "", err
+		}
+
+		placeholders := make([]string, 0, len(columns)+1)
+		args := make([]interface{}, 0, len(columns)+1)
+		for i, col := range columns {
+			placeholders = append(placeholders, "?::"+col.cast)
+			args = append(args, cursor.Values[i])
+		}
+		placeholders = append(placeholders, "?")
+		args = append(args, cursor.ID)
+
+		query = query.Where(
+			fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), operator, strings.Join(placeholders, ", ")),
+			args...,
+		)
+	}
+
+	for _, expr := range exprs {
+		query = query.Order(expr + " " + direction)
+	}
+
+	var tasks []taskmodel.Task
+	if err := query.Limit(limit + 1).Find(&tasks).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		last := &tasks[len(tasks)-1]
+
+		values := make([]string, 0, len(columns))
+		for _, col := range columns {
+			values = append(values, col.value(last))
+		}
+		nextCursor = encodeTaskCursor(taskCursor{Sort: cursorSort(sort, filter.Desc), Values: values, ID: last.ID})
+	}
+
+	return tasks, nextCursor, nil
+}
+
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
@@ -2,26 +2,100 @@ package taskrepository
 
 import (
 	usermodels "gintugas/modules/components/Auth/model"
+	concurrency "gintugas/modules/components/Concurrency"
 	projectmodel "gintugas/modules/components/Project/model"
+	rank "gintugas/modules/components/Rank"
 	taskmodel "gintugas/modules/components/Tasks/model"
+	attachmentmodel "gintugas/modules/components/attachments/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository interface {
 	CreateTask(task *taskmodel.Task) error
-	GetTasksByProjectID(projectID uuid.UUID) ([]taskmodel.Task, error)
+	GetTasksByProjectID(projectID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error)
 	GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error)
+	// GetCommentCounts menghitung komentar yang belum dihapus per task, task tanpa komentar tidak ada di map
+	GetCommentCounts(taskIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	// GetProjectTasksByIDs mengabaikan id yang tidak ada atau milik project lain
+	GetProjectTasksByIDs(projectID uuid.UUID, taskIDs []uuid.UUID) ([]taskmodel.Task, error)
 	UpdateTask(task *taskmodel.Task) error
-	DeleteTask(taskID uuid.UUID) error
+	// DeleteTask memindahkan task ke trash
+	DeleteTask(taskID uuid.UUID, deletedBy *uuid.UUID) error
+
+	// GetLastTaskPosition dan GetAdjacentTaskPosition mengembalikan "" jika tidak ada task, excludeID diabaikan.
+	// Task di trash ikut dihitung supaya posisinya tidak dipakai task lain sebelum di-restore
+	GetLastTaskPosition(projectID uuid.UUID, excludeID uuid.UUID) (string, error)
+	GetAdjacentTaskPosition(projectID uuid.UUID, position string, after bool, excludeID uuid.UUID) (string, error)
 
 	GettaskbyuserID(userID uuid.UUID) ([]taskmodel.Task, error)
-	GetTasksByAssigneeID(assigneeID uuid.UUID) ([]taskmodel.Task, error)
-	GetTasksByStatus(projectID uuid.UUID, status string) ([]taskmodel.Task, error)
+	GetTasksByAssigneeID(assigneeID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error)
+	// GetTasksByMemberID mengambil task di semua project tempat user menjadi manager atau member
+	GetTasksByMemberID(userID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error)
 	GetUserByID(userID uuid.UUID) (*usermodels.User, error)
 	GetProjectByID(projectID uuid.UUID) (*projectmodel.Project, error)
 	IsProjectMember(projectID uuid.UUID, userID uuid.UUID) (bool, error)
+	// CanViewProject sama seperti IsProjectMember tapi tetap berlaku untuk project yang ada di trash
+	CanViewProject(projectID uuid.UUID, userID uuid.UUID) (bool, error)
+	// GetSprintState dan GetMilestoneProjectID dipakai untuk validasi sprint_id/milestone_id task
+	GetSprintState(sprintID uuid.UUID) (projectID uuid.UUID, status string, err error)
+	GetMilestoneProjectID(milestoneID uuid.UUID) (uuid.UUID, error)
+
+	GetTaskAssignees(taskID uuid.UUID) ([]taskmodel.TaskAssignee, error)
+	UpsertTaskAssignee(assignee *taskmodel.TaskAssignee) error
+	RemoveTaskAssignee(taskID uuid.UUID, userID uuid.UUID) error
+	DemotePrimaryAssignee(taskID uuid.UUID) error
+	ReplacePrimaryAssignee(taskID uuid.UUID, previous, next *uuid.UUID, keepPrevious bool) error
+	IsTaskAssignee(taskID uuid.UUID, userID uuid.UUID) (bool, error)
+
+	GetTaskWatchers(taskID uuid.UUID) ([]taskmodel.TaskWatcher, error)
+	AddTaskWatcher(watcher *taskmodel.TaskWatcher) error
+	RemoveTaskWatcher(taskID uuid.UUID, userID uuid.UUID) error
+
+	GetChecklistItems(taskID uuid.UUID) ([]taskmodel.ChecklistItem, error)
+	GetChecklistItem(taskID uuid.UUID, itemID uuid.UUID) (*taskmodel.ChecklistItem, error)
+	CreateChecklistItem(item *taskmodel.ChecklistItem) error
+	UpdateChecklistItem(item *taskmodel.ChecklistItem) error
+	DeleteChecklistItem(itemID uuid.UUID) error
+	GetLastChecklistPosition(taskID uuid.UUID, excludeID uuid.UUID) (string, error)
+	GetAdjacentChecklistPosition(taskID uuid.UUID, position string, after bool, excludeID uuid.UUID) (string, error)
+
+	// dipakai saat memindahkan task ke project lain
+	DetachNonMembers(taskID uuid.UUID, projectID uuid.UUID) error
+
+	// dipakai saat clone task
+	CopyTaskComments(sourceID uuid.UUID, targetID uuid.UUID) error
+	CopyChecklistItems(sourceID uuid.UUID, targetID uuid.UUID, keepAssignees bool) error
+	GetTaskAttachments(taskID uuid.UUID) ([]attachmentmodel.Attachment, error)
+	CreateAttachment(attachment *attachmentmodel.Attachment) error
+
+	// review pending selalu sinkron dengan status in-review, lihat TaskReview
+	CreateTaskReview(review *taskmodel.TaskReview) error
+	GetPendingReview(taskID uuid.UUID) (*taskmodel.TaskReview, error)
+	ResolvePendingReview(taskID uuid.UUID, decision string, reviewerID *uuid.UUID, comment string) error
+	GetTaskReviews(taskID uuid.UUID) ([]taskmodel.TaskReview, error)
+
+	// saved view hanya dikembalikan jika terlihat oleh userID: miliknya sendiri atau shared di project tempat userID bergabung
+	GetSavedViews(userID uuid.UUID, projectID *uuid.UUID) ([]taskmodel.SavedView, error)
+	GetSavedView(viewID uuid.UUID, userID uuid.UUID) (*taskmodel.SavedView, error)
+	CreateSavedView(view *taskmodel.SavedView) error
+	// UpdateSavedView juga melepas pin user lain jika view tidak lagi shared
+	UpdateSavedView(view *taskmodel.SavedView) error
+	DeleteSavedView(viewID uuid.UUID) error
+	GetSavedViewPins(userID uuid.UUID) ([]taskmodel.SavedViewPin, error)
+	// PinSavedView mengganti view default user di dashboard yang sama
+	PinSavedView(pin *taskmodel.SavedViewPin) error
+	// UnpinSavedView dengan dashboard kosong melepas view dari semua dashboard user
+	UnpinSavedView(userID uuid.UUID, viewID uuid.UUID, dashboard string) error
+
+	CreateTaskHistory(entry *taskmodel.TaskHistory) error
+	GetTaskHistory(taskID uuid.UUID) ([]taskmodel.TaskHistory, error)
+
+	// Transaction menjalankan fn dengan repository yang memakai satu transaksi database
+	Transaction(fn func(repo TaskRepository) error) error
 }
 
 type taskRepository struct {
@@ -34,22 +108,84 @@ func NewTaskRepository(db *gorm.DB) TaskRepository {
 	}
 }
 
+// ProjectArchivedColumn mengisi Task.ProjectArchived, dipakai juga repository lain yang memuat task untuk dicek IsReadOnly
+const ProjectArchivedColumn = `EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL) AS project_archived`
+
+// withTaskAggregates menambahkan kolom hitungan yang tidak disimpan di tabel tasks
+func withTaskAggregates(query *gorm.DB) *gorm.DB {
+	return query.Select(`tasks.*, ` + ProjectArchivedColumn + `,
+		(SELECT COALESCE(SUM(tl.duration_minutes), 0) FROM time_logs tl WHERE tl.task_id = tasks.id) AS time_spent_minutes,
+		(SELECT COUNT(*) FROM task_checklist_items ci WHERE ci.task_id = tasks.id) AS checklist_total,
+		(SELECT COUNT(*) FROM task_checklist_items ci WHERE ci.task_id = tasks.id AND ci.is_checked) AS checklist_done`)
+}
+
+// CreateTask menaruh task baru di paling bawah board jika posisi belum diisi
 func (r *taskRepository) CreateTask(task *taskmodel.Task) error {
+	if task.Position == "" {
+		last, err := r.GetLastTaskPosition(task.ProjectID, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if task.Position, err = rank.Between(last, ""); err != nil {
+			return err
+		}
+	}
 	return r.db.Create(task).Error
 }
 
-func (r *taskRepository) GetTasksByProjectID(projectID uuid.UUID) ([]taskmodel.Task, error) {
-	var tasks []taskmodel.Task
-	err := r.db.Where("project_id = ?", projectID).
+func (r *taskRepository) GetLastTaskPosition(projectID uuid.UUID, excludeID uuid.UUID) (string, error) {
+	var positions []string
+	err := r.db.Unscoped().Model(&taskmodel.Task{}).
+		Where("project_id = ? AND id <> ?", projectID, excludeID).
+		Order("position DESC").
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+// GetAdjacentTaskPosition mencari posisi terdekat sesudah (after = true) atau sebelum position di seluruh project
+func (r *taskRepository) GetAdjacentTaskPosition(projectID uuid.UUID, position string, after bool, excludeID uuid.UUID) (string, error) {
+	condition, order := "position > ?", "position ASC"
+	if !after {
+		condition, order = "position < ?", "position DESC"
+	}
+
+	var positions []string
+	err := r.db.Unscoped().Model(&taskmodel.Task{}).
+		Where("project_id = ? AND id <> ?", projectID, excludeID).
+		Where(condition, position).
+		Order(order).
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+func (r *taskRepository) GetTasksByProjectID(projectID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+	if filter.Sort == "" {
+		filter.Sort = BoardTaskSort
+	}
+	query := withTaskAggregates(r.db.Model(&taskmodel.Task{})).
+		Where("tasks.project_id = ?", projectID).
 		Preload("Assignee").
-		Find(&tasks).Error
-	return tasks, err
+		Preload("Assignees", orderAssignees).
+		Preload("Assignees.User")
+	return findTaskPage(query, filter)
 }
 
 func (r *taskRepository) GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error) {
 	var task taskmodel.Task
-	err := r.db.Where("id = ?", taskID).
+	err := withTaskAggregates(r.db.Model(&taskmodel.Task{})).
+		Where("tasks.id = ?", taskID).
 		Preload("Assignee").
+		Preload("Assignees", orderAssignees).
+		Preload("Assignees.User").
+		Preload("Watchers.User").
 		First(&task).Error
 	if err != nil {
 		return nil, err
@@ -57,29 +193,74 @@ func (r *taskRepository) GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error)
 	return &task, nil
 }
 
-func (r *taskRepository) UpdateTask(task *taskmodel.Task) error {
-	return r.db.Save(task).Error
+func (r *taskRepository) GetProjectTasksByIDs(projectID uuid.UUID, taskIDs []uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := withTaskAggregates(r.db.Model(&taskmodel.Task{})).
+		Where("tasks.project_id = ? AND tasks.id IN ?", projectID, taskIDs).
+		Preload("Watchers.User").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) GetCommentCounts(taskIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := map[uuid.UUID]int{}
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TaskID uuid.UUID
+		Total  int
+	}
+	err := r.db.Table("comments").
+		Select("task_id, COUNT(*) AS total").
+		Where("task_id IN ? AND deleted_at IS NULL", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TaskID] = row.Total
+	}
+	return counts, nil
 }
 
-func (r *taskRepository) DeleteTask(taskID uuid.UUID) error {
-	return r.db.Delete(&taskmodel.Task{}, "id = ?", taskID).Error
+// UpdateTask menyimpan task hanya jika versi di database masih sama, lalu menaikkan versinya
+func (r *taskRepository) UpdateTask(task *taskmodel.Task) error {
+	currentVersion := task.Version
+	task.Version = currentVersion + 1
+
+	result := r.db.Model(task).
+		Where("version = ?", currentVersion).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(task)
+	if result.Error != nil {
+		task.Version = currentVersion
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		task.Version = currentVersion
+		return concurrency.ErrPreconditionFailed
+	}
+	return nil
 }
 
-func (r *taskRepository) GetTasksByAssigneeID(assigneeID uuid.UUID) ([]taskmodel.Task, error) {
-	var tasks []taskmodel.Task
-	err := r.db.Where("assignee_id = ?", assigneeID).
-		Preload("Project").
-		Preload("Assignee").
-		Find(&tasks).Error
-	return tasks, err
+func (r *taskRepository) DeleteTask(taskID uuid.UUID, deletedBy *uuid.UUID) error {
+	return r.db.Model(&taskmodel.Task{}).
+		Where("id = ?", taskID).
+		Updates(map[string]interface{}{"deleted_at": time.Now(), "deleted_by": deletedBy}).Error
 }
 
-func (r *taskRepository) GetTasksByStatus(projectID uuid.UUID, status string) ([]taskmodel.Task, error) {
-	var tasks []taskmodel.Task
-	err := r.db.Where("project_id = ? AND status = ?", projectID, status).
+func (r *taskRepository) GetTasksByAssigneeID(assigneeID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+	query := withTaskAggregates(r.db.Model(&taskmodel.Task{})).
+		Where(assignedToUser, assigneeID).
+		Preload("Project").
 		Preload("Assignee").
-		Find(&tasks).Error
-	return tasks, err
+		Preload("Assignees", orderAssignees).
+		Preload("Assignees.User")
+	return findTaskPage(query, filter)
 }
 
 func (r *taskRepository) GetUserByID(userID uuid.UUID) (*usermodels.User, error) {
@@ -125,11 +306,63 @@ func (r *taskRepository) IsProjectMember(projectID uuid.UUID, userID uuid.UUID)
 	return count > 0, nil
 }
 
+func (r *taskRepository) CanViewProject(projectID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var allowed bool
+	err := r.db.Raw(`SELECT EXISTS (
+			SELECT 1 FROM projects p
+			WHERE p.id = ? AND (p.manager_id = ? OR EXISTS (
+				SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = ?)))`,
+		projectID, userID, userID).Scan(&allowed).Error
+	return allowed, err
+}
+
+func (r *taskRepository) GetSprintState(sprintID uuid.UUID) (uuid.UUID, string, error) {
+	var sprint struct {
+		ProjectID uuid.UUID
+		Status    string
+	}
+	err := r.db.Table("sprints").
+		Select("project_id, status").
+		Where("id = ?", sprintID).
+		Take(&sprint).Error
+	return sprint.ProjectID, sprint.Status, err
+}
+
+func (r *taskRepository) GetMilestoneProjectID(milestoneID uuid.UUID) (uuid.UUID, error) {
+	var milestone struct {
+		ProjectID uuid.UUID
+	}
+	err := r.db.Table("milestones").
+		Select("project_id").
+		Where("id = ?", milestoneID).
+		Take(&milestone).Error
+	return milestone.ProjectID, err
+}
+
 func (r *taskRepository) GettaskbyuserID(userID uuid.UUID) ([]taskmodel.Task, error) {
 	var tasks []taskmodel.Task
-	err := r.db.Where("assignee_id = ?", userID).
+	err := r.db.Where(assignedToUser, userID).
 		Preload("Project").
 		Preload("Assignee").
 		Find(&tasks).Error
 	return tasks, err
 }
+
+func (r *taskRepository) CreateTaskHistory(entry *taskmodel.TaskHistory) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *taskRepository) GetTaskHistory(taskID uuid.UUID) ([]taskmodel.TaskHistory, error) {
+	history := []taskmodel.TaskHistory{}
+	err := r.db.Where("task_id = ?", taskID).
+		Preload("Actor").
+		Order("created_at DESC").
+		Find(&history).Error
+	return history, err
+}
+
+func (r *taskRepository) Transaction(fn func(repo TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&taskRepository{db: tx})
+	})
+}
@@ -0,0 +1,44 @@
+package taskrepository
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (r *taskRepository) CreateTaskReview(review *taskmodel.TaskReview) error {
+	return r.db.Create(review).Error
+}
+
+func (r *taskRepository) GetPendingReview(taskID uuid.UUID) (*taskmodel.TaskReview, error) {
+	var review taskmodel.TaskReview
+	err := r.db.Where("task_id = ? AND decision = ?", taskID, taskmodel.ReviewPending).
+		First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ResolvePendingReview menutup review pending task dengan keputusan reviewer, tidak error jika tidak ada review pending
+func (r *taskRepository) ResolvePendingReview(taskID uuid.UUID, decision string, reviewerID *uuid.UUID, comment string) error {
+	return r.db.Model(&taskmodel.TaskReview{}).
+		Where("task_id = ? AND decision = ?", taskID, taskmodel.ReviewPending).
+		Updates(map[string]interface{}{
+			"decision":    decision,
+			"reviewer_id": reviewerID,
+			"comment":     comment,
+			"reviewed_at": time.Now(),
+		}).Error
+}
+
+func (r *taskRepository) GetTaskReviews(taskID uuid.UUID) ([]taskmodel.TaskReview, error) {
+	reviews := []taskmodel.TaskReview{}
+	err := r.db.Where("task_id = ?", taskID).
+		Preload("Submitter").
+		Preload("Reviewer").
+		Order("submitted_at DESC").
+		Find(&reviews).Error
+	return reviews, err
+}
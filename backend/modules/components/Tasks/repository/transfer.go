@@ -0,0 +1,58 @@
+package taskrepository
+
+import (
+	"database/sql"
+	"fmt"
+	attachmentmodel "gintugas/modules/components/attachments/models"
+
+	"github.com/google/uuid"
+)
+
+// CopyTaskComments menyalin semua comment task beserta penulis dan waktunya
+func (r *taskRepository) CopyTaskComments(sourceID uuid.UUID, targetID uuid.UUID) error {
+	return r.db.Exec(`
+		INSERT INTO comments (task_id, user_id, content, created_at, updated_at)
+		SELECT ?, user_id, content, created_at, updated_at
+		FROM comments
+		WHERE task_id = ? AND deleted_at IS NULL`, targetID, sourceID).Error
+}
+
+// notMemberOfTarget cocok untuk user (kolom %s) yang bukan manager maupun member project @project
+const notMemberOfTarget = `NOT EXISTS (SELECT 1 FROM projects tp WHERE tp.id = @project
+	AND (tp.manager_id = %[1]s OR EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = tp.id AND pm.user_id = %[1]s)))`
+
+// DetachNonMembers menghapus watcher dan assignee item checklist task yang bukan member project, dipakai saat task
+// dipindah ke project lain supaya user di luar project tujuan tidak lagi menerima kabar tentang task tersebut
+func (r *taskRepository) DetachNonMembers(taskID uuid.UUID, projectID uuid.UUID) error {
+	args := []interface{}{sql.Named("task", taskID), sql.Named("project", projectID)}
+
+	err := r.db.Exec(`DELETE FROM task_watchers WHERE task_id = @task AND `+
+		fmt.Sprintf(notMemberOfTarget, "task_watchers.user_id"), args...).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Exec(`UPDATE task_checklist_items SET assignee_id = NULL WHERE task_id = @task AND assignee_id IS NOT NULL AND `+
+		fmt.Sprintf(notMemberOfTarget, "task_checklist_items.assignee_id"), args...).Error
+}
+
+// CopyChecklistItems menyalin item checklist dalam keadaan belum dicentang, assignee item hanya ikut jika keepAssignees
+func (r *taskRepository) CopyChecklistItems(sourceID uuid.UUID, targetID uuid.UUID, keepAssignees bool) error {
+	return r.db.Exec(`
+		INSERT INTO task_checklist_items (task_id, content, position, assignee_id, created_by)
+		SELECT ?, content, position, CASE WHEN ? THEN assignee_id END, created_by
+		FROM task_checklist_items
+		WHERE task_id = ?`, targetID, keepAssignees, sourceID).Error
+}
+
+func (r *taskRepository) GetTaskAttachments(taskID uuid.UUID) ([]attachmentmodel.Attachment, error) {
+	var attachments []attachmentmodel.Attachment
+	err := r.db.Where("task_id = ?", taskID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *taskRepository) CreateAttachment(attachment *attachmentmodel.Attachment) error {
+	return r.db.Omit("Task", "Uploader").Create(attachment).Error
+}
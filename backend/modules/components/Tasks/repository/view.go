@@ -0,0 +1,93 @@
+package taskrepository
+
+import (
+	"database/sql"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// memberOfProject mencocokkan project yang dipegang atau diikuti user, project di trash tidak ikut
+const memberOfProject = `EXISTS (SELECT 1 FROM projects mp WHERE mp.id = %s AND mp.deleted_at IS NULL
+	AND (mp.manager_id = @user OR EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = mp.id AND pm.user_id = @user)))`
+
+func (r *taskRepository) GetTasksByMemberID(userID uuid.UUID, filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+	query := withTaskAggregates(r.db.Model(&taskmodel.Task{})).
+		Where(fmt.Sprintf(memberOfProject, "tasks.project_id"), sql.Named("user", userID)).
+		Preload("Project").
+		Preload("Assignee").
+		Preload("Assignees", orderAssignees).
+		Preload("Assignees.User")
+	return findTaskPage(query, filter)
+}
+
+func (r *taskRepository) visibleViews(userID uuid.UUID) *gorm.DB {
+	return r.db.Model(&taskmodel.SavedView{}).
+		Where("saved_views.owner_id = @user OR (saved_views.visibility = 'shared' AND "+
+			fmt.Sprintf(memberOfProject, "saved_views.project_id")+")", sql.Named("user", userID))
+}
+
+func (r *taskRepository) GetSavedViews(userID uuid.UUID, projectID *uuid.UUID) ([]taskmodel.SavedView, error) {
+	views := []taskmodel.SavedView{}
+	query := r.visibleViews(userID)
+	if projectID != nil {
+		query = query.Where("saved_views.project_id = ?", *projectID)
+	}
+	err := query.Order("saved_views.name ASC, saved_views.id ASC").Find(&views).Error
+	return views, err
+}
+
+func (r *taskRepository) GetSavedView(viewID uuid.UUID, userID uuid.UUID) (*taskmodel.SavedView, error) {
+	var view taskmodel.SavedView
+	err := r.visibleViews(userID).Where("saved_views.id = ?", viewID).First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *taskRepository) CreateSavedView(view *taskmodel.SavedView) error {
+	return r.db.Create(view).Error
+}
+
+func (r *taskRepository) UpdateSavedView(view *taskmodel.SavedView) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(view).
+			Select("*").
+			Omit("id", "owner_id", "created_at").
+			Updates(view).Error
+		if err != nil || view.Visibility == taskmodel.ViewShared {
+			return err
+		}
+		return tx.Where("view_id = ? AND user_id <> ?", view.ID, view.OwnerID).
+			Delete(&taskmodel.SavedViewPin{}).Error
+	})
+}
+
+func (r *taskRepository) DeleteSavedView(viewID uuid.UUID) error {
+	return r.db.Delete(&taskmodel.SavedView{}, "id = ?", viewID).Error
+}
+
+func (r *taskRepository) GetSavedViewPins(userID uuid.UUID) ([]taskmodel.SavedViewPin, error) {
+	pins := []taskmodel.SavedViewPin{}
+	err := r.db.Where("user_id = ?", userID).Find(&pins).Error
+	return pins, err
+}
+
+func (r *taskRepository) PinSavedView(pin *taskmodel.SavedViewPin) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "dashboard"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"view_id": pin.ViewID, "created_at": gorm.Expr("NOW()")}),
+	}).Create(pin).Error
+}
+
+func (r *taskRepository) UnpinSavedView(userID uuid.UUID, viewID uuid.UUID, dashboard string) error {
+	query := r.db.Where("user_id = ? AND view_id = ?", userID, viewID)
+	if dashboard != "" {
+		query = query.Where("dashboard = ?", dashboard)
+	}
+	return query.Delete(&taskmodel.SavedViewPin{}).Error
+}
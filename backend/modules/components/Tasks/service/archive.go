@@ -0,0 +1,84 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ensureWritable menolak perubahan pada task yang diarsipkan atau berada di project yang diarsipkan
+func ensureWritable(task *taskmodel.Task) error {
+	if task.IsReadOnly() {
+		return archive.ErrReadOnly
+	}
+	return nil
+}
+
+// ensureProjectWritable menolak task baru atau task pindahan ke project yang diarsipkan
+func (s *taskService) ensureProjectWritable(projectID uuid.UUID) error {
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	if project.ArchivedAt != nil {
+		return archive.ErrReadOnly
+	}
+	return nil
+}
+
+// ArchiveTask menjadikan task read-only dan menyembunyikannya dari listing default
+func (s *taskService) ArchiveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	return s.setTaskArchived(ctx, true)
+}
+
+func (s *taskService) UnarchiveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	return s.setTaskArchived(ctx, false)
+}
+
+func (s *taskService) setTaskArchived(ctx *gin.Context, archived bool) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadTaskAsManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// arsip task di project yang diarsipkan diatur lewat project-nya
+	if task.ProjectArchived {
+		return nil, archive.ErrReadOnly
+	}
+	if archived == (task.ArchivedAt != nil) {
+		if archived {
+			return nil, errors.New("task sudah diarsipkan")
+		}
+		return nil, errors.New("task tidak sedang diarsipkan")
+	}
+
+	action := taskmodel.HistoryUnarchived
+	task.ArchivedAt = nil
+	task.ArchivedBy = nil
+	if archived {
+		now := time.Now()
+		action = taskmodel.HistoryArchived
+		task.ArchivedAt = &now
+		task.ArchivedBy = actorID(ctx)
+	}
+	task.UpdatedAt = time.Now()
+
+	err = s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		if err := repo.UpdateTask(task); err != nil {
+			return err
+		}
+		return repo.CreateTaskHistory(newHistory(requestActor(ctx), action, task, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(requestActor(ctx), action, task, nil)
+	return s.reloadTask(task)
+}
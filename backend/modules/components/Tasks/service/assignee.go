@@ -0,0 +1,310 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// loadManagedTask mengambil task dari path lalu memastikan user adalah manager project, If-Match masih cocok dan task tidak diarsipkan
+func (s *taskService) loadManagedTask(ctx *gin.Context) (*taskmodel.Task, error) {
+	task, err := s.loadTaskAsManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureWritable(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// loadTaskAsManager sama dengan loadManagedTask tanpa menolak task yang diarsipkan
+func (s *taskService) loadTaskAsManager(ctx *gin.Context) (*taskmodel.Task, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectManager(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, task.Version); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// reloadTask mengambil ulang task supaya daftar assignee dan watcher di response sudah terbaru
+func (s *taskService) reloadTask(task *taskmodel.Task) (*taskmodel.TaskResponse, error) {
+	reloaded, err := s.taskRepo.GetTaskByID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToResponse(reloaded), nil
+}
+
+func (s *taskService) GetTaskAssignees(ctx *gin.Context) ([]taskmodel.TaskAssignee, error) {
+	task, err := s.loadReadableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetTaskAssignees(task.ID)
+}
+
+// AddTaskAssignee menambahkan user ke task. Task yang belum punya assignee utama otomatis menjadikan user ini assignee utama
+func (s *taskService) AddTaskAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.AssigneeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectMember(task.ProjectID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if isAssignee {
+		return nil, errors.New("user sudah menjadi assignee task ini")
+	}
+
+	makePrimary := req.IsPrimary || task.AssigneeID == nil
+	before := *task
+	if makePrimary {
+		task.AssigneeID = &req.UserID
+	}
+	task.UpdatedAt = time.Now()
+
+	changes := []taskmodel.FieldChange{{Field: "assignees", Old: nil, New: req.UserID.String()}}
+	changes = append(changes, taskmodel.DiffTask(&before, task)...)
+
+	err = s.commitTaskChange(requestActor(ctx), task, changes, func(repo taskrepository.TaskRepository) error {
+		if makePrimary {
+			return repo.ReplacePrimaryAssignee(task.ID, before.AssigneeID, task.AssigneeID, true)
+		}
+		return repo.UpsertTaskAssignee(&taskmodel.TaskAssignee{TaskID: task.ID, UserID: req.UserID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := *task
+	assigned.AssigneeID = &req.UserID
+	if err := s.notifyAssignee(&assigned); err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task)
+}
+
+// RemoveTaskAssignee melepas user dari task. Jika yang dilepas assignee utama, task menjadi tanpa assignee utama
+func (s *taskService) RemoveTaskAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format user ID")
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAssignee {
+		return nil, errors.New("user bukan assignee task ini")
+	}
+
+	before := *task
+	if task.AssigneeID != nil && *task.AssigneeID == userUUID {
+		task.AssigneeID = nil
+	}
+	task.UpdatedAt = time.Now()
+
+	changes := []taskmodel.FieldChange{{Field: "assignees", Old: userUUID.String(), New: nil}}
+	changes = append(changes, taskmodel.DiffTask(&before, task)...)
+
+	err = s.commitTaskChange(requestActor(ctx), task, changes, func(repo taskrepository.TaskRepository) error {
+		return repo.RemoveTaskAssignee(task.ID, userUUID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task)
+}
+
+// SetPrimaryAssignee menjadikan salah satu assignee sebagai assignee utama, assignee utama lama tetap ikut mengerjakan
+func (s *taskService) SetPrimaryAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format user ID")
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAssignee {
+		return nil, errors.New("user harus menjadi assignee task ini terlebih dahulu")
+	}
+
+	before := *task
+	task.AssigneeID = &userUUID
+	task.UpdatedAt = time.Now()
+
+	err = s.commitTaskChange(requestActor(ctx), task, taskmodel.DiffTask(&before, task), func(repo taskrepository.TaskRepository) error {
+		return repo.ReplacePrimaryAssignee(task.ID, before.AssigneeID, task.AssigneeID, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task)
+}
+
+func (s *taskService) GetTaskWatchers(ctx *gin.Context) ([]taskmodel.TaskWatcher, error) {
+	task, err := s.loadReadableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetTaskWatchers(task.ID)
+}
+
+// WatchTask menambahkan watcher. Tanpa user_id, user yang login yang menjadi watcher;
+// menambahkan user lain hanya boleh dilakukan manager project
+func (s *taskService) WatchTask(ctx *gin.Context) ([]taskmodel.TaskWatcher, error) {
+	task, watcherID, err := s.resolveWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.taskRepo.IsProjectMember(task.ProjectID, watcherID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa member project: %v", err)
+	}
+	if !isMember {
+		return nil, errors.New("watcher harus menjadi member dari project ini")
+	}
+
+	err = s.taskRepo.AddTaskWatcher(&taskmodel.TaskWatcher{TaskID: task.ID, UserID: watcherID})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetTaskWatchers(task.ID)
+}
+
+// UnwatchTask menghapus watcher, user biasa hanya bisa menghapus dirinya sendiri
+func (s *taskService) UnwatchTask(ctx *gin.Context) error {
+	task, watcherID, err := s.resolveWatcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.taskRepo.RemoveTaskWatcher(task.ID, watcherID)
+}
+
+// resolveWatcher membaca task dan user watcher dari path/body, lalu mengecek hak akses jika watcher bukan user yang login
+func (s *taskService) resolveWatcher(ctx *gin.Context) (*taskmodel.Task, uuid.UUID, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, uuid.Nil, errors.New("Gagal format task ID")
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return nil, uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	watcherID := *currentUser
+	if param := ctx.Param("user_id"); param != "" {
+		watcherID, err = uuid.Parse(param)
+		if err != nil {
+			return nil, uuid.Nil, errors.New("Gagal format user ID")
+		}
+	} else {
+		// body boleh kosong
+		var req taskmodel.WatcherRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			return nil, uuid.Nil, err
+		}
+		if req.UserID != nil {
+			watcherID = *req.UserID
+		}
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	if watcherID != *currentUser {
+		if err := s.validateProjectManager(ctx, task.ProjectID); err != nil {
+			return nil, uuid.Nil, err
+		}
+	}
+
+	return task, watcherID, nil
+}
+
+// notifyWatchers mengirim email ke semua watcher kecuali user yang melakukan perubahan
+func (s *taskService) notifyWatchers(actor changeActor, task *taskmodel.Task, changes []taskmodel.FieldChange) {
+	watchers, err := s.taskRepo.GetTaskWatchers(task.ID)
+	if err != nil || len(watchers) == 0 {
+		return
+	}
+
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil detail projek: %v\n", err)
+		return
+	}
+
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+
+	for _, watcher := range watchers {
+		if watcher.User == nil || (actor.ID != nil && watcher.UserID == *actor.ID) {
+			continue
+		}
+		err := s.mailService.SendTaskUpdateNotification(watcher.User.Email, task.Title, project.Nama, fields)
+		if err != nil {
+			fmt.Printf("Gagal untuk mengirim notif ke watcher: %v\n", err)
+		}
+	}
+}
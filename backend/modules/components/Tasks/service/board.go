@@ -0,0 +1,96 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	rank "gintugas/modules/components/Rank"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MoveTask memindahkan task di board: status dan posisi baru disimpan dalam satu update
+func (s *taskService) MoveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.MoveTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	status := task.Status
+	if req.Status != "" {
+		if !validTaskStatuses[req.Status] {
+			return nil, fmt.Errorf("status tidak valid: %s", req.Status)
+		}
+		status = req.Status
+	}
+
+	previous, err := s.loadNeighbour(task, status, req.PreviousID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.loadNeighbour(task, status, req.NextID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && next != nil && previous.Position >= next.Position {
+		return nil, errors.New("previous_id harus berada di atas next_id")
+	}
+
+	// batas atas dan bawah diambil dari tetangga terdekat di seluruh project supaya key baru tidak bentrok dengan task di kolom lain
+	var lower, upper string
+	switch {
+	case previous != nil:
+		lower = previous.Position
+		upper, err = s.taskRepo.GetAdjacentTaskPosition(task.ProjectID, lower, true, task.ID)
+	case next != nil:
+		upper = next.Position
+		lower, err = s.taskRepo.GetAdjacentTaskPosition(task.ProjectID, upper, false, task.ID)
+	default:
+		lower, err = s.taskRepo.GetLastTaskPosition(task.ProjectID, task.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := rank.Between(lower, upper)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *task
+	task.Status = status
+	task.Position = position
+	task.UpdatedAt = time.Now()
+
+	if err := s.saveTaskUpdate(requestActor(ctx), &before, task); err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task)
+}
+
+// loadNeighbour memastikan task acuan ada di project dan kolom status yang sama
+func (s *taskService) loadNeighbour(task *taskmodel.Task, status string, id *uuid.UUID) (*taskmodel.Task, error) {
+	if id == nil {
+		return nil, nil
+	}
+	if *id == task.ID {
+		return nil, errors.New("task acuan tidak boleh task yang dipindah")
+	}
+
+	neighbour, err := s.taskRepo.GetTaskByID(*id)
+	if err != nil {
+		return nil, fmt.Errorf("task acuan tidak ditemukan: %v", err)
+	}
+	if neighbour.ProjectID != task.ProjectID || neighbour.Status != status {
+		return nil, errors.New("task acuan harus berada di project dan kolom status yang sama")
+	}
+	return neighbour, nil
+}
@@ -0,0 +1,271 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// field yang boleh diubah lewat bulk update
+var bulkTaskFields = []string{"status", "assignee_id", "due_date"}
+
+// bulkItem menyimpan keadaan task sebelum dan sesudah perubahan selama bulk operation
+type bulkItem struct {
+	before  taskmodel.Task
+	task    *taskmodel.Task
+	changes []taskmodel.FieldChange
+}
+
+// BulkUpdateTasks menerapkan status, assignee_id, due_date atau delete ke banyak task dalam satu transaksi.
+// Semua task divalidasi dulu, jika ada satu yang gagal tidak ada perubahan yang disimpan.
+// Notifikasi dikirim sebagai satu email ringkasan per penerima, bukan satu email per task
+func (s *taskService) BulkUpdateTasks(ctx *gin.Context) (*taskmodel.BulkTaskResult, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format project ID")
+	}
+
+	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.BulkTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	switch req.Action {
+	case taskmodel.BulkActionUpdate:
+		if len(req.Changes) == 0 {
+			return nil, errors.New("changes wajib diisi untuk action update")
+		}
+		if err := req.Changes.Allow(bulkTaskFields...); err != nil {
+			return nil, err
+		}
+	case taskmodel.BulkActionDelete:
+		if len(req.Changes) > 0 {
+			return nil, errors.New("changes tidak boleh diisi untuk action delete")
+		}
+	}
+
+	taskIDs := uniqueTaskIDs(req.TaskIDs)
+	tasks, err := s.taskRepo.GetProjectTasksByIDs(projectUUID, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*taskmodel.Task, len(tasks))
+	for i := range tasks {
+		found[tasks[i].ID] = &tasks[i]
+	}
+
+	// validasi semua task sebelum ada yang disimpan
+	result := &taskmodel.BulkTaskResult{Results: make([]taskmodel.BulkTaskItemResult, len(taskIDs))}
+	items := make([]*bulkItem, len(taskIDs))
+	failed := false
+	now := time.Now()
+	for i, id := range taskIDs {
+		result.Results[i].TaskID = id
+
+		item, err := s.prepareBulkItem(req, found[id], now)
+		if err != nil {
+			result.Results[i].Result = taskmodel.BulkFailed
+			result.Results[i].Error = err.Error()
+			failed = true
+			continue
+		}
+		items[i] = item
+	}
+
+	if failed {
+		for i := range result.Results {
+			if result.Results[i].Result != taskmodel.BulkFailed {
+				result.Results[i].Result = taskmodel.BulkSkipped
+			}
+		}
+		return result, nil
+	}
+
+	actor := requestActor(ctx)
+	err = s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		for _, item := range items {
+			if req.Action == taskmodel.BulkActionDelete {
+				if err := repo.DeleteTask(item.task.ID, actor.ID); err != nil {
+					return err
+				}
+				if err := repo.CreateTaskHistory(newHistory(actor, taskmodel.HistoryDeleted, item.task, nil)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if len(item.changes) == 0 {
+				continue
+			}
+			if err := repo.ReplacePrimaryAssignee(item.task.ID, item.before.AssigneeID, item.task.AssigneeID, false); err != nil {
+				return err
+			}
+			if err := syncReview(actor, repo, item.task, item.changes); err != nil {
+				return err
+			}
+			if err := repo.UpdateTask(item.task); err != nil {
+				return fmt.Errorf("task %s: %w", item.task.ID, err)
+			}
+			if err := repo.CreateTaskHistory(newHistory(actor, taskmodel.HistoryUpdated, item.task, item.changes)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Applied = true
+	for i, item := range items {
+		switch {
+		case req.Action == taskmodel.BulkActionDelete:
+			result.Results[i].Result = taskmodel.BulkDeleted
+			s.publish(actor, taskmodel.HistoryDeleted, item.task, nil)
+		case len(item.changes) == 0:
+			result.Results[i].Result = taskmodel.BulkUnchanged
+			result.Results[i].Version = item.task.Version
+		default:
+			result.Results[i].Result = taskmodel.BulkUpdated
+			result.Results[i].Version = item.task.Version
+			s.publish(actor, taskmodel.HistoryUpdated, item.task, item.changes)
+		}
+	}
+
+	s.sendBulkDigest(ctx, projectUUID, req.Action, items)
+
+	return result, nil
+}
+
+// prepareBulkItem memvalidasi satu task dan menerapkan perubahan di memori tanpa menyimpannya
+func (s *taskService) prepareBulkItem(req taskmodel.BulkTaskRequest, task *taskmodel.Task, now time.Time) (*bulkItem, error) {
+	if task == nil {
+		return nil, errors.New("task tidak ditemukan di project ini")
+	}
+	if err := ensureWritable(task); err != nil {
+		return nil, err
+	}
+
+	item := &bulkItem{before: *task, task: task}
+	if req.Action == taskmodel.BulkActionDelete {
+		return item, nil
+	}
+
+	if err := s.applyTaskPatch(task, req.Changes); err != nil {
+		return nil, err
+	}
+
+	item.changes = taskmodel.DiffTask(&item.before, task)
+	if len(item.changes) == 0 {
+		return item, nil
+	}
+
+	// sama seperti saveTaskUpdate, occurrence yang diedit sendiri (selain status) keluar dari series
+	if task.RecurrenceID != nil {
+		for _, change := range item.changes {
+			if change.Field != "status" {
+				task.RecurrenceException = true
+			}
+		}
+	}
+	task.UpdatedAt = now
+
+	return item, nil
+}
+
+// sendBulkDigest mengumpulkan perubahan per penerima (assignee baru dan watcher selain actor) lalu mengirim satu email per penerima
+func (s *taskService) sendBulkDigest(ctx *gin.Context, projectID uuid.UUID, action string, items []*bulkItem) {
+	actor := actorID(ctx)
+	emails := map[uuid.UUID]string{}
+	lines := map[uuid.UUID][]string{}
+	var order []uuid.UUID
+
+	add := func(userID uuid.UUID, email, line string) {
+		if actor != nil && userID == *actor {
+			return
+		}
+		if _, ok := emails[userID]; !ok {
+			emails[userID] = email
+			order = append(order, userID)
+		}
+		lines[userID] = append(lines[userID], line)
+	}
+
+	for _, item := range items {
+		task := item.task
+		if action == taskmodel.BulkActionDelete {
+			for _, watcher := range task.Watchers {
+				if watcher.User != nil {
+					add(watcher.UserID, watcher.User.Email, fmt.Sprintf("'%s' was deleted", task.Title))
+				}
+			}
+			continue
+		}
+
+		if len(item.changes) == 0 {
+			continue
+		}
+
+		fields := make([]string, 0, len(item.changes))
+		for _, change := range item.changes {
+			fields = append(fields, change.Field)
+		}
+		line := fmt.Sprintf("'%s' (%s)", task.Title, strings.Join(fields, ", "))
+
+		notified := map[uuid.UUID]bool{}
+		if task.AssigneeID != nil && (item.before.AssigneeID == nil || *item.before.AssigneeID != *task.AssigneeID) {
+			assignee, err := s.taskRepo.GetUserByID(*task.AssigneeID)
+			if err != nil {
+				fmt.Printf("Gagal mengambil detail assignee: %v\n", err)
+			} else {
+				add(assignee.ID, assignee.Email, fmt.Sprintf("'%s' was assigned to you", task.Title))
+				notified[assignee.ID] = true
+			}
+		}
+
+		for _, watcher := range task.Watchers {
+			if watcher.User != nil && !notified[watcher.UserID] {
+				add(watcher.UserID, watcher.User.Email, line)
+			}
+		}
+	}
+
+	if len(order) == 0 {
+		return
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil detail projek: %v\n", err)
+		return
+	}
+
+	for _, userID := range order {
+		if err := s.mailService.SendTaskDigest(emails[userID], project.Nama, lines[userID]); err != nil {
+			fmt.Printf("Gagal untuk mengirim notif ringkasan: %v\n", err)
+		}
+	}
+}
+
+// uniqueTaskIDs membuang id ganda dengan tetap menjaga urutan request
+func uniqueTaskIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
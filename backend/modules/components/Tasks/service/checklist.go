@@ -0,0 +1,329 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	patch "gintugas/modules/components/Patch"
+	rank "gintugas/modules/components/Rank"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// loadWorkableTask mengambil task dari path, checklist hanya boleh diubah manager project dan assignee task selama task tidak diarsipkan
+func (s *taskService) loadWorkableTask(ctx *gin.Context) (*taskmodel.Task, uuid.UUID, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, uuid.Nil, errors.New("Gagal format task ID")
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return nil, uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	if err := ensureWritable(task); err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, *currentUser)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("gagal memeriksa assignee task: %v", err)
+	}
+	if !isAssignee {
+		if err := s.validateProjectManager(ctx, task.ProjectID); err != nil {
+			return nil, uuid.Nil, errors.New("forbidden: hanya manager project atau assignee task yang bisa mengubah checklist")
+		}
+	}
+
+	return task, *currentUser, nil
+}
+
+func (s *taskService) loadChecklistItem(ctx *gin.Context, task *taskmodel.Task) (*taskmodel.ChecklistItem, error) {
+	itemUUID, err := uuid.Parse(ctx.Param("item_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format checklist item ID")
+	}
+
+	item, err := s.taskRepo.GetChecklistItem(task.ID, itemUUID)
+	if err != nil {
+		return nil, errors.New("checklist item tidak ditemukan")
+	}
+	return item, nil
+}
+
+func (s *taskService) buildChecklist(task *taskmodel.Task) (*taskmodel.Checklist, error) {
+	items, err := s.taskRepo.GetChecklistItems(task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	checklist := &taskmodel.Checklist{
+		TaskID: task.ID,
+		Total:  len(items),
+		Items:  items,
+	}
+	for _, item := range items {
+		if item.IsChecked {
+			checklist.Done++
+		}
+	}
+	checklist.SuggestDone = checklist.Total > 0 && checklist.Done == checklist.Total && task.Status != "done"
+
+	return checklist, nil
+}
+
+func validateChecklistContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("content tidak boleh kosong")
+	}
+	if utf8.RuneCountInString(content) > 500 {
+		return "", errors.New("content maksimal 500 karakter")
+	}
+	return content, nil
+}
+
+func (s *taskService) GetChecklist(ctx *gin.Context) (*taskmodel.Checklist, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
+
+// AddChecklistItem menambahkan item di paling bawah checklist
+func (s *taskService) AddChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error) {
+	task, currentUser, err := s.loadWorkableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.ChecklistItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	content, err := validateChecklistContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AssigneeID != nil {
+		if err := s.validateProjectMember(task.ProjectID, *req.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	item := &taskmodel.ChecklistItem{
+		TaskID:     task.ID,
+		Content:    content,
+		AssigneeID: req.AssigneeID,
+		CreatedBy:  &currentUser,
+	}
+	if err := s.taskRepo.CreateChecklistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
+
+// UpdateChecklistItem mengubah content atau assignee_id item (JSON Merge Patch), null menghapus assignee
+func (s *taskService) UpdateChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error) {
+	task, _, err := s.loadWorkableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.loadChecklistItem(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := patch.Bind(ctx, "content", "assignee_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Has("content") {
+		content, err := doc.String("content", false)
+		if err != nil {
+			return nil, err
+		}
+		if item.Content, err = validateChecklistContent(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Has("assignee_id") {
+		assigneeID, err := decodePatchID(doc, "assignee_id")
+		if err != nil {
+			return nil, err
+		}
+		if assigneeID != nil {
+			if err := s.validateProjectMember(task.ProjectID, *assigneeID); err != nil {
+				return nil, err
+			}
+		}
+		item.AssigneeID = assigneeID
+	}
+
+	if err := s.taskRepo.UpdateChecklistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
+
+// SetChecklistItemChecked mencentang atau menghapus centang item. Selain manager dan assignee task,
+// assignee item juga boleh mencentang itemnya sendiri
+func (s *taskService) SetChecklistItemChecked(ctx *gin.Context, checked bool) (*taskmodel.Checklist, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureWritable(task); err != nil {
+		return nil, err
+	}
+
+	item, err := s.loadChecklistItem(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.AssigneeID == nil || *item.AssigneeID != *currentUser {
+		if _, _, err := s.loadWorkableTask(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if item.IsChecked == checked {
+		return s.buildChecklist(task)
+	}
+
+	item.IsChecked = checked
+	if checked {
+		now := time.Now()
+		item.CheckedAt = &now
+		item.CheckedBy = currentUser
+	} else {
+		item.CheckedAt = nil
+		item.CheckedBy = nil
+	}
+
+	if err := s.taskRepo.UpdateChecklistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
+
+// MoveChecklistItem memindahkan item di antara item lain dengan satu update, sama seperti MoveTask
+func (s *taskService) MoveChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error) {
+	task, _, err := s.loadWorkableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.loadChecklistItem(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.MoveChecklistItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	var lower, upper string
+	switch {
+	case req.PreviousID != nil:
+		previous, err := s.checklistNeighbour(task, item, *req.PreviousID)
+		if err != nil {
+			return nil, err
+		}
+		lower = previous.Position
+		upper, err = s.taskRepo.GetAdjacentChecklistPosition(task.ID, lower, true, item.ID)
+		if err != nil {
+			return nil, err
+		}
+	case req.NextID != nil:
+		next, err := s.checklistNeighbour(task, item, *req.NextID)
+		if err != nil {
+			return nil, err
+		}
+		upper = next.Position
+		lower, err = s.taskRepo.GetAdjacentChecklistPosition(task.ID, upper, false, item.ID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		lower, err = s.taskRepo.GetLastChecklistPosition(task.ID, item.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if item.Position, err = rank.Between(lower, upper); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.UpdateChecklistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
+
+func (s *taskService) checklistNeighbour(task *taskmodel.Task, item *taskmodel.ChecklistItem, id uuid.UUID) (*taskmodel.ChecklistItem, error) {
+	if id == item.ID {
+		return nil, errors.New("item acuan tidak boleh item yang dipindah")
+	}
+	neighbour, err := s.taskRepo.GetChecklistItem(task.ID, id)
+	if err != nil {
+		return nil, errors.New("item acuan tidak ditemukan di checklist task ini")
+	}
+	return neighbour, nil
+}
+
+func (s *taskService) DeleteChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error) {
+	task, _, err := s.loadWorkableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.loadChecklistItem(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.DeleteChecklistItem(item.ID); err != nil {
+		return nil, err
+	}
+
+	return s.buildChecklist(task)
+}
@@ -0,0 +1,43 @@
+package taskservice
+
+import (
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+)
+
+// TaskEventHandler dipanggil di goroutine terpisah, jadi tidak memperlambat request
+type TaskEventHandler func(event taskmodel.TaskEvent)
+
+// Subscribe mendaftarkan handler yang menerima semua event task
+func (s *taskService) Subscribe(handler TaskEventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+func (s *taskService) publish(actor changeActor, action string, task *taskmodel.Task, changes []taskmodel.FieldChange) {
+	s.PublishTaskEvent(taskmodel.TaskEvent{
+		Action:  action,
+		Task:    *task,
+		Changes: changes,
+		ActorID: actor.ID,
+		Source:  actor.Source,
+	})
+}
+
+func (s *taskService) PublishTaskEvent(event taskmodel.TaskEvent) {
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler TaskEventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Printf("task event handler panic: %v\n", r)
+				}
+			}()
+			handler(event)
+		}(handler)
+	}
+}
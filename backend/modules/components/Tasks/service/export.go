@@ -0,0 +1,169 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	export "gintugas/modules/components/Export"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// taskPageFetcher mengambil satu halaman task sesuai filter dan cursor-nya
+type taskPageFetcher func(filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error)
+
+// ExportProjectTasks menulis semua task project yang cocok dengan filter listing ke response (hanya manager project).
+// format=csv (default), xlsx atau json
+func (s *taskService) ExportProjectTasks(ctx *gin.Context) error {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
+		return err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectUUID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	filter, err := parseTaskFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]interface{}{
+		"project": gin.H{"id": project.ID, "name": project.Nama},
+	}
+	fileName := fmt.Sprintf("project-%s-tasks", project.ID)
+	return s.streamTasks(ctx, filter, fileName, meta, func(filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+		tasks, nextCursor, err := s.taskRepo.GetTasksByProjectID(projectUUID, filter)
+		for i := range tasks {
+			tasks[i].Project = *project
+		}
+		return tasks, nextCursor, err
+	})
+}
+
+// ExportMyTasks menulis semua task milik user yang login, filternya sama dengan my-tasks
+func (s *taskService) ExportMyTasks(ctx *gin.Context) error {
+	userID := actorID(ctx)
+	if userID == nil {
+		return errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	filter, err := parseTaskFilter(ctx)
+	if err != nil {
+		return err
+	}
+	// my-tasks selalu milik user yang login
+	filter.AssigneeID = nil
+
+	meta := map[string]interface{}{
+		"user_id": *userID,
+	}
+	return s.streamTasks(ctx, filter, "my-tasks", meta, func(filter taskmodel.TaskFilter) ([]taskmodel.Task, string, error) {
+		return s.taskRepo.GetTasksByAssigneeID(*userID, filter)
+	})
+}
+
+// streamTasks membaca task per halaman dengan cursor dan langsung menulisnya ke response, jadi export besar tidak
+// ditampung di memori. Error sebelum halaman pertama dikembalikan biasa, setelah itu response sudah terkirim sebagian
+func (s *taskService) streamTasks(ctx *gin.Context, filter taskmodel.TaskFilter, fileName string, meta map[string]interface{}, fetch taskPageFetcher) error {
+	format, err := export.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	filter.Cursor = ""
+	filter.Limit = taskrepository.MaxTaskLimit
+	tasks, nextCursor, err := fetch(filter)
+	if err != nil {
+		return err
+	}
+
+	exportedAt := time.Now()
+	meta["exported_at"] = exportedAt
+	meta["filters"] = ctx.Request.URL.Query()
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, fileName, exportedAt.Format("20060102"), format))
+
+	writer, err := export.NewWriter(format, ctx.Writer, export.Layout{
+		Header: taskmodel.TaskExportHeader,
+		Sheet:  "Tasks",
+		Meta:   meta,
+		Key:    "tasks",
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := s.writeTaskExports(writer, tasks); err != nil {
+			return err
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+
+		if nextCursor == "" {
+			break
+		}
+		filter.Cursor = nextCursor
+		if tasks, nextCursor, err = fetch(filter); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (s *taskService) writeTaskExports(writer export.Writer, tasks []taskmodel.Task) error {
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	counts, err := s.taskRepo.GetCommentCounts(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		record := taskmodel.TaskExport{
+			ID:           task.ID,
+			ProjectID:    task.ProjectID,
+			ProjectName:  task.Project.Nama,
+			Title:        task.Title,
+			Description:  task.Description,
+			Status:       task.Status,
+			StartDate:    task.StartDate,
+			DueDate:      task.DueDate,
+			Assignees:    []taskmodel.TaskExportAssignee{},
+			CommentCount: counts[task.ID],
+			Archived:     task.IsReadOnly(),
+			CreatedAt:    task.CreatedAt,
+			UpdatedAt:    task.UpdatedAt,
+		}
+		for _, assignee := range task.Assignees {
+			item := taskmodel.TaskExportAssignee{UserID: assignee.UserID, IsPrimary: assignee.IsPrimary}
+			if assignee.User != nil {
+				item.Username = assignee.User.Username
+				item.Email = assignee.User.Email
+			}
+			record.Assignees = append(record.Assignees, item)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var validTaskStatuses = map[string]bool{
+	"todo":        true,
+	"in-progress": true,
+	"in-review":   true,
+	"done":        true,
+}
+
+// parseTaskFilter membaca query parameter listing task
+// contoh: ?status=todo,in-progress&assignee_id=...&due_from=2025-01-01&overdue=true&q=invoice&sort=-due_date&limit=20
+// sprint_id dan milestone_id menerima "none" untuk task yang belum dijadwalkan,
+// archived=true hanya menampilkan task yang diarsipkan (termasuk lewat project-nya) dan archived=all menampilkan semuanya
+func parseTaskFilter(ctx *gin.Context) (taskmodel.TaskFilter, error) {
+	var query taskmodel.TaskListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return taskmodel.TaskFilter{}, errors.New("query parameter tidak valid: " + err.Error())
+	}
+	return buildTaskFilter(query)
+}
+
+// buildTaskFilter memvalidasi TaskListQuery, dipakai juga untuk filter saved view
+func buildTaskFilter(query taskmodel.TaskListQuery) (taskmodel.TaskFilter, error) {
+	filter := taskmodel.TaskFilter{
+		Search: strings.TrimSpace(query.Search),
+		Cursor: query.Cursor,
+		Limit:  query.Limit,
+	}
+
+	if query.Status != "" {
+		for _, status := range strings.Split(query.Status, ",") {
+			status = strings.TrimSpace(status)
+			if !validTaskStatuses[status] {
+				return filter, fmt.Errorf("status tidak valid: %s", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if query.AssigneeID != "" {
+		assigneeID, err := uuid.Parse(query.AssigneeID)
+		if err != nil {
+			return filter, errors.New("assignee_id tidak valid")
+		}
+		filter.AssigneeID = &assigneeID
+	}
+
+	if query.DueFrom != "" {
+		dueFrom, err := time.Parse("2006-01-02", query.DueFrom)
+		if err != nil {
+			return filter, errors.New("due_from harus berformat YYYY-MM-DD")
+		}
+		filter.DueFrom = &dueFrom
+	}
+
+	if query.DueTo != "" {
+		dueTo, err := time.Parse("2006-01-02", query.DueTo)
+		if err != nil {
+			return filter, errors.New("due_to harus berformat YYYY-MM-DD")
+		}
+		filter.DueTo = &dueTo
+	}
+
+	if query.Overdue != "" {
+		overdue, err := strconv.ParseBool(query.Overdue)
+		if err != nil {
+			return filter, errors.New("overdue harus bernilai true atau false")
+		}
+		filter.Overdue = &overdue
+	}
+
+	if query.Sprint != "" {
+		sprintID, err := parseOptionalID(query.Sprint)
+		if err != nil {
+			return filter, errors.New("sprint_id tidak valid")
+		}
+		filter.SprintID = &sprintID
+	}
+
+	if query.Milestone != "" {
+		milestoneID, err := parseOptionalID(query.Milestone)
+		if err != nil {
+			return filter, errors.New("milestone_id tidak valid")
+		}
+		filter.MilestoneID = &milestoneID
+	}
+
+	archived, err := archive.ParseFilter(query.Archived)
+	if err != nil {
+		return filter, err
+	}
+	filter.Archived = archived
+
+	if query.Limit < 0 {
+		return filter, errors.New("limit tidak boleh negatif")
+	}
+
+	// prefix "-" untuk urutan descending
+	sort := strings.TrimSpace(query.Sort)
+	if strings.HasPrefix(sort, "-") {
+		filter.Desc = true
+		sort = strings.TrimPrefix(sort, "-")
+	}
+	if sort != "" && !taskrepository.IsValidTaskSort(sort) {
+		return filter, fmt.Errorf("sort tidak didukung: %s", sort)
+	}
+	filter.Sort = sort
+
+	return filter, nil
+}
+
+// parseOptionalID mengubah "none" menjadi uuid.Nil
+func parseOptionalID(value string) (uuid.UUID, error) {
+	if value == "none" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
+
+func (s *taskService) buildTaskPage(tasks []taskmodel.Task, nextCursor string) *taskmodel.TaskPage {
+	page := &taskmodel.TaskPage{
+		Tasks: make([]taskmodel.TaskResponse, 0, len(tasks)),
+	}
+	for i := range tasks {
+		page.Tasks = append(page.Tasks, *s.convertToResponse(&tasks[i]))
+	}
+	if nextCursor != "" {
+		page.NextCursor = &nextCursor
+	}
+	return page
+}
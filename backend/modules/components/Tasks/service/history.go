@@ -0,0 +1,117 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// actorID mengambil user yang sedang login untuk dicatat di history
+func actorID(ctx *gin.Context) *uuid.UUID {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return nil
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		return nil
+	}
+
+	userUUID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil
+	}
+	return &userUUID
+}
+
+// changeActor adalah pelaku perubahan task yang dicatat di history, review dan event. Perubahan lewat API memakai user
+// yang login dengan Source kosong, perubahan oleh proses background memakai ID nil dengan Source sesuai asalnya
+type changeActor struct {
+	ID     *uuid.UUID
+	Source string
+}
+
+// requestActor mengambil pelaku perubahan dari request API
+func requestActor(ctx *gin.Context) changeActor {
+	return changeActor{ID: actorID(ctx)}
+}
+
+func newHistory(actor changeActor, action string, task *taskmodel.Task, changes []taskmodel.FieldChange) *taskmodel.TaskHistory {
+	if changes == nil {
+		changes = []taskmodel.FieldChange{}
+	}
+	return &taskmodel.TaskHistory{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		TaskTitle: task.Title,
+		ActorID:   actor.ID,
+		Action:    action,
+		Changes:   changes,
+	}
+}
+
+// authorizeProjectRead memastikan user boleh membaca data project: admin, manager project atau member project.
+// Project yang ada di trash tetap diperiksa supaya history task yang dihapus masih bisa dibaca
+func (s *taskService) authorizeProjectRead(ctx *gin.Context, projectID uuid.UUID) error {
+	if ctx.GetString("user_role") == "admin" {
+		return nil
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return errors.New("unauthorized: user tidak terautentikasi")
+	}
+	allowed, err := s.taskRepo.CanViewProject(projectID, *currentUser)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa member project: %v", err)
+	}
+	if !allowed {
+		return errors.New("forbidden: hanya manager atau member project yang bisa melihat task ini")
+	}
+	return nil
+}
+
+// loadReadableTask mengambil task dari path untuk dibaca: hanya admin, manager project dan member project.
+// Task di trash dianggap tidak ada
+func (s *taskService) loadReadableTask(ctx *gin.Context) (*taskmodel.Task, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, errors.New("task tidak ditemukan")
+	}
+
+	if err := s.authorizeProjectRead(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// GetTaskHistory tidak memakai loadReadableTask supaya history task yang sudah di trash tetap bisa dibaca.
+// Akses diperiksa dari project_id history terbaru karena task bisa dipindah antar project
+func (s *taskService) GetTaskHistory(ctx *gin.Context) ([]taskmodel.TaskHistory, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	history, err := s.taskRepo.GetTaskHistory(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, errors.New("task tidak ditemukan")
+	}
+
+	if err := s.authorizeProjectRead(ctx, history[0].ProjectID); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
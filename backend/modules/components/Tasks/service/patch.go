@@ -0,0 +1,218 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
+	patch "gintugas/modules/components/Patch"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// field task yang boleh diubah lewat PATCH
+var taskPatchFields = []string{"title", "description", "status", "assignee_id", "due_date", "start_date", "original_estimate_minutes", "sprint_id", "milestone_id"}
+
+// PatchTask mengubah sebagian field task (JSON Merge Patch).
+// Field yang tidak dikirim tidak berubah, null menghapus nilai description, assignee_id, due_date, start_date, original_estimate_minutes, sprint_id dan milestone_id
+func (s *taskService) PatchTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	existingTask, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.taskEditorRole(ctx, existingTask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureWritable(existingTask); err != nil {
+		return nil, err
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, existingTask.Version); err != nil {
+		return nil, err
+	}
+
+	doc, err := patch.Bind(ctx, taskPatchFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *existingTask
+	if err := s.applyTaskPatch(existingTask, doc); err != nil {
+		return nil, err
+	}
+	if err := authorizeTaskChanges(role, &before, existingTask, taskmodel.DiffTask(&before, existingTask)); err != nil {
+		return nil, err
+	}
+
+	existingTask.UpdatedAt = time.Now()
+
+	if err := s.saveTaskUpdate(requestActor(ctx), &before, existingTask); err != nil {
+		return nil, err
+	}
+
+	// notifikasi hanya jika assignee benar-benar berganti
+	if existingTask.AssigneeID != nil && (before.AssigneeID == nil || *before.AssigneeID != *existingTask.AssigneeID) {
+		if err := s.notifyAssignee(existingTask); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.reloadTask(existingTask)
+}
+
+func (s *taskService) applyTaskPatch(task *taskmodel.Task, doc patch.Document) error {
+	if doc.Has("title") {
+		title, err := doc.String("title", false)
+		if err != nil {
+			return err
+		}
+		title = strings.TrimSpace(title)
+		if title == "" {
+			return errors.New("title tidak boleh kosong")
+		}
+		if utf8.RuneCountInString(title) > 200 {
+			return errors.New("title maksimal 200 karakter")
+		}
+		task.Title = title
+	}
+
+	if doc.Has("description") {
+		description, err := doc.String("description", true)
+		if err != nil {
+			return err
+		}
+		task.Description = description
+	}
+
+	if doc.Has("status") {
+		status, err := doc.String("status", false)
+		if err != nil {
+			return err
+		}
+		if !validTaskStatuses[status] {
+			return fmt.Errorf("status tidak valid: %s", status)
+		}
+		task.Status = status
+	}
+
+	if doc.Has("assignee_id") {
+		if doc.IsNull("assignee_id") {
+			task.AssigneeID = nil
+		} else {
+			var assigneeID uuid.UUID
+			if err := doc.Decode("assignee_id", &assigneeID); err != nil {
+				return err
+			}
+			if err := s.validateProjectMember(task.ProjectID, assigneeID); err != nil {
+				return err
+			}
+			task.AssigneeID = &assigneeID
+		}
+	}
+
+	if doc.Has("due_date") {
+		dueDate, err := decodePatchDate(doc, "due_date")
+		if err != nil {
+			return err
+		}
+		task.DueDate = dueDate
+	}
+
+	if doc.Has("start_date") {
+		startDate, err := decodePatchDate(doc, "start_date")
+		if err != nil {
+			return err
+		}
+		task.StartDate = startDate
+	}
+
+	if doc.Has("original_estimate_minutes") {
+		if doc.IsNull("original_estimate_minutes") {
+			task.OriginalEstimateMinutes = nil
+		} else {
+			var estimate int
+			if err := doc.Decode("original_estimate_minutes", &estimate); err != nil {
+				return err
+			}
+			if estimate < 0 {
+				return errors.New("original_estimate_minutes tidak boleh negatif")
+			}
+			task.OriginalEstimateMinutes = &estimate
+		}
+	}
+
+	if doc.Has("sprint_id") {
+		sprintID, err := decodePatchID(doc, "sprint_id")
+		if err != nil {
+			return err
+		}
+		if err := s.validatePlanning(task.ProjectID, sprintID, nil); err != nil {
+			return err
+		}
+		task.SprintID = sprintID
+	}
+
+	if doc.Has("milestone_id") {
+		milestoneID, err := decodePatchID(doc, "milestone_id")
+		if err != nil {
+			return err
+		}
+		if err := s.validatePlanning(task.ProjectID, nil, milestoneID); err != nil {
+			return err
+		}
+		task.MilestoneID = milestoneID
+	}
+
+	return nil
+}
+
+// decodePatchID membaca field UUID yang boleh null
+func decodePatchID(doc patch.Document, field string) (*uuid.UUID, error) {
+	if doc.IsNull(field) {
+		return nil, nil
+	}
+	var id uuid.UUID
+	if err := doc.Decode(field, &id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// decodePatchDate membaca field tanggal yang boleh null
+func decodePatchDate(doc patch.Document, field string) (*time.Time, error) {
+	if doc.IsNull(field) {
+		return nil, nil
+	}
+	raw, err := doc.String(field, false)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parsePatchDate(field, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// parsePatchDate menerima YYYY-MM-DD atau RFC3339
+func parsePatchDate(field, value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("%s harus berformat YYYY-MM-DD atau RFC3339", field)
+}
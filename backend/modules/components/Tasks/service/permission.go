@@ -0,0 +1,94 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// taskRole adalah peran user terhadap satu task, urutannya dari yang paling sedikit haknya
+type taskRole int
+
+const (
+	roleAssignee taskRole = iota + 1
+	roleManager
+)
+
+// taskFieldRoles adalah satu-satunya tempat hak ubah field task diatur: peran minimum yang boleh mengubah field tersebut.
+// Field yang tidak ada di sini hanya boleh diubah manager project
+var taskFieldRoles = map[string]taskRole{
+	"title":                     roleManager,
+	"description":               roleManager,
+	"status":                    roleAssignee,
+	"assignee_id":               roleManager,
+	"due_date":                  roleManager,
+	"start_date":                roleManager,
+	"original_estimate_minutes": roleManager,
+	"sprint_id":                 roleManager,
+	"milestone_id":              roleManager,
+}
+
+// taskEditorRole menentukan peran user yang login terhadap task: manager project, assignee task, atau error jika bukan keduanya
+func (s *taskService) taskEditorRole(ctx *gin.Context, task *taskmodel.Task) (taskRole, error) {
+	if err := s.validateProjectManager(ctx, task.ProjectID); err == nil {
+		return roleManager, nil
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return 0, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, *currentUser)
+	if err != nil {
+		return 0, fmt.Errorf("gagal memeriksa assignee task: %v", err)
+	}
+	if !isAssignee {
+		return 0, errors.New("forbidden: hanya manager project atau assignee task yang bisa mengubah task ini")
+	}
+	return roleAssignee, nil
+}
+
+// authorizeTaskChanges memeriksa setiap field yang benar-benar berubah terhadap taskFieldRoles, jadi field yang dikirim
+// ulang dengan nilai yang sama tidak ditolak. Untuk assignee, perpindahan status juga harus lolos assigneeStatusRule
+func authorizeTaskChanges(role taskRole, before, after *taskmodel.Task, changes []taskmodel.FieldChange) error {
+	if role == roleManager {
+		return nil
+	}
+
+	denied := []string{}
+	for _, change := range changes {
+		required, ok := taskFieldRoles[change.Field]
+		if !ok {
+			required = roleManager
+		}
+		if role < required {
+			denied = append(denied, change.Field)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("forbidden: assignee tidak boleh mengubah %s", strings.Join(denied, ", "))
+	}
+
+	if before.Status != after.Status {
+		return assigneeStatusRule(before.Status, after.Status)
+	}
+	return nil
+}
+
+// assigneeStatusRule: task yang sedang direview menunggu keputusan manager, task done hanya bisa dibuka lagi oleh manager,
+// dan done hanya bisa dicapai lewat approval review
+func assigneeStatusRule(from, to string) error {
+	switch {
+	case from == taskmodel.StatusInReview:
+		return errors.New("task sedang direview, tunggu keputusan manager project")
+	case from == "done":
+		return errors.New("task yang sudah done hanya bisa diubah manager project")
+	case to == "done":
+		return errors.New("assignee tidak bisa langsung menyelesaikan task, ajukan review ke manager project")
+	}
+	return nil
+}
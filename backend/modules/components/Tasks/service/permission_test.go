@@ -0,0 +1,99 @@
+package taskservice
+
+import (
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"testing"
+)
+
+func TestAuthorizeTaskChangesFields(t *testing.T) {
+	tests := []struct {
+		role    taskRole
+		field   string
+		allowed bool
+	}{
+		{roleManager, "title", true},
+		{roleManager, "description", true},
+		{roleManager, "status", true},
+		{roleManager, "assignee_id", true},
+		{roleManager, "due_date", true},
+		{roleManager, "start_date", true},
+		{roleManager, "original_estimate_minutes", true},
+		{roleManager, "sprint_id", true},
+		{roleManager, "milestone_id", true},
+		{roleManager, "priority", true},
+		{roleAssignee, "status", true},
+		{roleAssignee, "title", false},
+		{roleAssignee, "description", false},
+		{roleAssignee, "assignee_id", false},
+		{roleAssignee, "due_date", false},
+		{roleAssignee, "start_date", false},
+		{roleAssignee, "original_estimate_minutes", false},
+		{roleAssignee, "sprint_id", false},
+		{roleAssignee, "milestone_id", false},
+		// field yang tidak terdaftar di taskFieldRoles hanya boleh diubah manager
+		{roleAssignee, "priority", false},
+	}
+
+	for _, tt := range tests {
+		// status tetap sama supaya yang diuji hanya hak ubah field, bukan assigneeStatusRule
+		before := &taskmodel.Task{Status: "todo"}
+		after := &taskmodel.Task{Status: "todo"}
+		changes := []taskmodel.FieldChange{{Field: tt.field}}
+
+		err := authorizeTaskChanges(tt.role, before, after, changes)
+		if got := err == nil; got != tt.allowed {
+			t.Errorf("role %d mengubah %q: allowed = %v, want %v (err: %v)", tt.role, tt.field, got, tt.allowed, err)
+		}
+	}
+}
+
+func TestAuthorizeTaskChangesReportsAllDeniedFields(t *testing.T) {
+	before := &taskmodel.Task{Status: "todo"}
+	after := &taskmodel.Task{Status: "todo"}
+	changes := []taskmodel.FieldChange{{Field: "title"}, {Field: "status"}, {Field: "due_date"}}
+
+	err := authorizeTaskChanges(roleAssignee, before, after, changes)
+	want := "forbidden: assignee tidak boleh mengubah title, due_date"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %q", err, want)
+	}
+}
+
+func TestAssigneeStatusRule(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{"todo", "in-progress", true},
+		{"in-progress", "todo", true},
+		{"todo", taskmodel.StatusInReview, true},
+		{"in-progress", taskmodel.StatusInReview, true},
+		{"todo", "done", false},
+		{"in-progress", "done", false},
+		{taskmodel.StatusInReview, "in-progress", false},
+		{taskmodel.StatusInReview, "todo", false},
+		{taskmodel.StatusInReview, "done", false},
+		{"done", "todo", false},
+		{"done", "in-progress", false},
+		{"done", taskmodel.StatusInReview, false},
+	}
+
+	for _, tt := range tests {
+		err := assigneeStatusRule(tt.from, tt.to)
+		if got := err == nil; got != tt.allowed {
+			t.Errorf("assigneeStatusRule(%q, %q): allowed = %v, want %v (err: %v)", tt.from, tt.to, got, tt.allowed, err)
+		}
+
+		// lewat authorizeTaskChanges hasilnya harus sama untuk assignee, dan manager selalu boleh
+		before := &taskmodel.Task{Status: tt.from}
+		after := &taskmodel.Task{Status: tt.to}
+		changes := []taskmodel.FieldChange{{Field: "status"}}
+		if err := authorizeTaskChanges(roleAssignee, before, after, changes); (err == nil) != tt.allowed {
+			t.Errorf("assignee %q -> %q lewat authorizeTaskChanges: err = %v, want allowed %v", tt.from, tt.to, err, tt.allowed)
+		}
+		if err := authorizeTaskChanges(roleManager, before, after, changes); err != nil {
+			t.Errorf("manager %q -> %q: err = %v, want nil", tt.from, tt.to, err)
+		}
+	}
+}
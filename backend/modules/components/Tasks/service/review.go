@@ -0,0 +1,239 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// loadAssignedTask mengambil task dari path untuk assignee-nya sendiri, task yang diarsipkan tidak bisa diubah
+func (s *taskService) loadAssignedTask(ctx *gin.Context) (*taskmodel.Task, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, *currentUser)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa assignee task: %v", err)
+	}
+	if !isAssignee {
+		return nil, errors.New("forbidden: hanya assignee task yang bisa melakukan operasi ini")
+	}
+
+	if err := ensureWritable(task); err != nil {
+		return nil, err
+	}
+	if err := concurrency.CheckIfMatch(ctx, task.Version); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SetTaskStatus memindahkan task milik assignee sendiri antara todo dan in-progress, aturannya sama dengan UpdateTask oleh assignee
+func (s *taskService) SetTaskStatus(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadAssignedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.TaskStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	before := *task
+	task.Status = req.Status
+	task.UpdatedAt = time.Now()
+
+	if err := authorizeTaskChanges(roleAssignee, &before, task, taskmodel.DiffTask(&before, task)); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveTaskUpdate(requestActor(ctx), &before, task); err != nil {
+		return nil, err
+	}
+	return s.reloadTask(task)
+}
+
+// SubmitTaskReview dipakai assignee untuk mengajukan task ke manager project, status task menjadi in-review
+func (s *taskService) SubmitTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadAssignedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := assigneeStatusRule(task.Status, taskmodel.StatusInReview); err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.SubmitReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	review := &taskmodel.TaskReview{
+		TaskID:      task.ID,
+		SubmittedBy: actorID(ctx),
+		Note:        strings.TrimSpace(req.Note),
+	}
+
+	before := *task
+	task.Status = taskmodel.StatusInReview
+	task.UpdatedAt = time.Now()
+
+	err = s.commitTaskChange(requestActor(ctx), task, taskmodel.DiffTask(&before, task), func(repo taskrepository.TaskRepository) error {
+		return repo.CreateTaskReview(review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReviewRequest(ctx, task)
+	return s.reloadTask(task)
+}
+
+// ApproveTaskReview menyelesaikan review pending, task menjadi done
+func (s *taskService) ApproveTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	return s.decideTaskReview(ctx, taskmodel.ReviewApproved)
+}
+
+// RejectTaskReview mengembalikan task ke in-progress, comment wajib diisi supaya assignee tahu apa yang harus diperbaiki
+func (s *taskService) RejectTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	return s.decideTaskReview(ctx, taskmodel.ReviewRejected)
+}
+
+func (s *taskService) decideTaskReview(ctx *gin.Context, decision string) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.ReviewDecisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if decision == taskmodel.ReviewRejected && comment == "" {
+		return nil, errors.New("comment wajib diisi saat menolak review")
+	}
+
+	review, err := s.taskRepo.GetPendingReview(task.ID)
+	if task.Status != taskmodel.StatusInReview || err != nil {
+		return nil, errors.New("task tidak sedang menunggu review")
+	}
+
+	before := *task
+	task.Status = "in-progress"
+	if decision == taskmodel.ReviewApproved {
+		task.Status = "done"
+	}
+	task.UpdatedAt = time.Now()
+
+	err = s.commitTaskChange(requestActor(ctx), task, taskmodel.DiffTask(&before, task), func(repo taskrepository.TaskRepository) error {
+		return repo.ResolvePendingReview(task.ID, decision, actorID(ctx), comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if review.SubmittedBy != nil {
+		s.notifyReviewDecision(task, *review.SubmittedBy, decision, comment)
+	}
+	return s.reloadTask(task)
+}
+
+// GetTaskReviews mengembalikan riwayat review task, yang terbaru lebih dulu
+func (s *taskService) GetTaskReviews(ctx *gin.Context) ([]taskmodel.TaskReview, error) {
+	task, err := s.loadReadableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetTaskReviews(task.ID)
+}
+
+// syncReview menjaga review pending sesuai status task. Task yang masuk in-review lewat update biasa (misalnya board)
+// mendapat review baru, task yang keluar dari in-review tanpa lewat approve/reject dianggap diputuskan oleh actor:
+// pindah ke done berarti approved, status lain berarti rejected
+func syncReview(actor changeActor, repo taskrepository.TaskRepository, task *taskmodel.Task, changes []taskmodel.FieldChange) error {
+	for _, change := range changes {
+		if change.Field != "status" {
+			continue
+		}
+
+		if change.Old == taskmodel.StatusInReview {
+			decision := taskmodel.ReviewRejected
+			if task.Status == "done" {
+				decision = taskmodel.ReviewApproved
+			}
+			return repo.ResolvePendingReview(task.ID, decision, actor.ID, "")
+		}
+
+		if task.Status == taskmodel.StatusInReview {
+			if _, err := repo.GetPendingReview(task.ID); err == nil {
+				return nil
+			}
+			return repo.CreateTaskReview(&taskmodel.TaskReview{TaskID: task.ID, SubmittedBy: actor.ID})
+		}
+	}
+	return nil
+}
+
+func (s *taskService) notifyReviewRequest(ctx *gin.Context, task *taskmodel.Task) {
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil detail projek: %v\n", err)
+		return
+	}
+	manager, err := s.taskRepo.GetUserByID(project.ManagerID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil data manager: %v\n", err)
+		return
+	}
+
+	submitter := ""
+	if actor := actorID(ctx); actor != nil {
+		if user, err := s.taskRepo.GetUserByID(*actor); err == nil {
+			submitter = user.Username
+		}
+	}
+
+	if err := s.mailService.SendReviewRequestNotification(manager.Email, task.Title, project.Nama, submitter); err != nil {
+		fmt.Printf("Gagal untuk mengirim notif review ke manager: %v\n", err)
+	}
+}
+
+func (s *taskService) notifyReviewDecision(task *taskmodel.Task, submitterID uuid.UUID, decision, comment string) {
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil detail projek: %v\n", err)
+		return
+	}
+	submitter, err := s.taskRepo.GetUserByID(submitterID)
+	if err != nil {
+		fmt.Printf("Gagal mengambil data pengaju review: %v\n", err)
+		return
+	}
+
+	err = s.mailService.SendReviewDecisionNotification(submitter.Email, task.Title, project.Nama, decision == taskmodel.ReviewApproved, comment)
+	if err != nil {
+		fmt.Printf("Gagal untuk mengirim notif hasil review: %v\n", err)
+	}
+}
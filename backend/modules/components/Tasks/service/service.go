@@ -3,10 +3,12 @@ package taskservice
 import (
 	"errors"
 	"fmt"
+	concurrency "gintugas/modules/components/Concurrency"
 	. "gintugas/modules/components/Mail/service"
 	taskmodel "gintugas/modules/components/Tasks/model"
 	taskrepository "gintugas/modules/components/Tasks/repository"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,16 +17,67 @@ import (
 
 type TaskService interface {
 	CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
-	GetProjectTasks(ctx *gin.Context) ([]taskmodel.TaskResponse, error)
+	GetProjectTasks(ctx *gin.Context) (*taskmodel.TaskPage, error)
 	GetTaskByID(ctx *gin.Context) (*taskmodel.TaskResponse, error)
 	UpdateTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	PatchTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	MoveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
 	DeleteTask(ctx *gin.Context) error
 	GetMyTasks(c *gin.Context)
+	ExportProjectTasks(ctx *gin.Context) error
+	ExportMyTasks(ctx *gin.Context) error
+	GetTaskHistory(ctx *gin.Context) ([]taskmodel.TaskHistory, error)
+
+	GetTaskAssignees(ctx *gin.Context) ([]taskmodel.TaskAssignee, error)
+	AddTaskAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	RemoveTaskAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	SetPrimaryAssignee(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	GetTaskWatchers(ctx *gin.Context) ([]taskmodel.TaskWatcher, error)
+	WatchTask(ctx *gin.Context) ([]taskmodel.TaskWatcher, error)
+	UnwatchTask(ctx *gin.Context) error
+
+	MoveTaskToProject(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	CloneTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+
+	GetChecklist(ctx *gin.Context) (*taskmodel.Checklist, error)
+	AddChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error)
+	UpdateChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error)
+	SetChecklistItemChecked(ctx *gin.Context, checked bool) (*taskmodel.Checklist, error)
+	MoveChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error)
+	DeleteChecklistItem(ctx *gin.Context) (*taskmodel.Checklist, error)
+
+	ArchiveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	UnarchiveTask(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+
+	BulkUpdateTasks(ctx *gin.Context) (*taskmodel.BulkTaskResult, error)
+
+	SetTaskStatus(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	SubmitTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	ApproveTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	RejectTaskReview(ctx *gin.Context) (*taskmodel.TaskResponse, error)
+	GetTaskReviews(ctx *gin.Context) ([]taskmodel.TaskReview, error)
+
+	GetSavedViews(ctx *gin.Context) ([]taskmodel.SavedView, error)
+	CreateSavedView(ctx *gin.Context) (*taskmodel.SavedView, error)
+	UpdateSavedView(ctx *gin.Context) (*taskmodel.SavedView, error)
+	DeleteSavedView(ctx *gin.Context) error
+	GetSavedViewTasks(ctx *gin.Context) (*taskmodel.TaskPage, error)
+	PinSavedView(ctx *gin.Context) (*taskmodel.SavedView, error)
+	UnpinSavedView(ctx *gin.Context) error
+
+	UpdateTaskAsSystem(taskID uuid.UUID, source string, mutate func(task *taskmodel.Task) error) (*taskmodel.Task, []taskmodel.FieldChange, error)
+
+	Subscribe(handler TaskEventHandler)
+	// PublishTaskEvent mengirim event ke semua subscriber, untuk task yang dibuat service lain
+	PublishTaskEvent(event taskmodel.TaskEvent)
 }
 
 type taskService struct {
 	taskRepo    taskrepository.TaskRepository
 	mailService MailService
+
+	mu       sync.RWMutex
+	handlers []TaskEventHandler
 }
 
 func NewTaskService(taskRepo taskrepository.TaskRepository, mailService MailService) TaskService {
@@ -77,6 +130,34 @@ func (s *taskService) validateProjectMember(projectID uuid.UUID, assigneeID uuid
 	return nil
 }
 
+// validatePlanning memastikan sprint dan milestone milik project yang sama, sprint yang sudah ditutup tidak bisa diisi
+func (s *taskService) validatePlanning(projectID uuid.UUID, sprintID, milestoneID *uuid.UUID) error {
+	if sprintID != nil {
+		sprintProject, status, err := s.taskRepo.GetSprintState(*sprintID)
+		if err != nil {
+			return fmt.Errorf("sprint tidak ditemukan: %v", err)
+		}
+		if sprintProject != projectID {
+			return errors.New("sprint harus berasal dari project yang sama")
+		}
+		if status == "closed" {
+			return errors.New("sprint sudah ditutup")
+		}
+	}
+
+	if milestoneID != nil {
+		milestoneProject, err := s.taskRepo.GetMilestoneProjectID(*milestoneID)
+		if err != nil {
+			return fmt.Errorf("milestone tidak ditemukan: %v", err)
+		}
+		if milestoneProject != projectID {
+			return errors.New("milestone harus berasal dari project yang sama")
+		}
+	}
+
+	return nil
+}
+
 func (s *taskService) CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
 	projectID := ctx.Param("project_id")
 	projectUUID, err := uuid.Parse(projectID)
@@ -87,6 +168,9 @@ func (s *taskService) CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
 		return nil, err
 	}
+	if err := s.ensureProjectWritable(projectUUID); err != nil {
+		return nil, err
+	}
 
 	var taskReq taskmodel.TaskRequest
 	if err := ctx.ShouldBindJSON(&taskReq); err != nil {
@@ -99,6 +183,10 @@ func (s *taskService) CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 		}
 	}
 
+	if err := s.validatePlanning(projectUUID, taskReq.SprintID, taskReq.MilestoneID); err != nil {
+		return nil, err
+	}
+
 	task := &taskmodel.Task{
 		ProjectID:   projectUUID,
 		Title:       taskReq.Title,
@@ -106,16 +194,38 @@ func (s *taskService) CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 		Status:      taskReq.Status,
 		AssigneeID:  taskReq.AssigneeID,
 		DueDate:     taskReq.DueDate,
+		StartDate:   taskReq.StartDate,
+
+		OriginalEstimateMinutes: taskReq.OriginalEstimateMinutes,
+
+		SprintID:    taskReq.SprintID,
+		MilestoneID: taskReq.MilestoneID,
 	}
 
 	if task.Status == "" {
 		task.Status = "todo"
 	}
 
-	if err := s.taskRepo.CreateTask(task); err != nil {
+	actor := requestActor(ctx)
+	err = s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		if err := repo.CreateTask(task); err != nil {
+			return err
+		}
+		if err := repo.ReplacePrimaryAssignee(task.ID, nil, task.AssigneeID, false); err != nil {
+			return err
+		}
+		changes := taskmodel.DiffTask(nil, task)
+		if err := syncReview(actor, repo, task, changes); err != nil {
+			return err
+		}
+		return repo.CreateTaskHistory(newHistory(actor, taskmodel.HistoryCreated, task, changes))
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.publish(actor, taskmodel.HistoryCreated, task, taskmodel.DiffTask(nil, task))
+
 	if task.AssigneeID != nil {
 		assignee, err := s.taskRepo.GetUserByID(*task.AssigneeID)
 		if err != nil {
@@ -142,24 +252,24 @@ func (s *taskService) CreateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 
 }
 
-func (s *taskService) GetProjectTasks(ctx *gin.Context) ([]taskmodel.TaskResponse, error) {
+func (s *taskService) GetProjectTasks(ctx *gin.Context) (*taskmodel.TaskPage, error) {
 	projectID := ctx.Param("project_id")
 	projectUUID, err := uuid.Parse(projectID)
 	if err != nil {
 		return nil, errors.New("Gagal format Project ID")
 	}
 
-	tasks, err := s.taskRepo.GetTasksByProjectID(projectUUID)
+	filter, err := parseTaskFilter(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var response []taskmodel.TaskResponse
-	for _, task := range tasks {
-		response = append(response, *s.convertToResponse(&task))
+	tasks, nextCursor, err := s.taskRepo.GetTasksByProjectID(projectUUID, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	return s.buildTaskPage(tasks, nextCursor), nil
 }
 
 func (s *taskService) GetTaskByID(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
@@ -189,7 +299,16 @@ func (s *taskService) UpdateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 		return nil, err
 	}
 
-	if err := s.validateProjectManager(ctx, existingTask.ProjectID); err != nil {
+	role, err := s.taskEditorRole(ctx, existingTask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureWritable(existingTask); err != nil {
+		return nil, err
+	}
+
+	if err := concurrency.CheckIfMatch(ctx, existingTask.Version); err != nil {
 		return nil, err
 	}
 
@@ -198,11 +317,7 @@ func (s *taskService) UpdateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 		return nil, err
 	}
 
-	if taskReq.AssigneeID != nil {
-		if err := s.validateProjectMember(existingTask.ProjectID, *taskReq.AssigneeID); err != nil {
-			return nil, err
-		}
-	}
+	before := *existingTask
 
 	if taskReq.Title != "" {
 		existingTask.Title = taskReq.Title
@@ -220,36 +335,117 @@ func (s *taskService) UpdateTask(ctx *gin.Context) (*taskmodel.TaskResponse, err
 	if taskReq.DueDate != nil && !taskReq.DueDate.IsZero() {
 		existingTask.DueDate = taskReq.DueDate
 	}
+	if taskReq.StartDate != nil && !taskReq.StartDate.IsZero() {
+		existingTask.StartDate = taskReq.StartDate
+	}
+	if taskReq.OriginalEstimateMinutes != nil {
+		existingTask.OriginalEstimateMinutes = taskReq.OriginalEstimateMinutes
+	}
+	if taskReq.SprintID != nil {
+		existingTask.SprintID = taskReq.SprintID
+	}
+	if taskReq.MilestoneID != nil {
+		existingTask.MilestoneID = taskReq.MilestoneID
+	}
+
+	// hak ubah diperiksa dari field yang benar-benar berubah, lihat taskFieldRoles
+	if err := authorizeTaskChanges(role, &before, existingTask, taskmodel.DiffTask(&before, existingTask)); err != nil {
+		return nil, err
+	}
+
+	if taskReq.AssigneeID != nil {
+		if err := s.validateProjectMember(existingTask.ProjectID, *taskReq.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validatePlanning(existingTask.ProjectID, taskReq.SprintID, taskReq.MilestoneID); err != nil {
+		return nil, err
+	}
 
 	existingTask.UpdatedAt = time.Now()
 
-	if err := s.taskRepo.UpdateTask(existingTask); err != nil {
+	if err := s.saveTaskUpdate(requestActor(ctx), &before, existingTask); err != nil {
 		return nil, err
 	}
 
 	if existingTask.AssigneeID != nil {
-		assignee, err := s.taskRepo.GetUserByID(*existingTask.AssigneeID)
-		if err != nil {
-			return nil, fmt.Errorf("Gagal mengambil detail assignee: %v", err)
+		if err := s.notifyAssignee(existingTask); err != nil {
+			return nil, err
 		}
+	}
 
-		project, err := s.taskRepo.GetProjectByID(existingTask.ProjectID)
-		if err != nil {
-			return nil, fmt.Errorf("Gagal mengambil detail projek: %v", err)
+	return s.reloadTask(existingTask)
+}
+
+// saveTaskUpdate menyimpan perubahan field task, assignee utama di task_assignees ikut diganti jika assignee_id berubah
+func (s *taskService) saveTaskUpdate(actor changeActor, before, task *taskmodel.Task) error {
+	changes := taskmodel.DiffTask(before, task)
+
+	// occurrence yang diedit sendiri (selain status) tidak lagi ikut perubahan series
+	if task.RecurrenceID != nil {
+		for _, change := range changes {
+			if change.Field != "status" {
+				task.RecurrenceException = true
+			}
 		}
+	}
 
-		err = s.mailService.SendTaskAssignmentNotification(
-			assignee.Email,
-			existingTask.Title,
-			project.Nama,
-		)
+	return s.commitTaskChange(actor, task, changes, func(repo taskrepository.TaskRepository) error {
+		return repo.ReplacePrimaryAssignee(task.ID, before.AssigneeID, task.AssigneeID, false)
+	})
+}
 
-		if err != nil {
-			fmt.Printf("Gagal untuk mengirim notif: %v\n", err)
+// commitTaskChange menjalankan apply, menyimpan task dan history dalam satu transaksi lalu mengabari watcher
+func (s *taskService) commitTaskChange(actor changeActor, task *taskmodel.Task, changes []taskmodel.FieldChange, apply func(repo taskrepository.TaskRepository) error) error {
+	err := s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		if err := apply(repo); err != nil {
+			return err
 		}
+		if err := syncReview(actor, repo, task, changes); err != nil {
+			return err
+		}
+		if err := repo.UpdateTask(task); err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return repo.CreateTaskHistory(newHistory(actor, taskmodel.HistoryUpdated, task, changes))
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		s.notifyWatchers(actor, task, changes)
+		s.publish(actor, taskmodel.HistoryUpdated, task, changes)
+	}
+	return nil
+}
+
+func (s *taskService) notifyAssignee(task *taskmodel.Task) error {
+	assignee, err := s.taskRepo.GetUserByID(*task.AssigneeID)
+	if err != nil {
+		return fmt.Errorf("Gagal mengambil detail assignee: %v", err)
+	}
+
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		return fmt.Errorf("Gagal mengambil detail projek: %v", err)
+	}
+
+	err = s.mailService.SendTaskAssignmentNotification(
+		assignee.Email,
+		task.Title,
+		project.Nama,
+	)
+
+	if err != nil {
+		fmt.Printf("Gagal untuk mengirim notif: %v\n", err)
 	}
 
-	return s.convertToResponse(existingTask), nil
+	return nil
 }
 
 func (s *taskService) DeleteTask(ctx *gin.Context) error {
@@ -267,11 +463,35 @@ func (s *taskService) DeleteTask(ctx *gin.Context) error {
 	if err := s.validateProjectManager(ctx, task.ProjectID); err != nil {
 		return err
 	}
+	if err := ensureWritable(task); err != nil {
+		return err
+	}
+
+	err = s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		if err := repo.DeleteTask(taskUUID, actorID(ctx)); err != nil {
+			return err
+		}
+		return repo.CreateTaskHistory(newHistory(requestActor(ctx), taskmodel.HistoryDeleted, task, nil))
+	})
+	if err != nil {
+		return err
+	}
 
-	return s.taskRepo.DeleteTask(taskUUID)
+	s.publish(requestActor(ctx), taskmodel.HistoryDeleted, task, nil)
+	return nil
 }
 
 func (s *taskService) convertToResponse(task *taskmodel.Task) *taskmodel.TaskResponse {
+	// sisa estimasi hanya dihitung jika task punya estimasi awal, tidak pernah negatif
+	var remaining *int
+	if task.OriginalEstimateMinutes != nil {
+		left := *task.OriginalEstimateMinutes - task.TimeSpentMinutes
+		if left < 0 {
+			left = 0
+		}
+		remaining = &left
+	}
+
 	return &taskmodel.TaskResponse{
 		ID:          task.ID,
 		ProjectID:   task.ProjectID,
@@ -280,10 +500,35 @@ func (s *taskService) convertToResponse(task *taskmodel.Task) *taskmodel.TaskRes
 		Status:      task.Status,
 		AssigneeID:  task.AssigneeID,
 		DueDate:     task.DueDate,
+		StartDate:   task.StartDate,
+		Version:     task.Version,
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
-		Assignee:    task.Assignee,
-		Project:     &task.Project,
+		Position:    task.Position,
+
+		RecurrenceID:        task.RecurrenceID,
+		OccurrenceDate:      task.OccurrenceDate,
+		RecurrenceException: task.RecurrenceException,
+
+		OriginalEstimateMinutes:  task.OriginalEstimateMinutes,
+		TimeSpentMinutes:         task.TimeSpentMinutes,
+		RemainingEstimateMinutes: remaining,
+
+		ChecklistTotal: task.ChecklistTotal,
+		ChecklistDone:  task.ChecklistDone,
+
+		SprintID:    task.SprintID,
+		MilestoneID: task.MilestoneID,
+
+		ArchivedAt:      task.ArchivedAt,
+		ArchivedBy:      task.ArchivedBy,
+		ProjectArchived: task.ProjectArchived,
+		ReadOnly:        task.IsReadOnly(),
+
+		Assignee:  task.Assignee,
+		Assignees: task.Assignees,
+		Watchers:  task.Watchers,
+		Project:   &task.Project,
 	}
 }
 
@@ -300,30 +545,25 @@ func (s *taskService) GetMyTasks(c *gin.Context) {
 		return
 	}
 
-	tasks, err := s.taskRepo.GetTasksByAssigneeID(userUUID)
+	filter, err := parseTaskFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	// my-tasks selalu milik user yang login
+	filter.AssigneeID = nil
 
-	// Debug: Cek apakah project data ada
-	if len(tasks) > 0 {
-		fmt.Printf("🔍 First task Project data in repo: %+v\n", tasks[0].Project)
-		fmt.Printf("🔍 First task Project Name: %s\n", tasks[0].Project.Nama)
+	tasks, nextCursor, err := s.taskRepo.GetTasksByAssigneeID(userUUID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks: " + err.Error()})
+		return
 	}
 
-	// Convert to response
-	var response []taskmodel.TaskResponse
-	for _, task := range tasks {
-		taskResponse := s.convertToResponse(&task)
-		response = append(response, *taskResponse)
-
-		// Debug setiap task
-		fmt.Printf("📦 Task %s - Project in response: %+v\n", task.ID, taskResponse.Project)
-	}
+	page := s.buildTaskPage(tasks, nextCursor)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Tasks retrieved successfully",
-		"tasks":   response,
+		"message":     "Tasks retrieved successfully",
+		"tasks":       page.Tasks,
+		"next_cursor": page.NextCursor,
 	})
 }
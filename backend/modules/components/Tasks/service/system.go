@@ -0,0 +1,55 @@
+package taskservice
+
+import (
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateTaskAsSystem mengubah task atas nama sistem (misalnya automation rule) tanpa pemeriksaan peran user. mutate
+// menerima task terbaru, hasilnya tetap melewati validasi status dan member project serta sinkronisasi review.
+// Event yang dipublish punya ActorID nil dan Source sesuai source, sehingga subscriber bisa mengenali asalnya
+func (s *taskService) UpdateTaskAsSystem(taskID uuid.UUID, source string, mutate func(task *taskmodel.Task) error) (*taskmodel.Task, []taskmodel.FieldChange, error) {
+	task, err := s.taskRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ensureWritable(task); err != nil {
+		return nil, nil, err
+	}
+
+	before := *task
+	if err := mutate(task); err != nil {
+		return nil, nil, err
+	}
+
+	if !validTaskStatuses[task.Status] {
+		return nil, nil, fmt.Errorf("status tidak valid: %s", task.Status)
+	}
+	assigneeChanged := task.AssigneeID != nil && (before.AssigneeID == nil || *before.AssigneeID != *task.AssigneeID)
+	if assigneeChanged {
+		if err := s.validateProjectMember(task.ProjectID, *task.AssigneeID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changes := taskmodel.DiffTask(&before, task)
+	if len(changes) == 0 {
+		return task, nil, nil
+	}
+	task.UpdatedAt = time.Now()
+
+	// history, review dan event tercatat tanpa actor dengan source sebagai sumber perubahan
+	if err := s.saveTaskUpdate(changeActor{Source: source}, &before, task); err != nil {
+		return nil, nil, err
+	}
+
+	if assigneeChanged {
+		if err := s.notifyAssignee(task); err != nil {
+			fmt.Printf("Gagal untuk mengirim notif ke assignee: %v\n", err)
+		}
+	}
+	return task, changes, nil
+}
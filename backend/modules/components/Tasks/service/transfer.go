@@ -0,0 +1,258 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	rank "gintugas/modules/components/Rank"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	attachmentmodel "gintugas/modules/components/attachments/models"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// validateAssigneesIn memastikan semua assignee task adalah member project tujuan
+func (s *taskService) validateAssigneesIn(task *taskmodel.Task, projectID uuid.UUID) error {
+	for _, assignee := range task.Assignees {
+		isMember, err := s.taskRepo.IsProjectMember(projectID, assignee.UserID)
+		if err != nil {
+			return fmt.Errorf("gagal memeriksa member project: %v", err)
+		}
+		if !isMember {
+			return fmt.Errorf("assignee %s bukan member project tujuan", assignee.UserID)
+		}
+	}
+	return nil
+}
+
+// MoveTaskToProject memindahkan task ke project lain. Comment, attachment, checklist dan time log ikut karena terhubung ke task,
+// sedangkan sprint, milestone dan series recurrence milik project lama dilepas. Watcher dan assignee item checklist
+// yang bukan member project tujuan ikut dilepas, assignee task harus sudah menjadi member project tujuan
+func (s *taskService) MoveTaskToProject(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	task, err := s.loadManagedTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.MoveToProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	if req.ProjectID == task.ProjectID {
+		return nil, errors.New("task sudah berada di project tersebut")
+	}
+	if err := s.validateProjectManager(ctx, req.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureProjectWritable(req.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := s.validateAssigneesIn(task, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	before := *task
+	task.ProjectID = req.ProjectID
+	task.SprintID = nil
+	task.MilestoneID = nil
+	task.RecurrenceID = nil
+	task.OccurrenceDate = nil
+	task.RecurrenceException = false
+	task.UpdatedAt = time.Now()
+
+	last, err := s.taskRepo.GetLastTaskPosition(req.ProjectID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Position, err = rank.Between(last, ""); err != nil {
+		return nil, err
+	}
+
+	changes := append([]taskmodel.FieldChange{{
+		Field: "project_id",
+		Old:   before.ProjectID.String(),
+		New:   task.ProjectID.String(),
+	}}, taskmodel.DiffTask(&before, task)...)
+
+	err = s.commitTaskChange(requestActor(ctx), task, changes, func(repo taskrepository.TaskRepository) error {
+		return repo.DetachNonMembers(task.ID, req.ProjectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadTask(task)
+}
+
+// CloneTask membuat salinan task sebagai task baru berstatus todo di paling bawah board project tujuan.
+// Task yang diarsipkan tetap bisa disalin, tetapi project tujuan harus aktif
+func (s *taskService) CloneTask(ctx *gin.Context) (*taskmodel.TaskResponse, error) {
+	source, err := s.loadTaskAsManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.CloneTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	targetProject := source.ProjectID
+	if req.ProjectID != nil && *req.ProjectID != source.ProjectID {
+		if err := s.validateProjectManager(ctx, *req.ProjectID); err != nil {
+			return nil, err
+		}
+		targetProject = *req.ProjectID
+	}
+	sameProject := targetProject == source.ProjectID
+	if err := s.ensureProjectWritable(targetProject); err != nil {
+		return nil, err
+	}
+
+	if req.IncludeAssignees {
+		if err := s.validateAssigneesIn(source, targetProject); err != nil {
+			return nil, err
+		}
+	}
+
+	task := &taskmodel.Task{
+		ProjectID:   targetProject,
+		Title:       source.Title,
+		Description: source.Description,
+		Status:      "todo",
+		DueDate:     source.DueDate,
+		StartDate:   source.StartDate,
+
+		OriginalEstimateMinutes: source.OriginalEstimateMinutes,
+	}
+	if req.Title != "" {
+		task.Title = req.Title
+	}
+	if sameProject {
+		task.SprintID = source.SprintID
+		task.MilestoneID = source.MilestoneID
+		// sprint yang sudah ditutup tidak bisa menerima task baru
+		if err := s.validatePlanning(targetProject, task.SprintID, nil); err != nil {
+			task.SprintID = nil
+		}
+	}
+	if req.IncludeAssignees {
+		task.AssigneeID = source.AssigneeID
+	}
+
+	var copiedFiles []string
+	if req.IncludeAttachments {
+		defer func() {
+			// file salinan hanya dipertahankan jika transaksi berhasil
+			for _, path := range copiedFiles {
+				os.Remove(path)
+			}
+		}()
+	}
+
+	err = s.taskRepo.Transaction(func(repo taskrepository.TaskRepository) error {
+		if err := repo.CreateTask(task); err != nil {
+			return err
+		}
+
+		if req.IncludeAssignees {
+			for _, assignee := range source.Assignees {
+				err := repo.UpsertTaskAssignee(&taskmodel.TaskAssignee{
+					TaskID:    task.ID,
+					UserID:    assignee.UserID,
+					IsPrimary: assignee.IsPrimary,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if req.IncludeChecklist {
+			if err := repo.CopyChecklistItems(source.ID, task.ID, sameProject); err != nil {
+				return err
+			}
+		}
+
+		if req.IncludeComments {
+			if err := repo.CopyTaskComments(source.ID, task.ID); err != nil {
+				return err
+			}
+		}
+
+		if req.IncludeAttachments {
+			attachments, err := repo.GetTaskAttachments(source.ID)
+			if err != nil {
+				return err
+			}
+			for _, attachment := range attachments {
+				path, err := copyAttachmentFile(attachment.FilePath)
+				if err != nil {
+					return fmt.Errorf("gagal menyalin file %s: %v", attachment.FileName, err)
+				}
+				copiedFiles = append(copiedFiles, path)
+
+				err = repo.CreateAttachment(&attachmentmodel.Attachment{
+					TaskID:     task.ID,
+					FilePath:   path,
+					FileName:   attachment.FileName,
+					FileSize:   attachment.FileSize,
+					MimeType:   attachment.MimeType,
+					UploadedBy: attachment.UploadedBy,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return repo.CreateTaskHistory(newHistory(requestActor(ctx), taskmodel.HistoryCreated, task, taskmodel.DiffTask(nil, task)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	copiedFiles = nil
+
+	s.publish(requestActor(ctx), taskmodel.HistoryCreated, task, taskmodel.DiffTask(nil, task))
+
+	if task.AssigneeID != nil {
+		if err := s.notifyAssignee(task); err != nil {
+			fmt.Printf("gagal untuk mengirim email notifikasi: %v\n", err)
+		}
+	}
+
+	return s.reloadTask(task)
+}
+
+// copyAttachmentFile menyalin file attachment ke folder yang sama dengan nama baru,
+// karena menghapus attachment juga menghapus file fisiknya
+func copyAttachmentFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	target := filepath.Join(filepath.Dir(path), uuid.New().String()+filepath.Ext(path))
+	dst, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(target)
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(target)
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,322 @@
+package taskservice
+
+import (
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// viewerID mengembalikan user yang login, saved view selalu dibaca dari sudut pandang user ini
+func viewerID(ctx *gin.Context) (uuid.UUID, error) {
+	currentUser := actorID(ctx)
+	if currentUser == nil {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+	return *currentUser, nil
+}
+
+// loadSavedView mengambil view dari path, view private milik user lain dianggap tidak ada
+func (s *taskService) loadSavedView(ctx *gin.Context) (*taskmodel.SavedView, uuid.UUID, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	viewID, err := uuid.Parse(ctx.Param("view_id"))
+	if err != nil {
+		return nil, uuid.Nil, errors.New("Gagal format view ID")
+	}
+
+	view, err := s.taskRepo.GetSavedView(viewID, userID)
+	if err != nil {
+		return nil, uuid.Nil, errors.New("saved view tidak ditemukan")
+	}
+	return view, userID, nil
+}
+
+// viewFilter menyusun TaskFilter dari filter saved view dengan validasi yang sama seperti query listing task.
+// Cursor dan limit tetap berasal dari request karena bukan bagian dari view
+func viewFilter(view *taskmodel.SavedView, userID uuid.UUID, cursor string, limit int) (taskmodel.TaskFilter, error) {
+	filters := view.Filters
+	assigneeID := filters.AssigneeID
+	if assigneeID == "me" {
+		assigneeID = userID.String()
+	}
+
+	return buildTaskFilter(taskmodel.TaskListQuery{
+		Status:     filters.Status,
+		AssigneeID: assigneeID,
+		DueFrom:    filters.DueFrom,
+		DueTo:      filters.DueTo,
+		Overdue:    filters.Overdue,
+		Sprint:     filters.Sprint,
+		Milestone:  filters.Milestone,
+		Archived:   filters.Archived,
+		Search:     filters.Search,
+		Sort:       view.Sort,
+		Cursor:     cursor,
+		Limit:      limit,
+	})
+}
+
+// applyViewRequest mengisi view dari request setelah memastikan kombinasi scope, project, dan visibility valid
+func (s *taskService) applyViewRequest(view *taskmodel.SavedView, req taskmodel.SavedViewRequest, userID uuid.UUID) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("nama view wajib diisi")
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = taskmodel.ViewPrivate
+	}
+
+	if req.Scope == taskmodel.ViewScopeProject {
+		if req.ProjectID == nil {
+			return errors.New("project_id wajib diisi untuk scope project")
+		}
+		isMember, err := s.taskRepo.IsProjectMember(*req.ProjectID, userID)
+		if err != nil {
+			return fmt.Errorf("gagal memeriksa member project: %v", err)
+		}
+		if !isMember {
+			return errors.New("forbidden: hanya manager atau member project yang bisa membuat view untuk project ini")
+		}
+	} else {
+		if req.ProjectID != nil {
+			return fmt.Errorf("project_id hanya dipakai untuk scope project, bukan %s", req.Scope)
+		}
+		if visibility == taskmodel.ViewShared {
+			return errors.New("hanya view dengan scope project yang bisa dibagikan")
+		}
+	}
+
+	if req.Scope == taskmodel.ViewScopeMyTasks && req.Filters.AssigneeID != "" {
+		return errors.New("assignee_id tidak bisa dipakai pada scope my_tasks")
+	}
+
+	view.Name = name
+	view.Scope = req.Scope
+	view.ProjectID = req.ProjectID
+	view.Visibility = visibility
+	view.Filters = req.Filters
+	view.Filters.Search = strings.TrimSpace(view.Filters.Search)
+	view.Sort = strings.TrimSpace(req.Sort)
+
+	_, err := viewFilter(view, userID, "", 0)
+	return err
+}
+
+// fillPinnedOn mengisi PinnedOn setiap view dengan dashboard tempat view tersebut dipasang oleh userID
+func (s *taskService) fillPinnedOn(userID uuid.UUID, views ...*taskmodel.SavedView) error {
+	pins, err := s.taskRepo.GetSavedViewPins(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		view.PinnedOn = []string{}
+		for _, pin := range pins {
+			if pin.ViewID == view.ID {
+				view.PinnedOn = append(view.PinnedOn, pin.Dashboard)
+			}
+		}
+	}
+	return nil
+}
+
+// GetSavedViews mengembalikan view milik user dan view shared di project-nya, query project_id membatasi ke satu project
+func (s *taskService) GetSavedViews(ctx *gin.Context) ([]taskmodel.SavedView, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectID *uuid.UUID
+	if value := ctx.Query("project_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errors.New("project_id tidak valid")
+		}
+		projectID = &parsed
+	}
+
+	views, err := s.taskRepo.GetSavedViews(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pointers := make([]*taskmodel.SavedView, len(views))
+	for i := range views {
+		pointers[i] = &views[i]
+	}
+	if err := s.fillPinnedOn(userID, pointers...); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (s *taskService) CreateSavedView(ctx *gin.Context) (*taskmodel.SavedView, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.SavedViewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	view := &taskmodel.SavedView{OwnerID: userID}
+	if err := s.applyViewRequest(view, req, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.CreateSavedView(view); err != nil {
+		return nil, err
+	}
+	view.PinnedOn = []string{}
+	return view, nil
+}
+
+// UpdateSavedView hanya untuk pemilik view. View yang diubah menjadi private hilang dari dashboard user lain
+func (s *taskService) UpdateSavedView(ctx *gin.Context) (*taskmodel.SavedView, error) {
+	view, userID, err := s.loadSavedView(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if view.OwnerID != userID {
+		return nil, errors.New("forbidden: hanya pemilik view yang bisa mengubah view ini")
+	}
+
+	var req taskmodel.SavedViewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := s.applyViewRequest(view, req, userID); err != nil {
+		return nil, err
+	}
+	view.UpdatedAt = time.Now()
+
+	if err := s.taskRepo.UpdateSavedView(view); err != nil {
+		return nil, err
+	}
+	if err := s.fillPinnedOn(userID, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// DeleteSavedView: selain pemiliknya, manager project boleh menghapus view yang dibagikan di project-nya
+func (s *taskService) DeleteSavedView(ctx *gin.Context) error {
+	view, userID, err := s.loadSavedView(ctx)
+	if err != nil {
+		return err
+	}
+
+	if view.OwnerID != userID {
+		if view.Visibility != taskmodel.ViewShared || view.ProjectID == nil {
+			return errors.New("forbidden: hanya pemilik view yang bisa menghapus view ini")
+		}
+		if err := s.validateProjectManager(ctx, *view.ProjectID); err != nil {
+			return errors.New("forbidden: hanya pemilik view atau manager project yang bisa menghapus view ini")
+		}
+	}
+
+	return s.taskRepo.DeleteSavedView(view.ID)
+}
+
+// GetSavedViewTasks menjalankan view: filter dan sort dari view, cursor dan limit dari query request
+func (s *taskService) GetSavedViewTasks(ctx *gin.Context) (*taskmodel.TaskPage, error) {
+	view, userID, err := s.loadSavedView(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var query taskmodel.TaskListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, errors.New("query parameter tidak valid: " + err.Error())
+	}
+	if query.Limit < 0 {
+		return nil, errors.New("limit tidak boleh negatif")
+	}
+
+	filter, err := viewFilter(view, userID, query.Cursor, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []taskmodel.Task
+	var nextCursor string
+	switch view.Scope {
+	case taskmodel.ViewScopeProject:
+		// pemilik view private bisa saja sudah keluar dari project-nya
+		if err := s.validateProjectMember(*view.ProjectID, userID); err != nil {
+			return nil, errors.New("forbidden: kamu bukan lagi manager atau member project view ini")
+		}
+		tasks, nextCursor, err = s.taskRepo.GetTasksByProjectID(*view.ProjectID, filter)
+	case taskmodel.ViewScopeMyProjects:
+		tasks, nextCursor, err = s.taskRepo.GetTasksByMemberID(userID, filter)
+	default:
+		filter.AssigneeID = nil
+		tasks, nextCursor, err = s.taskRepo.GetTasksByAssigneeID(userID, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildTaskPage(tasks, nextCursor), nil
+}
+
+// PinSavedView memasang view sebagai default dashboard user, menggantikan view default sebelumnya.
+// Dashboard manager hanya untuk admin dan manager
+func (s *taskService) PinSavedView(ctx *gin.Context) (*taskmodel.SavedView, error) {
+	view, userID, err := s.loadSavedView(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req taskmodel.PinViewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	role := ctx.GetString("user_role")
+	if req.Dashboard == taskmodel.DashboardManager && role != "admin" && role != "manager" {
+		return nil, errors.New("forbidden: dashboard manager hanya untuk admin dan manager")
+	}
+
+	err = s.taskRepo.PinSavedView(&taskmodel.SavedViewPin{
+		UserID:    userID,
+		Dashboard: req.Dashboard,
+		ViewID:    view.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillPinnedOn(userID, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// UnpinSavedView melepas view dari dashboard pada query dashboard, tanpa query dari semua dashboard user
+func (s *taskService) UnpinSavedView(ctx *gin.Context) error {
+	view, userID, err := s.loadSavedView(ctx)
+	if err != nil {
+		return err
+	}
+
+	dashboard := ctx.Query("dashboard")
+	if dashboard != "" && dashboard != taskmodel.DashboardManager && dashboard != taskmodel.DashboardStaff {
+		return fmt.Errorf("dashboard tidak valid: %s", dashboard)
+	}
+
+	return s.taskRepo.UnpinSavedView(userID, view.ID, dashboard)
+}
@@ -0,0 +1,78 @@
+package templatemodel
+
+import (
+	projectmodel "gintugas/modules/components/Project/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTemplate menyimpan kumpulan task yang bisa dipakai ulang untuk membuat project baru
+type ProjectTemplate struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string     `json:"name" gorm:"type:varchar(150);not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	CreatedBy   *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Tasks []TemplateTask `json:"tasks" gorm:"foreignKey:TemplateID"`
+	// Placeholders adalah daftar assignee_placeholder unik yang harus dipetakan saat instantiate
+	Placeholders []string `json:"placeholders" gorm:"-"`
+}
+
+func (ProjectTemplate) TableName() string {
+	return "project_templates"
+}
+
+// TemplateTask: DueOffsetDays dihitung dari start_date project, AssigneePlaceholder diganti user saat instantiate
+type TemplateTask struct {
+	ID                      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TemplateID              uuid.UUID `json:"template_id" gorm:"type:uuid;not null"`
+	Title                   string    `json:"title" gorm:"type:varchar(200);not null"`
+	Description             string    `json:"description" gorm:"type:text"`
+	DueOffsetDays           *int      `json:"due_offset_days"`
+	AssigneePlaceholder     *string   `json:"assignee_placeholder" gorm:"type:varchar(50)"`
+	OriginalEstimateMinutes *int      `json:"original_estimate_minutes"`
+	SortOrder               int       `json:"sort_order" gorm:"not null;default:0"`
+}
+
+func (TemplateTask) TableName() string {
+	return "template_tasks"
+}
+
+type TemplateRequest struct {
+	Name        string                `json:"name" binding:"required,max=150"`
+	Description string                `json:"description"`
+	Tasks       []TemplateTaskRequest `json:"tasks" binding:"required,min=1,dive"`
+}
+
+type TemplateTaskRequest struct {
+	Title                   string  `json:"title" binding:"required,max=200"`
+	Description             string  `json:"description"`
+	DueOffsetDays           *int    `json:"due_offset_days" binding:"omitempty,min=0"`
+	AssigneePlaceholder     *string `json:"assignee_placeholder" binding:"omitempty,max=50"`
+	OriginalEstimateMinutes *int    `json:"original_estimate_minutes" binding:"omitempty,min=0"`
+}
+
+// SaveAsTemplateRequest membuat template dari task project yang sudah ada
+type SaveAsTemplateRequest struct {
+	Name        string `json:"name" binding:"required,max=150"`
+	Description string `json:"description"`
+}
+
+// InstantiateRequest: Assignees memetakan placeholder ke user, placeholder yang tidak dipetakan menjadi task tanpa assignee.
+// Member dan user di Assignees otomatis ditambahkan sebagai member project
+type InstantiateRequest struct {
+	Name        string               `json:"name" binding:"required,max=150"`
+	Description string               `json:"description"`
+	StartDate   string               `json:"start_date"` // YYYY-MM-DD, default hari ini
+	Members     []uuid.UUID          `json:"members"`
+	Assignees   map[string]uuid.UUID `json:"assignees"`
+}
+
+type InstantiateResult struct {
+	Project   projectmodel.Project `json:"project"`
+	Members   []uuid.UUID          `json:"members"`
+	TaskCount int                  `json:"task_count"`
+}
@@ -0,0 +1,118 @@
+package templaterepository
+
+import (
+	projectmodel "gintugas/modules/components/Project/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	templatemodel "gintugas/modules/components/Templates/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TemplateRepository interface {
+	CreateTemplate(template *templatemodel.ProjectTemplate) error
+	GetTemplates() ([]templatemodel.ProjectTemplate, error)
+	GetTemplateByID(id uuid.UUID) (*templatemodel.ProjectTemplate, error)
+	UpdateTemplate(template *templatemodel.ProjectTemplate) error
+	DeleteTemplate(id uuid.UUID) error
+	// ReplaceTemplateTasks menghapus semua task template lalu menyimpan tasks sesuai urutannya
+	ReplaceTemplateTasks(templateID uuid.UUID, tasks []templatemodel.TemplateTask) error
+
+	// GetProjectTasks mengambil task project sesuai urutan board untuk disimpan sebagai template
+	GetProjectTasks(projectID uuid.UUID) ([]taskmodel.Task, error)
+	CreateProject(project *projectmodel.Project) error
+	AddProjectMember(projectID uuid.UUID, userID uuid.UUID) error
+
+	// Transaction memberi repository template dan task yang memakai transaksi yang sama
+	Transaction(fn func(repo TemplateRepository, tasks taskrepository.TaskRepository) error) error
+}
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func orderTemplateTasks(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order ASC")
+}
+
+func (r *templateRepository) CreateTemplate(template *templatemodel.ProjectTemplate) error {
+	return r.db.Omit(clause.Associations).Create(template).Error
+}
+
+func (r *templateRepository) GetTemplates() ([]templatemodel.ProjectTemplate, error) {
+	templates := []templatemodel.ProjectTemplate{}
+	err := r.db.Preload("Tasks", orderTemplateTasks).
+		Order("name ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (r *templateRepository) GetTemplateByID(id uuid.UUID) (*templatemodel.ProjectTemplate, error) {
+	var template templatemodel.ProjectTemplate
+	err := r.db.Where("id = ?", id).
+		Preload("Tasks", orderTemplateTasks).
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) UpdateTemplate(template *templatemodel.ProjectTemplate) error {
+	template.UpdatedAt = time.Now()
+	return r.db.Model(template).
+		Select("*").
+		Omit("id", "created_by", "created_at", clause.Associations).
+		Updates(template).Error
+}
+
+func (r *templateRepository) DeleteTemplate(id uuid.UUID) error {
+	return r.db.Delete(&templatemodel.ProjectTemplate{}, "id = ?", id).Error
+}
+
+func (r *templateRepository) ReplaceTemplateTasks(templateID uuid.UUID, tasks []templatemodel.TemplateTask) error {
+	if err := r.db.Where("template_id = ?", templateID).Delete(&templatemodel.TemplateTask{}).Error; err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	for i := range tasks {
+		tasks[i].TemplateID = templateID
+		tasks[i].SortOrder = i
+	}
+	return r.db.Create(&tasks).Error
+}
+
+func (r *templateRepository) GetProjectTasks(projectID uuid.UUID) ([]taskmodel.Task, error) {
+	var tasks []taskmodel.Task
+	err := r.db.Where("project_id = ?", projectID).
+		Preload("Assignee").
+		Order("position ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *templateRepository) CreateProject(project *projectmodel.Project) error {
+	return r.db.Omit(clause.Associations).Create(project).Error
+}
+
+func (r *templateRepository) AddProjectMember(projectID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Create(&projectmodel.ProjectMember{
+		ProjectID: projectID.String(),
+		UserID:    userID.String(),
+	}).Error
+}
+
+func (r *templateRepository) Transaction(fn func(repo TemplateRepository, tasks taskrepository.TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&templateRepository{db: tx}, taskrepository.NewTaskRepository(tx))
+	})
+}
@@ -0,0 +1,484 @@
+package templateservice
+
+import (
+	"errors"
+	"fmt"
+	projectmodel "gintugas/modules/components/Project/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	templatemodel "gintugas/modules/components/Templates/model"
+	templaterepository "gintugas/modules/components/Templates/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TemplateService interface {
+	CreateTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error)
+	GetTemplates(ctx *gin.Context) ([]templatemodel.ProjectTemplate, error)
+	GetTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error)
+	UpdateTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error)
+	DeleteTemplate(ctx *gin.Context) error
+	SaveProjectAsTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error)
+	InstantiateTemplate(ctx *gin.Context) (*templatemodel.InstantiateResult, error)
+}
+
+type templateService struct {
+	repo     templaterepository.TemplateRepository
+	taskRepo taskrepository.TaskRepository
+	events   taskmodel.TaskEventPublisher
+}
+
+func NewTemplateService(repo templaterepository.TemplateRepository, taskRepo taskrepository.TaskRepository, events taskmodel.TaskEventPublisher) TemplateService {
+	return &templateService{
+		repo:     repo,
+		taskRepo: taskRepo,
+		events:   events,
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+func (s *templateService) validateProjectManager(ctx *gin.Context, projectID uuid.UUID) error {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	if project.ManagerID != userUUID {
+		return errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	return nil
+}
+
+// validateTemplateOwner: template dipakai bersama semua manager, tapi hanya pembuatnya atau admin yang bisa mengubah
+func validateTemplateOwner(ctx *gin.Context, template *templatemodel.ProjectTemplate) error {
+	if ctx.GetString("user_role") == "admin" {
+		return nil
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if template.CreatedBy == nil || *template.CreatedBy != userUUID {
+		return errors.New("forbidden: hanya pembuat template atau admin yang bisa mengubah template")
+	}
+	return nil
+}
+
+func (s *templateService) loadTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error) {
+	templateUUID, err := uuid.Parse(ctx.Param("template_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format template ID")
+	}
+
+	template, err := s.repo.GetTemplateByID(templateUUID)
+	if err != nil {
+		return nil, errors.New("template tidak ditemukan")
+	}
+	return withPlaceholders(template), nil
+}
+
+// withPlaceholders mengisi daftar placeholder unik dari task template, terurut alfabet
+func withPlaceholders(template *templatemodel.ProjectTemplate) *templatemodel.ProjectTemplate {
+	seen := map[string]bool{}
+	template.Placeholders = []string{}
+	for _, task := range template.Tasks {
+		if task.AssigneePlaceholder == nil || seen[*task.AssigneePlaceholder] {
+			continue
+		}
+		seen[*task.AssigneePlaceholder] = true
+		template.Placeholders = append(template.Placeholders, *task.AssigneePlaceholder)
+	}
+	sort.Strings(template.Placeholders)
+	return template
+}
+
+func buildTemplateTasks(requests []templatemodel.TemplateTaskRequest) ([]templatemodel.TemplateTask, error) {
+	tasks := make([]templatemodel.TemplateTask, 0, len(requests))
+	for i, req := range requests {
+		title := strings.TrimSpace(req.Title)
+		if title == "" {
+			return nil, fmt.Errorf("title task ke-%d tidak boleh kosong", i+1)
+		}
+
+		var placeholder *string
+		if req.AssigneePlaceholder != nil {
+			if value := strings.TrimSpace(*req.AssigneePlaceholder); value != "" {
+				placeholder = &value
+			}
+		}
+
+		tasks = append(tasks, templatemodel.TemplateTask{
+			Title:                   title,
+			Description:             req.Description,
+			DueOffsetDays:           req.DueOffsetDays,
+			AssigneePlaceholder:     placeholder,
+			OriginalEstimateMinutes: req.OriginalEstimateMinutes,
+		})
+	}
+	return tasks, nil
+}
+
+func validateTemplateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name tidak boleh kosong")
+	}
+	return name, nil
+}
+
+func (s *templateService) saveTemplate(template *templatemodel.ProjectTemplate, tasks []templatemodel.TemplateTask, create bool) (*templatemodel.ProjectTemplate, error) {
+	err := s.repo.Transaction(func(repo templaterepository.TemplateRepository, _ taskrepository.TaskRepository) error {
+		if create {
+			if err := repo.CreateTemplate(template); err != nil {
+				return err
+			}
+		} else if err := repo.UpdateTemplate(template); err != nil {
+			return err
+		}
+		return repo.ReplaceTemplateTasks(template.ID, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.GetTemplateByID(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	return withPlaceholders(saved), nil
+}
+
+func (s *templateService) CreateTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error) {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req templatemodel.TemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTemplateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := buildTemplateTasks(req.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &templatemodel.ProjectTemplate{
+		Name:        name,
+		Description: req.Description,
+		CreatedBy:   &userUUID,
+	}
+	return s.saveTemplate(template, tasks, true)
+}
+
+func (s *templateService) GetTemplates(ctx *gin.Context) ([]templatemodel.ProjectTemplate, error) {
+	templates, err := s.repo.GetTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		withPlaceholders(&templates[i])
+	}
+	return templates, nil
+}
+
+func (s *templateService) GetTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error) {
+	return s.loadTemplate(ctx)
+}
+
+// UpdateTemplate mengganti name, description dan seluruh daftar task template
+func (s *templateService) UpdateTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error) {
+	template, err := s.loadTemplate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTemplateOwner(ctx, template); err != nil {
+		return nil, err
+	}
+
+	var req templatemodel.TemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	if template.Name, err = validateTemplateName(req.Name); err != nil {
+		return nil, err
+	}
+	tasks, err := buildTemplateTasks(req.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	template.Description = req.Description
+
+	return s.saveTemplate(template, tasks, false)
+}
+
+func (s *templateService) DeleteTemplate(ctx *gin.Context) error {
+	template, err := s.loadTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	if err := validateTemplateOwner(ctx, template); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTemplate(template.ID)
+}
+
+// SaveProjectAsTemplate menyimpan task project sebagai template. Offset due date dihitung dari tanggal project dibuat
+// dan assignee utama menjadi placeholder dengan nama username-nya
+func (s *templateService) SaveProjectAsTemplate(ctx *gin.Context) (*templatemodel.ProjectTemplate, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	if err := s.validateProjectManager(ctx, projectUUID); err != nil {
+		return nil, err
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req templatemodel.SaveAsTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTemplateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectUUID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+
+	projectTasks, err := s.repo.GetProjectTasks(projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	if len(projectTasks) == 0 {
+		return nil, errors.New("project belum memiliki task untuk disimpan sebagai template")
+	}
+
+	start := dateOnly(project.CreatedAt)
+	tasks := make([]templatemodel.TemplateTask, 0, len(projectTasks))
+	for _, task := range projectTasks {
+		templateTask := templatemodel.TemplateTask{
+			Title:                   task.Title,
+			Description:             task.Description,
+			OriginalEstimateMinutes: task.OriginalEstimateMinutes,
+		}
+		if task.DueDate != nil {
+			offset := int(dateOnly(*task.DueDate).Sub(start).Hours() / 24)
+			if offset < 0 {
+				offset = 0
+			}
+			templateTask.DueOffsetDays = &offset
+		}
+		if task.Assignee != nil {
+			placeholder := task.Assignee.Username
+			templateTask.AssigneePlaceholder = &placeholder
+		}
+		tasks = append(tasks, templateTask)
+	}
+
+	template := &templatemodel.ProjectTemplate{
+		Name:        name,
+		Description: req.Description,
+		CreatedBy:   &userUUID,
+	}
+	if template.Description == "" {
+		template.Description = project.Description
+	}
+	return s.saveTemplate(template, tasks, true)
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// InstantiateTemplate membuat project, member dan semua task template dalam satu transaksi.
+// User yang membuat menjadi manager project
+func (s *templateService) InstantiateTemplate(ctx *gin.Context) (*templatemodel.InstantiateResult, error) {
+	template, err := s.loadTemplate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req templatemodel.InstantiateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTemplateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = template.Description
+	}
+	if description == "" {
+		return nil, errors.New("deskripsi projek harus diisi")
+	}
+
+	start := dateOnly(time.Now())
+	if req.StartDate != "" {
+		if start, err = time.Parse("2006-01-02", req.StartDate); err != nil {
+			return nil, errors.New("start_date harus berformat YYYY-MM-DD")
+		}
+	}
+
+	placeholders := map[string]bool{}
+	for _, placeholder := range template.Placeholders {
+		placeholders[placeholder] = true
+	}
+	for placeholder := range req.Assignees {
+		if !placeholders[placeholder] {
+			return nil, fmt.Errorf("placeholder %s tidak ada di template", placeholder)
+		}
+	}
+
+	// manager sudah dianggap member project, jadi tidak perlu ditambahkan ke project_members
+	members := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{userUUID: true}
+	addMember := func(id uuid.UUID) error {
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+		if _, err := s.taskRepo.GetUserByID(id); err != nil {
+			return fmt.Errorf("user %s tidak ditemukan", id)
+		}
+		members = append(members, id)
+		return nil
+	}
+	for _, id := range req.Members {
+		if err := addMember(id); err != nil {
+			return nil, err
+		}
+	}
+	for _, placeholder := range template.Placeholders {
+		if id, ok := req.Assignees[placeholder]; ok {
+			if err := addMember(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	project := &projectmodel.Project{
+		Nama:        name,
+		Description: description,
+		ManagerID:   userUUID,
+	}
+
+	created := []*taskmodel.Task{}
+	err = s.repo.Transaction(func(repo templaterepository.TemplateRepository, tasks taskrepository.TaskRepository) error {
+		if err := repo.CreateProject(project); err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := repo.AddProjectMember(project.ID, member); err != nil {
+				return err
+			}
+		}
+
+		for _, templateTask := range template.Tasks {
+			task := &taskmodel.Task{
+				ProjectID:   project.ID,
+				Title:       templateTask.Title,
+				Description: templateTask.Description,
+				Status:      "todo",
+
+				OriginalEstimateMinutes: templateTask.OriginalEstimateMinutes,
+			}
+			if templateTask.DueOffsetDays != nil {
+				dueDate := start.AddDate(0, 0, *templateTask.DueOffsetDays)
+				task.DueDate = &dueDate
+			}
+			if templateTask.AssigneePlaceholder != nil {
+				if id, ok := req.Assignees[*templateTask.AssigneePlaceholder]; ok {
+					task.AssigneeID = &id
+				}
+			}
+
+			if err := tasks.CreateTask(task); err != nil {
+				return err
+			}
+			if err := tasks.ReplacePrimaryAssignee(task.ID, nil, task.AssigneeID, false); err != nil {
+				return err
+			}
+			err := tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+				TaskID:    task.ID,
+				ProjectID: task.ProjectID,
+				TaskTitle: task.Title,
+				ActorID:   &userUUID,
+				Action:    taskmodel.HistoryCreated,
+				Changes:   taskmodel.DiffTask(nil, task),
+			})
+			if err != nil {
+				return err
+			}
+			created = append(created, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat project dari template: %v", err)
+	}
+
+	for _, task := range created {
+		s.events.PublishTaskEvent(taskmodel.TaskEvent{
+			Action:  taskmodel.HistoryCreated,
+			Task:    *task,
+			Changes: taskmodel.DiffTask(nil, task),
+			ActorID: &userUUID,
+			Source:  taskmodel.EventSourceTemplate,
+		})
+	}
+
+	return &templatemodel.InstantiateResult{
+		Project:   *project,
+		Members:   members,
+		TaskCount: len(template.Tasks),
+	}, nil
+}
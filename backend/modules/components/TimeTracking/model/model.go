@@ -0,0 +1,50 @@
+package timelogmodel
+
+import (
+	usermodels "gintugas/modules/components/Auth/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeLog adalah satu catatan waktu kerja di task. Entri dari timer punya StartedAt,
+// EndedAt kosong berarti timer masih berjalan dan durasinya belum dihitung
+type TimeLog struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TaskID          uuid.UUID  `json:"task_id" gorm:"type:uuid;not null"`
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	LogDate         time.Time  `json:"log_date" gorm:"type:date;not null"`
+	DurationMinutes int        `json:"duration_minutes" gorm:"not null;default:0"`
+	Note            string     `json:"note" gorm:"type:text"`
+	StartedAt       *time.Time `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *usermodels.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (TimeLog) TableName() string {
+	return "time_logs"
+}
+
+// IsRunning true selama timer belum dihentikan
+func (l *TimeLog) IsRunning() bool {
+	return l.StartedAt != nil && l.EndedAt == nil
+}
+
+type TimeLogRequest struct {
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1,max=1440"`
+	LogDate         string `json:"log_date"` // YYYY-MM-DD, default hari ini
+	Note            string `json:"note"`
+}
+
+type TimerRequest struct {
+	Note string `json:"note"`
+}
+
+type TaskTimeLogs struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	TotalMinutes int       `json:"total_minutes"`
+	TimeLogs     []TimeLog `json:"time_logs"`
+}
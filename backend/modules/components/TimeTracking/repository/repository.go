@@ -0,0 +1,73 @@
+package timelogrepository
+
+import (
+	timelogmodel "gintugas/modules/components/TimeTracking/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TimeLogRepository interface {
+	CreateTimeLog(log *timelogmodel.TimeLog) error
+	GetTimeLogByID(id uuid.UUID) (*timelogmodel.TimeLog, error)
+	GetTaskTimeLogs(taskID uuid.UUID) ([]timelogmodel.TimeLog, error)
+	UpdateTimeLog(log *timelogmodel.TimeLog) error
+	DeleteTimeLog(id uuid.UUID) error
+
+	// GetRunningTimer mengembalikan gorm.ErrRecordNotFound jika user tidak punya timer yang berjalan
+	GetRunningTimer(userID uuid.UUID) (*timelogmodel.TimeLog, error)
+}
+
+type timeLogRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeLogRepository(db *gorm.DB) TimeLogRepository {
+	return &timeLogRepository{db: db}
+}
+
+func (r *timeLogRepository) CreateTimeLog(log *timelogmodel.TimeLog) error {
+	return r.db.Omit(clause.Associations).Create(log).Error
+}
+
+func (r *timeLogRepository) GetTimeLogByID(id uuid.UUID) (*timelogmodel.TimeLog, error) {
+	var log timelogmodel.TimeLog
+	err := r.db.Where("id = ?", id).
+		Preload("User").
+		First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *timeLogRepository) GetTaskTimeLogs(taskID uuid.UUID) ([]timelogmodel.TimeLog, error) {
+	var logs []timelogmodel.TimeLog
+	err := r.db.Where("task_id = ?", taskID).
+		Preload("User").
+		Order("log_date DESC, created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *timeLogRepository) UpdateTimeLog(log *timelogmodel.TimeLog) error {
+	return r.db.Model(log).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(log).Error
+}
+
+func (r *timeLogRepository) DeleteTimeLog(id uuid.UUID) error {
+	return r.db.Delete(&timelogmodel.TimeLog{}, "id = ?", id).Error
+}
+
+func (r *timeLogRepository) GetRunningTimer(userID uuid.UUID) (*timelogmodel.TimeLog, error) {
+	var log timelogmodel.TimeLog
+	err := r.db.Where("user_id = ? AND started_at IS NOT NULL AND ended_at IS NULL", userID).
+		First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
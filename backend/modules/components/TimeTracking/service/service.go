@@ -0,0 +1,334 @@
+package timelogservice
+
+import (
+	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	timelogmodel "gintugas/modules/components/TimeTracking/model"
+	timelogrepository "gintugas/modules/components/TimeTracking/repository"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TimeLogService interface {
+	LogTime(ctx *gin.Context) (*timelogmodel.TimeLog, error)
+	GetTaskTimeLogs(ctx *gin.Context) (*timelogmodel.TaskTimeLogs, error)
+	DeleteTimeLog(ctx *gin.Context) error
+
+	StartTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error)
+	StopTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error)
+	GetRunningTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error)
+}
+
+type timeLogService struct {
+	repo     timelogrepository.TimeLogRepository
+	taskRepo taskrepository.TaskRepository
+}
+
+func NewTimeLogService(repo timelogrepository.TimeLogRepository, taskRepo taskrepository.TaskRepository) TimeLogService {
+	return &timeLogService{
+		repo:     repo,
+		taskRepo: taskRepo,
+	}
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+func (s *timeLogService) isProjectManager(projectID, userID uuid.UUID) (bool, error) {
+	project, err := s.taskRepo.GetProjectByID(projectID)
+	if err != nil {
+		return false, fmt.Errorf("gagal mengambil detail project: %v", err)
+	}
+	return project.ManagerID == userID, nil
+}
+
+// loadLoggableTask mengambil task dari path, waktu hanya boleh dicatat oleh assignee task atau manager project
+func (s *timeLogService) loadLoggableTask(ctx *gin.Context) (*taskmodel.Task, uuid.UUID, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, uuid.Nil, errors.New("Gagal format task ID")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	isAssignee, err := s.taskRepo.IsTaskAssignee(task.ID, userUUID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	if !isAssignee {
+		isManager, err := s.isProjectManager(task.ProjectID, userUUID)
+		if err != nil {
+			return nil, uuid.Nil, err
+		}
+		if !isManager {
+			return nil, uuid.Nil, errors.New("forbidden: hanya assignee task atau manager project yang bisa mencatat waktu")
+		}
+	}
+
+	return task, userUUID, nil
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// LogTime mencatat waktu kerja secara manual
+func (s *timeLogService) LogTime(ctx *gin.Context) (*timelogmodel.TimeLog, error) {
+	task, userUUID, err := s.loadLoggableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsReadOnly() {
+		return nil, archive.ErrReadOnly
+	}
+
+	var req timelogmodel.TimeLogRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	logDate := today()
+	if req.LogDate != "" {
+		logDate, err = time.Parse("2006-01-02", req.LogDate)
+		if err != nil {
+			return nil, errors.New("log_date harus berformat YYYY-MM-DD")
+		}
+		if logDate.After(today()) {
+			return nil, errors.New("log_date tidak boleh di masa depan")
+		}
+	}
+
+	log := &timelogmodel.TimeLog{
+		TaskID:          task.ID,
+		UserID:          userUUID,
+		LogDate:         logDate,
+		DurationMinutes: req.DurationMinutes,
+		Note:            strings.TrimSpace(req.Note),
+	}
+
+	if err := s.repo.CreateTimeLog(log); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTimeLogByID(log.ID)
+}
+
+// GetTaskTimeLogs hanya untuk admin, manager project dan member project
+func (s *timeLogService) GetTaskTimeLogs(ctx *gin.Context) (*timelogmodel.TaskTimeLogs, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetTaskByID(taskUUID)
+	if err != nil {
+		return nil, errors.New("task tidak ditemukan")
+	}
+
+	if ctx.GetString("user_role") != "admin" {
+		isManager, err := s.isProjectManager(task.ProjectID, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if !isManager {
+			isMember, err := s.taskRepo.IsProjectMember(task.ProjectID, userUUID)
+			if err != nil {
+				return nil, fmt.Errorf("gagal memeriksa member project: %v", err)
+			}
+			if !isMember {
+				return nil, errors.New("forbidden: hanya manager atau member project yang bisa melihat catatan waktu")
+			}
+		}
+	}
+
+	logs, err := s.repo.GetTaskTimeLogs(taskUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, log := range logs {
+		total += log.DurationMinutes
+	}
+
+	return &timelogmodel.TaskTimeLogs{
+		TaskID:       taskUUID,
+		TotalMinutes: total,
+		TimeLogs:     logs,
+	}, nil
+}
+
+// DeleteTimeLog hanya boleh dilakukan pemilik catatan atau manager project
+func (s *timeLogService) DeleteTimeLog(ctx *gin.Context) error {
+	logUUID, err := uuid.Parse(ctx.Param("time_log_id"))
+	if err != nil {
+		return errors.New("Gagal format time log ID")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	log, err := s.repo.GetTimeLogByID(logUUID)
+	if err != nil {
+		return err
+	}
+
+	task, err := s.taskRepo.GetTaskByID(log.TaskID)
+	if err != nil {
+		return err
+	}
+	if task.IsReadOnly() {
+		return archive.ErrReadOnly
+	}
+
+	if log.UserID != userUUID {
+		isManager, err := s.isProjectManager(task.ProjectID, userUUID)
+		if err != nil {
+			return err
+		}
+		if !isManager {
+			return errors.New("forbidden: hanya pemilik catatan atau manager project yang bisa menghapus")
+		}
+	}
+
+	return s.repo.DeleteTimeLog(logUUID)
+}
+
+// StartTimer memulai timer di task, user hanya boleh punya satu timer yang berjalan
+func (s *timeLogService) StartTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error) {
+	task, userUUID, err := s.loadLoggableTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsReadOnly() {
+		return nil, archive.ErrReadOnly
+	}
+
+	// body boleh kosong
+	var req timelogmodel.TimerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	running, err := s.repo.GetRunningTimer(userUUID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if running != nil {
+		if running.TaskID == task.ID {
+			return nil, errors.New("timer untuk task ini sudah berjalan")
+		}
+		return nil, errors.New("masih ada timer yang berjalan di task lain, hentikan terlebih dahulu")
+	}
+
+	now := time.Now()
+	log := &timelogmodel.TimeLog{
+		TaskID:    task.ID,
+		UserID:    userUUID,
+		LogDate:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Note:      strings.TrimSpace(req.Note),
+		StartedAt: &now,
+	}
+
+	if err := s.repo.CreateTimeLog(log); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTimeLogByID(log.ID)
+}
+
+// StopTimer menghentikan timer user di task ini, durasi dibulatkan ke atas per menit (minimal 1 menit).
+// Timer dicari dari user yang login tanpa membaca task, jadi pemilik timer tetap bisa menghentikannya walaupun
+// sudah tidak menjadi assignee, task dipindah ke project lain, diarsipkan, atau ada di trash
+func (s *timeLogService) StopTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error) {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format task ID")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req timelogmodel.TimerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	running, err := s.repo.GetRunningTimer(userUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && running.TaskID != taskUUID) {
+		return nil, errors.New("tidak ada timer yang berjalan di task ini")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	minutes := int(math.Ceil(now.Sub(*running.StartedAt).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	running.EndedAt = &now
+	running.DurationMinutes = minutes
+	running.UpdatedAt = now
+	if note := strings.TrimSpace(req.Note); note != "" {
+		running.Note = note
+	}
+
+	if err := s.repo.UpdateTimeLog(running); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTimeLogByID(running.ID)
+}
+
+// GetRunningTimer mengembalikan nil jika user tidak punya timer yang berjalan
+func (s *timeLogService) GetRunningTimer(ctx *gin.Context) (*timelogmodel.TimeLog, error) {
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.repo.GetRunningTimer(userUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return running, err
+}
@@ -0,0 +1,57 @@
+package trashmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashedProject: TaskCount adalah jumlah task yang ikut masuk trash bersama project dan akan ikut di-restore
+type TrashedProject struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	ManagerID     uuid.UUID  `json:"manager_id"`
+	TaskCount     int64      `json:"task_count"`
+	DeletedAt     time.Time  `json:"deleted_at"`
+	DeletedBy     *uuid.UUID `json:"deleted_by"`
+	DeletedByName *string    `json:"deleted_by_name"`
+	PurgeAt       time.Time  `json:"purge_at" gorm:"-"`
+}
+
+type TrashedTask struct {
+	ID            uuid.UUID  `json:"id"`
+	ProjectID     uuid.UUID  `json:"project_id"`
+	Title         string     `json:"title"`
+	Status        string     `json:"status"`
+	DeletedAt     time.Time  `json:"deleted_at"`
+	DeletedBy     *uuid.UUID `json:"deleted_by"`
+	DeletedByName *string    `json:"deleted_by_name"`
+	PurgeAt       time.Time  `json:"purge_at" gorm:"-"`
+}
+
+type TrashedComment struct {
+	ID            uuid.UUID  `json:"id"`
+	TaskID        uuid.UUID  `json:"task_id"`
+	TaskTitle     string     `json:"task_title"`
+	Content       string     `json:"content"`
+	UserID        *uuid.UUID `json:"user_id"`
+	DeletedAt     time.Time  `json:"deleted_at"`
+	DeletedBy     *uuid.UUID `json:"deleted_by"`
+	DeletedByName *string    `json:"deleted_by_name"`
+	PurgeAt       time.Time  `json:"purge_at" gorm:"-"`
+}
+
+// ProjectTrash berisi task dan komentar yang dihapus satu per satu dari project yang masih aktif
+type ProjectTrash struct {
+	ProjectID     uuid.UUID        `json:"project_id"`
+	RetentionDays int              `json:"retention_days"`
+	Tasks         []TrashedTask    `json:"tasks"`
+	Comments      []TrashedComment `json:"comments"`
+}
+
+type PurgeResult struct {
+	Projects int64 `json:"projects"`
+	Tasks    int64 `json:"tasks"`
+	Comments int64 `json:"comments"`
+	Files    int   `json:"files"`
+}
@@ -0,0 +1,202 @@
+package trashrepository
+
+import (
+	projectmodel "gintugas/modules/components/Project/model"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	trashmodel "gintugas/modules/components/Trash/model"
+	commentmodel "gintugas/modules/components/command/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TrashRepository interface {
+	// GetDeletedProjects mengambil semua project di trash, managerID nil berarti semua manager
+	GetDeletedProjects(managerID *uuid.UUID) ([]trashmodel.TrashedProject, error)
+	GetDeletedTasks(projectID uuid.UUID) ([]trashmodel.TrashedTask, error)
+	GetDeletedComments(projectID uuid.UUID) ([]trashmodel.TrashedComment, error)
+
+	GetDeletedProject(id uuid.UUID) (*projectmodel.Project, error)
+	GetDeletedTask(id uuid.UUID) (*taskmodel.Task, error)
+	GetDeletedComment(id uuid.UUID) (*commentmodel.Comments, error)
+
+	// RestoreProject juga mengembalikan task yang masuk trash bersamaan dengan project dan mengembalikan task-task tersebut
+	RestoreProject(project *projectmodel.Project) ([]taskmodel.Task, error)
+	RestoreTask(id uuid.UUID) error
+	RestoreComment(id uuid.UUID) error
+
+	// GetPurgeableFiles mengambil path file attachment milik task yang akan dihapus permanen oleh Purge
+	GetPurgeableFiles(cutoff time.Time) ([]string, error)
+	Purge(cutoff time.Time) (*trashmodel.PurgeResult, error)
+
+	// Transaction memberi repository trash dan task yang memakai transaksi yang sama
+	Transaction(fn func(repo TrashRepository, tasks taskrepository.TaskRepository) error) error
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+func (r *trashRepository) GetDeletedProjects(managerID *uuid.UUID) ([]trashmodel.TrashedProject, error) {
+	projects := []trashmodel.TrashedProject{}
+	query := r.db.Table("projects p").
+		Select(`p.id, p.nama AS name, p.manager_id, p.deleted_at, p.deleted_by, u.username AS deleted_by_name,
+			(SELECT COUNT(*) FROM tasks t WHERE t.project_id = p.id AND t.deleted_at = p.deleted_at) AS task_count`).
+		Joins("LEFT JOIN users u ON u.id = p.deleted_by").
+		Where("p.deleted_at IS NOT NULL")
+	if managerID != nil {
+		query = query.Where("p.manager_id = ?", *managerID)
+	}
+	err := query.Order("p.deleted_at DESC").Scan(&projects).Error
+	return projects, err
+}
+
+func (r *trashRepository) GetDeletedTasks(projectID uuid.UUID) ([]trashmodel.TrashedTask, error) {
+	tasks := []trashmodel.TrashedTask{}
+	err := r.db.Table("tasks t").
+		Select("t.id, t.project_id, t.title, t.status, t.deleted_at, t.deleted_by, u.username AS deleted_by_name").
+		Joins("LEFT JOIN users u ON u.id = t.deleted_by").
+		Where("t.project_id = ? AND t.deleted_at IS NOT NULL", projectID).
+		Order("t.deleted_at DESC").
+		Scan(&tasks).Error
+	return tasks, err
+}
+
+// GetDeletedComments hanya mengambil komentar dari task yang tidak ada di trash
+func (r *trashRepository) GetDeletedComments(projectID uuid.UUID) ([]trashmodel.TrashedComment, error) {
+	comments := []trashmodel.TrashedComment{}
+	err := r.db.Table("comments c").
+		Select("c.id, c.task_id, t.title AS task_title, c.content, c.user_id, c.deleted_at, c.deleted_by, u.username AS deleted_by_name").
+		Joins("JOIN tasks t ON t.id = c.task_id").
+		Joins("LEFT JOIN users u ON u.id = c.deleted_by").
+		Where("t.project_id = ? AND t.deleted_at IS NULL AND c.deleted_at IS NOT NULL", projectID).
+		Order("c.deleted_at DESC").
+		Scan(&comments).Error
+	return comments, err
+}
+
+func (r *trashRepository) GetDeletedProject(id uuid.UUID) (*projectmodel.Project, error) {
+	var project projectmodel.Project
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&project).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *trashRepository) GetDeletedTask(id uuid.UUID) (*taskmodel.Task, error) {
+	var task taskmodel.Task
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *trashRepository) GetDeletedComment(id uuid.UUID) (*commentmodel.Comments, error) {
+	var comment commentmodel.Comments
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func restoreColumns() map[string]interface{} {
+	return map[string]interface{}{"deleted_at": nil, "deleted_by": nil}
+}
+
+func (r *trashRepository) RestoreProject(project *projectmodel.Project) ([]taskmodel.Task, error) {
+	tasks := []taskmodel.Task{}
+	err := r.db.Unscoped().
+		Where("project_id = ? AND deleted_at = ?", project.ID, project.DeletedAt.Time).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) > 0 {
+		ids := make([]uuid.UUID, len(tasks))
+		for i := range tasks {
+			ids[i] = tasks[i].ID
+			tasks[i].DeletedAt = gorm.DeletedAt{}
+			tasks[i].DeletedBy = nil
+		}
+		err = r.db.Unscoped().Model(&taskmodel.Task{}).
+			Where("id IN ?", ids).
+			Updates(restoreColumns()).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.db.Unscoped().Model(&projectmodel.Project{}).
+		Where("id = ?", project.ID).
+		Updates(restoreColumns()).Error
+	return tasks, err
+}
+
+func (r *trashRepository) RestoreTask(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&taskmodel.Task{}).
+		Where("id = ?", id).
+		Updates(restoreColumns()).Error
+}
+
+func (r *trashRepository) RestoreComment(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&commentmodel.Comments{}).
+		Where("id = ?", id).
+		Updates(restoreColumns()).Error
+}
+
+func (r *trashRepository) GetPurgeableFiles(cutoff time.Time) ([]string, error) {
+	var paths []string
+	err := r.db.Table("attachments a").
+		Joins("JOIN tasks t ON t.id = a.task_id").
+		Where("t.deleted_at < ? OR t.project_id IN (SELECT id FROM projects WHERE deleted_at < ?)", cutoff, cutoff).
+		Pluck("a.file_path", &paths).Error
+	return paths, err
+}
+
+// Purge menghapus permanen data yang masuk trash sebelum cutoff. Comment, attachment, checklist dan time log
+// milik task yang dihapus ikut terhapus lewat ON DELETE CASCADE
+func (r *trashRepository) Purge(cutoff time.Time) (*trashmodel.PurgeResult, error) {
+	result := &trashmodel.PurgeResult{}
+
+	comments := r.db.Exec("DELETE FROM comments WHERE deleted_at < ?", cutoff)
+	if comments.Error != nil {
+		return nil, comments.Error
+	}
+	result.Comments = comments.RowsAffected
+
+	tasks := r.db.Exec("DELETE FROM tasks WHERE deleted_at < ?", cutoff)
+	if tasks.Error != nil {
+		return nil, tasks.Error
+	}
+	result.Tasks = tasks.RowsAffected
+
+	projects := r.db.Exec("DELETE FROM projects WHERE deleted_at < ?", cutoff)
+	if projects.Error != nil {
+		return nil, projects.Error
+	}
+	result.Projects = projects.RowsAffected
+
+	return result, nil
+}
+
+func (r *trashRepository) Transaction(fn func(repo TrashRepository, tasks taskrepository.TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&trashRepository{db: tx}, taskrepository.NewTaskRepository(tx))
+	})
+}
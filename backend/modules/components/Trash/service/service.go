@@ -0,0 +1,319 @@
+package trashservice
+
+import (
+	"errors"
+	"fmt"
+	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
+	trashmodel "gintugas/modules/components/Trash/model"
+	trashrepository "gintugas/modules/components/Trash/repository"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultRetentionDays dipakai jika TRASH_RETENTION_DAYS tidak diisi
+const DefaultRetentionDays = 30
+
+type TrashService interface {
+	GetProjectTrash(ctx *gin.Context) (*trashmodel.ProjectTrash, error)
+	GetDeletedProjects(ctx *gin.Context) ([]trashmodel.TrashedProject, error)
+	RestoreProject(ctx *gin.Context) (int64, error)
+	RestoreTask(ctx *gin.Context) error
+	RestoreComment(ctx *gin.Context) error
+
+	// PurgeExpired menghapus permanen data yang sudah melewati masa retensi beserta file attachment-nya
+	PurgeExpired() (*trashmodel.PurgeResult, error)
+	StartPurger(interval time.Duration)
+}
+
+type trashService struct {
+	repo          trashrepository.TrashRepository
+	taskRepo      taskrepository.TaskRepository
+	events        taskmodel.TaskEventPublisher
+	retentionDays int
+}
+
+func NewTrashService(repo trashrepository.TrashRepository, taskRepo taskrepository.TaskRepository, events taskmodel.TaskEventPublisher, retentionDays int) TrashService {
+	return &trashService{
+		repo:          repo,
+		taskRepo:      taskRepo,
+		events:        events,
+		retentionDays: retentionDays,
+	}
+}
+
+// RetentionDaysFromEnv membaca TRASH_RETENTION_DAYS, nilai kosong atau tidak valid memakai DefaultRetentionDays
+func RetentionDaysFromEnv() int {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return DefaultRetentionDays
+	}
+	return days
+}
+
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		return uuid.Nil, errors.New("unauthorized: user tidak terautentikasi")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprint(userID))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id format: " + err.Error())
+	}
+	return userUUID, nil
+}
+
+// validateManager: admin boleh mengelola trash semua project, selain itu hanya manager project-nya
+func validateManager(ctx *gin.Context, managerID uuid.UUID) error {
+	if ctx.GetString("user_role") == "admin" {
+		return nil
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	if managerID != userUUID {
+		return errors.New("forbidden: hanya manager project yang bisa melakukan operasi ini")
+	}
+	return nil
+}
+
+func (s *trashService) purgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.AddDate(0, 0, s.retentionDays)
+}
+
+func (s *trashService) GetProjectTrash(ctx *gin.Context) (*trashmodel.ProjectTrash, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return nil, errors.New("Gagal format Project ID")
+	}
+
+	project, err := s.taskRepo.GetProjectByID(projectUUID)
+	if err != nil {
+		return nil, errors.New("project tidak ditemukan")
+	}
+	if err := validateManager(ctx, project.ManagerID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.GetDeletedTasks(projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].PurgeAt = s.purgeAt(tasks[i].DeletedAt)
+	}
+
+	comments, err := s.repo.GetDeletedComments(projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		comments[i].PurgeAt = s.purgeAt(comments[i].DeletedAt)
+	}
+
+	return &trashmodel.ProjectTrash{
+		ProjectID:     projectUUID,
+		RetentionDays: s.retentionDays,
+		Tasks:         tasks,
+		Comments:      comments,
+	}, nil
+}
+
+// GetDeletedProjects: admin melihat semua project di trash, manager hanya project miliknya
+func (s *trashService) GetDeletedProjects(ctx *gin.Context) ([]trashmodel.TrashedProject, error) {
+	var managerID *uuid.UUID
+	if ctx.GetString("user_role") != "admin" {
+		userUUID, err := currentUserID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		managerID = &userUUID
+	}
+
+	projects, err := s.repo.GetDeletedProjects(managerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		projects[i].PurgeAt = s.purgeAt(projects[i].DeletedAt)
+	}
+	return projects, nil
+}
+
+// RestoreProject mengembalikan project dan task yang ikut terhapus bersamanya, mengembalikan jumlah task yang di-restore
+func (s *trashService) RestoreProject(ctx *gin.Context) (int64, error) {
+	projectUUID, err := uuid.Parse(ctx.Param("project_id"))
+	if err != nil {
+		return 0, errors.New("Gagal format Project ID")
+	}
+
+	project, err := s.repo.GetDeletedProject(projectUUID)
+	if err != nil {
+		return 0, errors.New("project tidak ada di trash")
+	}
+	if err := validateManager(ctx, project.ManagerID); err != nil {
+		return 0, err
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var restored []taskmodel.Task
+	err = s.repo.Transaction(func(repo trashrepository.TrashRepository, _ taskrepository.TaskRepository) error {
+		restored, err = repo.RestoreProject(project)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range restored {
+		s.publishRestored(&restored[i], userUUID)
+	}
+	return int64(len(restored)), nil
+}
+
+func (s *trashService) RestoreTask(ctx *gin.Context) error {
+	taskUUID, err := uuid.Parse(ctx.Param("task_id"))
+	if err != nil {
+		return errors.New("Gagal format task ID")
+	}
+
+	task, err := s.repo.GetDeletedTask(taskUUID)
+	if err != nil {
+		return errors.New("task tidak ada di trash")
+	}
+
+	project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+	if err != nil {
+		return errors.New("project task ini ada di trash, restore project-nya terlebih dulu")
+	}
+	if err := validateManager(ctx, project.ManagerID); err != nil {
+		return err
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Transaction(func(repo trashrepository.TrashRepository, tasks taskrepository.TaskRepository) error {
+		if err := repo.RestoreTask(task.ID); err != nil {
+			return err
+		}
+		return tasks.CreateTaskHistory(&taskmodel.TaskHistory{
+			TaskID:    task.ID,
+			ProjectID: task.ProjectID,
+			TaskTitle: task.Title,
+			ActorID:   &userUUID,
+			Action:    taskmodel.HistoryRestored,
+			Changes:   []taskmodel.FieldChange{},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	task.DeletedAt = gorm.DeletedAt{}
+	task.DeletedBy = nil
+	s.publishRestored(task, userUUID)
+	return nil
+}
+
+// publishRestored dipanggil setelah commit supaya subscriber melihat task yang sudah kembali dari trash
+func (s *trashService) publishRestored(task *taskmodel.Task, actorID uuid.UUID) {
+	s.events.PublishTaskEvent(taskmodel.TaskEvent{
+		Action:  taskmodel.HistoryRestored,
+		Task:    *task,
+		Changes: []taskmodel.FieldChange{},
+		ActorID: &actorID,
+	})
+}
+
+// RestoreComment bisa dilakukan penulis komentar, manager project atau admin
+func (s *trashService) RestoreComment(ctx *gin.Context) error {
+	commentUUID, err := uuid.Parse(ctx.Param("comment_id"))
+	if err != nil {
+		return errors.New("Gagal format comment ID")
+	}
+
+	comment, err := s.repo.GetDeletedComment(commentUUID)
+	if err != nil {
+		return errors.New("komentar tidak ada di trash")
+	}
+
+	task, err := s.taskRepo.GetTaskByID(comment.TaskID)
+	if err != nil {
+		return errors.New("task komentar ini ada di trash, restore task-nya terlebih dulu")
+	}
+
+	userUUID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	if comment.UserID == nil || *comment.UserID != userUUID {
+		project, err := s.taskRepo.GetProjectByID(task.ProjectID)
+		if err != nil {
+			return fmt.Errorf("gagal mengambil detail project: %v", err)
+		}
+		if err := validateManager(ctx, project.ManagerID); err != nil {
+			return errors.New("forbidden: hanya penulis komentar atau manager project yang bisa me-restore komentar")
+		}
+	}
+
+	return s.repo.RestoreComment(comment.ID)
+}
+
+func (s *trashService) PurgeExpired() (*trashmodel.PurgeResult, error) {
+	cutoff := time.Now().AddDate(0, 0, -s.retentionDays)
+
+	var (
+		result *trashmodel.PurgeResult
+		files  []string
+	)
+	err := s.repo.Transaction(func(repo trashrepository.TrashRepository, _ taskrepository.TaskRepository) error {
+		var err error
+		if files, err = repo.GetPurgeableFiles(cutoff); err != nil {
+			return err
+		}
+		result, err = repo.Purge(cutoff)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// file dihapus setelah commit supaya tidak hilang jika transaksi gagal
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Warning: gagal menghapus file fisik: %v\n", err)
+			continue
+		}
+		result.Files++
+	}
+	return result, nil
+}
+
+func (s *trashService) StartPurger(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.PurgeExpired(); err != nil {
+				fmt.Printf("Gagal mengosongkan trash: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
 	attachmentmodel "gintugas/modules/components/attachments/models"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ type AttachmentRepository interface {
 	GetAttachmentByID(attachmentID uuid.UUID) (*attachmentmodel.Attachment, error)
 	DeleteAttachment(attachmentID uuid.UUID) error
 	GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error)
+	IsTaskAssignee(taskID uuid.UUID, userID uuid.UUID) (bool, error)
 }
 
 type attachmentRepository struct {
@@ -57,7 +59,8 @@ func (r *attachmentRepository) DeleteAttachment(attachmentID uuid.UUID) error {
 
 func (r *attachmentRepository) GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error) {
 	var task taskmodel.Task
-	err := r.db.Where("id = ?", taskID).
+	err := r.db.Select("tasks.*, "+taskrepository.ProjectArchivedColumn).
+		Where("id = ?", taskID).
 		Preload("Assignee").
 		First(&task).Error
 	if err != nil {
@@ -65,3 +68,11 @@ func (r *attachmentRepository) GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, e
 	}
 	return &task, nil
 }
+
+func (r *attachmentRepository) IsTaskAssignee(taskID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&taskmodel.TaskAssignee{}).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
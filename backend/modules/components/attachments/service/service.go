@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	archive "gintugas/modules/components/Archive"
 	taskmodel "gintugas/modules/components/Tasks/model"
 	attachmentmodel "gintugas/modules/components/attachments/models"
 	attachmentrepository "gintugas/modules/components/attachments/repository"
@@ -33,6 +34,7 @@ type AttachmentRepository interface {
 	GetAttachmentByID(attachmentID uuid.UUID) (*attachmentmodel.Attachment, error)
 	DeleteAttachment(attachmentID uuid.UUID) error
 	GetTaskByID(taskID uuid.UUID) (*taskmodel.Task, error)
+	IsTaskAssignee(taskID uuid.UUID, userID uuid.UUID) (bool, error)
 }
 
 func NewAttachmentService(attachmentRepo attachmentrepository.AttachmentRepository, uploadPath string) AttachmentService {
@@ -68,14 +70,17 @@ func (s *attachmentService) validateTaskAssignee(ctx *gin.Context, taskID uuid.U
 	if err != nil {
 		return fmt.Errorf("task tidak ditemukan: %v", err)
 	}
+	if task.IsReadOnly() {
+		return archive.ErrReadOnly
+	}
 
-	//cek task ada petugas
-	if task.AssigneeID == nil {
-		return errors.New("task ini belum memiliki assignee")
+	//cek user termasuk assignee task (utama maupun tambahan)
+	isAssignee, err := s.attachmentRepo.IsTaskAssignee(taskID, userUUID)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa assignee task: %v", err)
 	}
 
-	//cek lagi apakah petugas task
-	if *task.AssigneeID != userUUID {
+	if !isAssignee {
 		return errors.New("forbidden: hanya assignee task yang bisa upload attachment")
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Comments struct {
@@ -13,13 +14,23 @@ type Comments struct {
 	TaskID    uuid.UUID  `json:"task_id" gorm:"type:uuid;not null"`
 	UserID    *uuid.UUID `json:"user_id" gorm:"type:uuid"`
 	Content   string     `json:"content" gorm:"type:text;not null"`
+	Version   int        `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 
+	DeletedAt gorm.DeletedAt `json:"-"`
+	DeletedBy *uuid.UUID     `json:"-" gorm:"type:uuid"`
+
 	Tasks taskmodel.Task   `json:"tasks,omitempty" gorm:"foreignKey:TaskID"`
 	Users *usermodels.User `json:"users,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// CommentEvent dikirim ke subscriber CommentsService setiap ada komentar baru
+type CommentEvent struct {
+	Comment Comments
+	ActorID *uuid.UUID
+}
+
 type CommentsRequest struct {
 	Content string `json:"content" binding:"required"`
 }
@@ -29,6 +40,7 @@ type CommentsResponse struct {
 	TaskID    uuid.UUID        `json:"task_id"`
 	Content   string           `json:"content"`
 	UserID    *uuid.UUID       `json:"user_id"`
+	Version   int              `json:"version"`
 	CreatedAt time.Time        `json:"created_at"`
 	UpdatedAt time.Time        `json:"updated_at"`
 	Users     *usermodels.User `json:"users,omitempty"`
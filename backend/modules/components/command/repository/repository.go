@@ -2,11 +2,15 @@ package repository
 
 import (
 	usermodels "gintugas/modules/components/Auth/model"
+	concurrency "gintugas/modules/components/Concurrency"
 	taskmodel "gintugas/modules/components/Tasks/model"
+	taskrepository "gintugas/modules/components/Tasks/repository"
 	"gintugas/modules/components/command/model"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CommentsRepository interface {
@@ -14,7 +18,8 @@ type CommentsRepository interface {
 	GetCommentsByTaskID(taskID uuid.UUID) ([]model.Comments, error)
 	GetCommentsByID(commentsID uuid.UUID) (*model.Comments, error)
 	UpdateComments(comments *model.Comments) error
-	DeleteComments(commentsID uuid.UUID) error
+	// DeleteComments memindahkan komentar ke trash
+	DeleteComments(commentsID uuid.UUID, deletedBy uuid.UUID) error
 
 	GetCommentByUserID(userID uuid.UUID) ([]model.Comments, error)
 	GetUserByID(userID uuid.UUID) (*usermodels.User, error)
@@ -54,12 +59,31 @@ func (r *commentsRepository) GetCommentsByID(commentsID uuid.UUID) (*model.Comme
 	return &coment, nil
 }
 
+// UpdateComments menyimpan komentar hanya jika versi di database masih sama, lalu menaikkan versinya
 func (r *commentsRepository) UpdateComments(comments *model.Comments) error {
-	return r.db.Save(comments).Error
+	currentVersion := comments.Version
+	comments.Version = currentVersion + 1
+
+	result := r.db.Model(comments).
+		Where("version = ?", currentVersion).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(comments)
+	if result.Error != nil {
+		comments.Version = currentVersion
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		comments.Version = currentVersion
+		return concurrency.ErrPreconditionFailed
+	}
+	return nil
 }
 
-func (r *commentsRepository) DeleteComments(commentsID uuid.UUID) error {
-	return r.db.Delete(&model.Comments{}, "id = ?", commentsID).Error
+func (r *commentsRepository) DeleteComments(commentsID uuid.UUID, deletedBy uuid.UUID) error {
+	return r.db.Model(&model.Comments{}).
+		Where("id = ?", commentsID).
+		Updates(map[string]interface{}{"deleted_at": time.Now(), "deleted_by": deletedBy}).Error
 }
 
 func (r *commentsRepository) GetCommentByUserID(userID uuid.UUID) ([]model.Comments, error) {
@@ -82,7 +106,7 @@ func (r *commentsRepository) GetUserByID(userID uuid.UUID) (*usermodels.User, er
 
 func (r *commentsRepository) GetTaskByID(TaskID uuid.UUID) (*taskmodel.Task, error) {
 	var tasks taskmodel.Task
-	err := r.db.First(&tasks, "id = ?", TaskID).Error
+	err := r.db.Select("tasks.*, "+taskrepository.ProjectArchivedColumn).First(&tasks, "id = ?", TaskID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	archive "gintugas/modules/components/Archive"
+	concurrency "gintugas/modules/components/Concurrency"
 	"gintugas/modules/components/command/model"
 	"gintugas/modules/components/command/repository"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,10 +20,18 @@ type CommentsService interface {
 	GetCommentsByID(ctx *gin.Context) (*model.CommentsResponse, error)
 	UpdateComments(ctx *gin.Context) (*model.CommentsResponse, error)
 	DeleteComments(ctx *gin.Context) error
+
+	Subscribe(handler CommentEventHandler)
 }
 
+// CommentEventHandler dipanggil di goroutine terpisah, jadi tidak memperlambat request
+type CommentEventHandler func(event model.CommentEvent)
+
 type commentsService struct {
 	commentsRepo repository.CommentsRepository
+
+	mu       sync.RWMutex
+	handlers []CommentEventHandler
 }
 
 func NewTaskService(commentsRepo repository.CommentsRepository) CommentsService {
@@ -28,6 +40,42 @@ func NewTaskService(commentsRepo repository.CommentsRepository) CommentsService
 	}
 }
 
+// Subscribe mendaftarkan handler yang menerima setiap komentar baru
+func (s *commentsService) Subscribe(handler CommentEventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+func (s *commentsService) publish(event model.CommentEvent) {
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler CommentEventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Printf("comment event handler panic: %v\n", r)
+				}
+			}()
+			handler(event)
+		}(handler)
+	}
+}
+
+// ensureTaskWritable: komentar di task yang diarsipkan (atau project-nya diarsipkan) tidak bisa ditambah, diubah maupun dihapus
+func (s *commentsService) ensureTaskWritable(taskID uuid.UUID) error {
+	task, err := s.commentsRepo.GetTaskByID(taskID)
+	if err != nil {
+		return errors.New("task tidak ditemukan")
+	}
+	if task.IsReadOnly() {
+		return archive.ErrReadOnly
+	}
+	return nil
+}
+
 func (s *commentsService) CreateComments(ctx *gin.Context) (*model.CommentsResponse, error) {
 	taskID := ctx.Param("task_id")
 	taskUUID, err := uuid.Parse(taskID)
@@ -50,6 +98,10 @@ func (s *commentsService) CreateComments(ctx *gin.Context) (*model.CommentsRespo
 		return &model.CommentsResponse{}, errors.New("invalid user id format: " + err.Error())
 	}
 
+	if err := s.ensureTaskWritable(taskUUID); err != nil {
+		return nil, err
+	}
+
 	var commentsreq model.CommentsRequest
 	if err := ctx.ShouldBindJSON(&commentsreq); err != nil {
 		return nil, err
@@ -65,6 +117,7 @@ func (s *commentsService) CreateComments(ctx *gin.Context) (*model.CommentsRespo
 		return nil, err
 	}
 
+	s.publish(model.CommentEvent{Comment: *comments, ActorID: &userUUID})
 	return s.convertToResponse(comments), nil
 
 }
@@ -137,6 +190,14 @@ func (s *commentsService) UpdateComments(ctx *gin.Context) (*model.CommentsRespo
 		return nil, errors.New("Forbidden: Anda hanya dapat memperbarui komentar Anda sendiri")
 	}
 
+	if err := concurrency.CheckIfMatch(ctx, existingKomen.Version); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTaskWritable(existingKomen.TaskID); err != nil {
+		return nil, err
+	}
+
 	var commentsreq model.CommentsRequest
 	if err := ctx.ShouldBindJSON(&commentsreq); err != nil {
 		return nil, err
@@ -186,7 +247,11 @@ func (s *commentsService) DeleteComments(ctx *gin.Context) error {
 		return errors.New("Forbidden: Anda hanya dapat memperbarui komentar Anda sendiri")
 	}
 
-	return s.commentsRepo.DeleteComments(commentsUUID)
+	if err := s.ensureTaskWritable(existingKomen.TaskID); err != nil {
+		return err
+	}
+
+	return s.commentsRepo.DeleteComments(commentsUUID, userUUID)
 }
 
 func (s *commentsService) convertToResponse(comments *model.Comments) *model.CommentsResponse {
@@ -195,6 +260,7 @@ func (s *commentsService) convertToResponse(comments *model.Comments) *model.Com
 		TaskID:    comments.TaskID,
 		UserID:    comments.UserID,
 		Content:   comments.Content,
+		Version:   comments.Version,
 		CreatedAt: comments.CreatedAt,
 		UpdatedAt: comments.UpdatedAt,
 		Users:     comments.Users,
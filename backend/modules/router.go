@@ -7,11 +7,31 @@ import (
 	controllers "gintugas/modules/components/Auth/controllers"
 	middleware "gintugas/modules/components/Auth/middleware"
 	role "gintugas/modules/components/Auth/middleware/middlewarerole"
+	automationrepository "gintugas/modules/components/Automation/repository"
+	automationservice "gintugas/modules/components/Automation/service"
+	calendarrepository "gintugas/modules/components/Calendar/repository"
+	calendarservice "gintugas/modules/components/Calendar/service"
+	importrepository "gintugas/modules/components/Import/repository"
+	importservice "gintugas/modules/components/Import/service"
 	services "gintugas/modules/components/Mail/service"
+	planningrepository "gintugas/modules/components/Planning/repository"
+	planningservice "gintugas/modules/components/Planning/service"
 	repositoryprojek "gintugas/modules/components/Project/repository"
 	servissprj "gintugas/modules/components/Project/service"
+	recurrencerepository "gintugas/modules/components/Recurrence/repository"
+	recurrenceservice "gintugas/modules/components/Recurrence/service"
+	reminderrepository "gintugas/modules/components/Reminder/repository"
+	reminderservice "gintugas/modules/components/Reminder/service"
+	searchrepository "gintugas/modules/components/Search/repository"
+	searchservice "gintugas/modules/components/Search/service"
 	taskrepository "gintugas/modules/components/Tasks/repository"
 	taskservice "gintugas/modules/components/Tasks/service"
+	templaterepository "gintugas/modules/components/Templates/repository"
+	templateservice "gintugas/modules/components/Templates/service"
+	timelogrepository "gintugas/modules/components/TimeTracking/repository"
+	timelogservice "gintugas/modules/components/TimeTracking/service"
+	trashrepository "gintugas/modules/components/Trash/repository"
+	trashservice "gintugas/modules/components/Trash/service"
 	attachmentrepository "gintugas/modules/components/attachments/repository"
 	attachmentservice "gintugas/modules/components/attachments/service"
 	. "gintugas/modules/components/command/repository"
@@ -28,9 +48,9 @@ import (
 func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Atau specific: []string{"http://localhost:3000", "http://127.0.0.1:*"}
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -60,6 +80,53 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 	// Dashboard Handler
 	dashboardHandler := serviceroute.NewDashboardHandler(gormDB)
 
+	recurrenceRepo := recurrencerepository.NewRecurrenceRepository(gormDB)
+	recurrenceService := recurrenceservice.NewRecurrenceService(recurrenceRepo, taskRepo, mailService, taskService)
+	recurrenceHandler := serviceroute.NewRecurrenceHandler(recurrenceService)
+	taskService.Subscribe(recurrenceService.HandleTaskEvent)
+	recurrenceService.StartScheduler(time.Minute)
+
+	planningRepo := planningrepository.NewPlanningRepository(gormDB)
+	planningService := planningservice.NewPlanningService(planningRepo, taskRepo, planningservice.MaxParallelTasksFromEnv())
+	planningHandler := serviceroute.NewPlanningHandler(planningService)
+
+	importRepo := importrepository.NewImportRepository(gormDB)
+	importService := importservice.NewImportService(importRepo, taskRepo, uploadPath, taskService)
+	importHandler := serviceroute.NewImportHandler(importService)
+
+	templateRepo := templaterepository.NewTemplateRepository(gormDB)
+	templateService := templateservice.NewTemplateService(templateRepo, taskRepo, taskService)
+	templateHandler := serviceroute.NewTemplateHandler(templateService)
+
+	timeLogRepo := timelogrepository.NewTimeLogRepository(gormDB)
+	timeLogService := timelogservice.NewTimeLogService(timeLogRepo, taskRepo)
+	timeLogHandler := serviceroute.NewTimeLogHandler(timeLogService)
+
+	searchRepo := searchrepository.NewSearchRepository(gormDB)
+	searchService := searchservice.NewSearchService(searchRepo)
+	searchHandler := serviceroute.NewSearchHandler(searchService)
+
+	trashRepo := trashrepository.NewTrashRepository(gormDB)
+	trashService := trashservice.NewTrashService(trashRepo, taskRepo, taskService, trashservice.RetentionDaysFromEnv())
+	trashHandler := serviceroute.NewTrashHandler(trashService)
+	trashService.StartPurger(time.Hour)
+
+	reminderRepo := reminderrepository.NewReminderRepository(gormDB)
+	reminderService := reminderservice.NewReminderService(reminderRepo, mailService, reminderservice.ConfigFromEnv())
+	reminderService.StartScheduler(15 * time.Minute)
+
+	automationRepo := automationrepository.NewAutomationRepository(gormDB)
+	automationService := automationservice.NewAutomationService(automationRepo, taskRepo, taskService, mailService)
+	automationHandler := serviceroute.NewAutomationHandler(automationService)
+	taskService.Subscribe(automationService.HandleTaskEvent)
+	commentsService.Subscribe(automationService.HandleCommentEvent)
+	automationService.StartWorkers(2)
+	automationService.StartScheduler(15 * time.Minute)
+
+	calendarRepo := calendarrepository.NewCalendarRepository(gormDB)
+	calendarService := calendarservice.NewCalendarService(calendarRepo, taskRepo)
+	calendarHandler := serviceroute.NewCalendarHandler(calendarService)
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	api := router.Group("/api")
@@ -75,6 +142,9 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 
 		api.GET("/users", serviceroute.GetAllUsersRouter(db))
 
+		// feed kalender dibaca aplikasi kalender tanpa login, aksesnya lewat token di url
+		api.GET("/calendar/:token", calendarHandler.GetFeed)
+
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware())
 		{
@@ -83,6 +153,7 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 			{
 				admin.GET("/users/:id", serviceroute.GetUsersRouter(db))
 				admin.PUT("/users/:id", serviceroute.UpdateUsersRouter(db))
+				admin.PATCH("/users/:id", serviceroute.PatchUsersRouter(db))
 				admin.DELETE("/users/:id", serviceroute.DeleteUsersRouter(db))
 
 				// Admin Dashboard Routes
@@ -103,7 +174,10 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 				manager.GET("/project", serviceroute.GetAllProjektRouter(db))
 				manager.GET("/project/:id", serviceroute.GetProjectRouter(db))
 				manager.PUT("/project/:id", serviceroute.UpdateProjectRouter(db))
+				manager.PATCH("/project/:id", serviceroute.PatchProjectRouter(db))
 				manager.DELETE("/project/:id", serviceroute.DeleteProjectRouter(db))
+				manager.POST("/project/:id/archive", serviceroute.ArchiveProjectRouter(db))
+				manager.POST("/project/:id/unarchive", serviceroute.UnarchiveProjectRouter(db))
 
 				member := manager.Group("/projects/:project_id/members")
 				{
@@ -116,9 +190,77 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 				tasks := manager.Group("/projects/:project_id/tasks")
 				{
 					tasks.POST("", taskController.CreateTask)
+					tasks.POST("/bulk", taskController.BulkUpdateTasks)
 					tasks.GET("", taskController.GetProjectTasks)
-					tasks.PUT("/:task_id", taskController.UpdateTask)
+					tasks.GET("/export", taskController.ExportProjectTasks)
+					tasks.POST("/:task_id/move", taskController.MoveTask)
+					tasks.POST("/:task_id/move-to-project", taskController.MoveTaskToProject)
+					tasks.POST("/:task_id/clone", taskController.CloneTask)
+					tasks.POST("/:task_id/archive", taskController.ArchiveTask)
+					tasks.POST("/:task_id/unarchive", taskController.UnarchiveTask)
 					tasks.DELETE("/:task_id", taskController.DeleteTask)
+					tasks.POST("/:task_id/assignees", taskController.AddTaskAssignee)
+					tasks.DELETE("/:task_id/assignees/:user_id", taskController.RemoveTaskAssignee)
+					tasks.PUT("/:task_id/assignees/:user_id/primary", taskController.SetPrimaryAssignee)
+					tasks.POST("/:task_id/recurrence", recurrenceHandler.CreateRecurrence)
+					tasks.POST("/:task_id/review/approve", taskController.ApproveTaskReview)
+					tasks.POST("/:task_id/review/reject", taskController.RejectTaskReview)
+				}
+
+				recurrences := manager.Group("/recurrences")
+				{
+					recurrences.GET("/:recurrence_id", recurrenceHandler.GetRecurrence)
+					recurrences.PATCH("/:recurrence_id", recurrenceHandler.UpdateRecurrence)
+					recurrences.DELETE("/:recurrence_id", recurrenceHandler.DeleteRecurrence)
+				}
+
+				// Sprint & Milestone Routes
+				manager.POST("/projects/:project_id/sprints", planningHandler.CreateSprint)
+				manager.PATCH("/sprints/:sprint_id", planningHandler.UpdateSprint)
+				manager.DELETE("/sprints/:sprint_id", planningHandler.DeleteSprint)
+				manager.POST("/sprints/:sprint_id/start", planningHandler.StartSprint)
+				manager.POST("/sprints/:sprint_id/close", planningHandler.CloseSprint)
+				manager.POST("/projects/:project_id/milestones", planningHandler.CreateMilestone)
+				manager.PATCH("/milestones/:milestone_id", planningHandler.UpdateMilestone)
+				manager.DELETE("/milestones/:milestone_id", planningHandler.DeleteMilestone)
+
+				// Template Routes
+				templates := manager.Group("/templates")
+				{
+					templates.POST("", templateHandler.CreateTemplate)
+					templates.GET("", templateHandler.GetTemplates)
+					templates.GET("/:template_id", templateHandler.GetTemplate)
+					templates.PUT("/:template_id", templateHandler.UpdateTemplate)
+					templates.DELETE("/:template_id", templateHandler.DeleteTemplate)
+					templates.POST("/:template_id/instantiate", templateHandler.InstantiateTemplate)
+				}
+				manager.POST("/projects/:project_id/template", templateHandler.SaveProjectAsTemplate)
+
+				// Automation Routes
+				automations := manager.Group("/projects/:project_id/automations")
+				{
+					automations.GET("", automationHandler.GetAutomationRules)
+					automations.POST("", automationHandler.CreateAutomationRule)
+					automations.PUT("/:rule_id", automationHandler.UpdateAutomationRule)
+					automations.DELETE("/:rule_id", automationHandler.DeleteAutomationRule)
+					automations.GET("/:rule_id/executions", automationHandler.GetAutomationExecutions)
+				}
+
+				// Calendar Routes
+				manager.POST("/projects/:project_id/calendar/feed", calendarHandler.CreateProjectFeed)
+				manager.DELETE("/projects/:project_id/calendar/feed", calendarHandler.RevokeProjectFeed)
+
+				// Import Routes
+				manager.POST("/projects/:project_id/import/preview", importHandler.PreviewImport)
+				manager.POST("/projects/:project_id/import", importHandler.ImportTasks)
+
+				// Trash Routes
+				manager.GET("/projects/:project_id/trash", trashHandler.GetProjectTrash)
+				trash := manager.Group("/trash")
+				{
+					trash.GET("/projects", trashHandler.GetDeletedProjects)
+					trash.POST("/projects/:project_id/restore", trashHandler.RestoreProject)
+					trash.POST("/tasks/:task_id/restore", trashHandler.RestoreTask)
 				}
 
 				// Manager Dashboard Routes
@@ -127,6 +269,8 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 					managerDashboard.GET("", dashboardHandler.GetManagerDashboard)
 					managerDashboard.GET("/projects", dashboardHandler.GetManagerProjects)
 					managerDashboard.GET("/tasks", dashboardHandler.GetManagerTasks)
+					managerDashboard.GET("/time/projects", dashboardHandler.GetManagerTimeByProject)
+					managerDashboard.GET("/time/users", dashboardHandler.GetManagerTimeByUser)
 				}
 			}
 
@@ -151,7 +295,57 @@ func Initiator(router *gin.Engine, db *sql.DB, gormDB *gorm.DB) {
 					attachments.DELETE("/attachments/:attachment_id", attachmentHandler.DeleteAttachment)
 				}
 				staff.GET("/projects/:project_id/tasks/:task_id", taskController.GetTaskByID)
+				// manager project bebas mengubah task, assignee hanya field yang diizinkan (lihat taskFieldRoles)
+				staff.PUT("/projects/:project_id/tasks/:task_id", taskController.UpdateTask)
+				staff.PATCH("/projects/:project_id/tasks/:task_id", taskController.PatchTask)
+				staff.GET("/tasks/:task_id/history", taskController.GetTaskHistory)
+				staff.GET("/tasks/:task_id/assignees", taskController.GetTaskAssignees)
+				staff.GET("/tasks/:task_id/watchers", taskController.GetTaskWatchers)
+				staff.POST("/tasks/:task_id/watchers", taskController.WatchTask)
+				staff.DELETE("/tasks/:task_id/watchers/:user_id", taskController.UnwatchTask)
+				staff.POST("/tasks/:task_id/status", taskController.SetTaskStatus)
+				staff.POST("/tasks/:task_id/review", taskController.SubmitTaskReview)
+				staff.GET("/tasks/:task_id/reviews", taskController.GetTaskReviews)
 				staff.GET("/my-tasks", taskController.GetMyTasks)
+				staff.GET("/my-tasks/export", taskController.ExportMyTasks)
+				staff.GET("/views", taskController.GetSavedViews)
+				staff.POST("/views", taskController.CreateSavedView)
+				staff.PUT("/views/:view_id", taskController.UpdateSavedView)
+				staff.DELETE("/views/:view_id", taskController.DeleteSavedView)
+				staff.GET("/views/:view_id/tasks", taskController.GetSavedViewTasks)
+				staff.POST("/views/:view_id/pin", taskController.PinSavedView)
+				staff.DELETE("/views/:view_id/pin", taskController.UnpinSavedView)
+				staff.POST("/calendar/feed", calendarHandler.CreateUserFeed)
+				staff.DELETE("/calendar/feed", calendarHandler.RevokeUserFeed)
+
+				checklist := staff.Group("/tasks/:task_id/checklist")
+				{
+					checklist.GET("", taskController.GetChecklist)
+					checklist.POST("", taskController.AddChecklistItem)
+					checklist.PATCH("/:item_id", taskController.UpdateChecklistItem)
+					checklist.DELETE("/:item_id", taskController.DeleteChecklistItem)
+					checklist.POST("/:item_id/check", taskController.CheckChecklistItem)
+					checklist.POST("/:item_id/uncheck", taskController.UncheckChecklistItem)
+					checklist.POST("/:item_id/move", taskController.MoveChecklistItem)
+				}
+				staff.GET("/search", searchHandler.Search)
+				staff.POST("/trash/comments/:comment_id/restore", trashHandler.RestoreComment)
+
+				staff.GET("/projects/:project_id/sprints", planningHandler.GetProjectSprints)
+				staff.GET("/sprints/:sprint_id", planningHandler.GetSprint)
+				staff.GET("/projects/:project_id/milestones", planningHandler.GetProjectMilestones)
+				staff.GET("/milestones/:milestone_id", planningHandler.GetMilestone)
+				staff.GET("/projects/:project_id/timeline", planningHandler.GetProjectTimeline)
+
+				timeLogs := staff.Group("")
+				{
+					timeLogs.POST("/tasks/:task_id/time-logs", timeLogHandler.LogTime)
+					timeLogs.GET("/tasks/:task_id/time-logs", timeLogHandler.GetTaskTimeLogs)
+					timeLogs.POST("/tasks/:task_id/timer/start", timeLogHandler.StartTimer)
+					timeLogs.POST("/tasks/:task_id/timer/stop", timeLogHandler.StopTimer)
+					timeLogs.GET("/time-logs/running", timeLogHandler.GetRunningTimer)
+					timeLogs.DELETE("/time-logs/:time_log_id", timeLogHandler.DeleteTimeLog)
+				}
 
 				// Staff Dashboard Routes
 				staffDashboard := staff.Group("/dashboard/staff")